@@ -0,0 +1,18 @@
+package appconfig
+
+import (
+	"os"
+	"strconv"
+)
+
+// defaultBodyLimit cukup untuk beberapa foto bagian struk panjang dalam satu request
+const defaultBodyLimit = 32 * 1024 * 1024
+
+// BodyLimit membaca batas ukuran body request dari UPLOAD_MAX_REQUEST_BYTES
+func BodyLimit() int {
+	limit, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_REQUEST_BYTES"))
+	if err != nil || limit <= 0 {
+		return defaultBodyLimit
+	}
+	return limit
+}
@@ -1,7 +1,15 @@
 package controllers
 
-import splitbillcontollers "github.com/arifin2018/splitbill-arifin.git/controllers/SplitbillContollers"
+import (
+	metricscontrollers "github.com/arifin2018/splitbill-arifin.git/controllers/MetricsControllers"
+	reviewcontrollers "github.com/arifin2018/splitbill-arifin.git/controllers/ReviewControllers"
+	splitbillcontollers "github.com/arifin2018/splitbill-arifin.git/controllers/SplitbillContollers"
+	webhookcontrollers "github.com/arifin2018/splitbill-arifin.git/controllers/WebhookControllers"
+)
 
 type AllControllers struct {
 	SplitbilController *splitbillcontollers.SplitbillControllerImpl
+	ReviewController   *reviewcontrollers.ReviewControllerImpl
+	WebhookController  *webhookcontrollers.WebhookControllerImpl
+	MetricsController  *metricscontrollers.MetricsControllerImpl
 }
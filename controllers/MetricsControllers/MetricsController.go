@@ -0,0 +1,20 @@
+package metricscontrollers
+
+import (
+	metricsservices "github.com/arifin2018/splitbill-arifin.git/services/MetricsServices"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MetricsController interface {
+	Metrics(app *fiber.Ctx) error
+}
+
+type MetricsControllerImpl struct {
+	MetricsService metricsservices.MetricsService
+}
+
+func NewMetricsController(metricsService metricsservices.MetricsService) *MetricsControllerImpl {
+	return &MetricsControllerImpl{
+		MetricsService: metricsService,
+	}
+}
@@ -0,0 +1,17 @@
+package metricscontrollers
+
+import (
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics returns runtime metrics of the extraction limiter
+// @Summary Get extraction metrics
+// @Description Get the state of the global limiter on in-flight model calls: slots in use, queue depth (request waiters and background async/batch jobs), acquired, rejected (429), timed out (503) and canceled waits, and wait times (count, total, average, maximum and a histogram in milliseconds), plus per-key usage counters of the Gemini API key pool (requests, successes, failures, quota errors and whether the key is benched)
+// @Tags Metrics
+// @Produce json
+// @Success 200 {object} models.Metrics "Extraction metrics"
+// @Router /metrics [get]
+func (metricsControllerImpl *MetricsControllerImpl) Metrics(app *fiber.Ctx) error {
+	return helpers.ResultSuccessFindJsonApi(app, metricsControllerImpl.MetricsService.Metrics())
+}
@@ -0,0 +1,22 @@
+package reviewcontrollers
+
+import (
+	reviewservices "github.com/arifin2018/splitbill-arifin.git/services/ReviewServices"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewController interface {
+	ListReviews(app *fiber.Ctx) error
+	GetReview(app *fiber.Ctx) error
+	Review(app *fiber.Ctx) error
+}
+
+type ReviewControllerImpl struct {
+	ReviewService reviewservices.ReviewService
+}
+
+func NewReviewController(reviewService reviewservices.ReviewService) *ReviewControllerImpl {
+	return &ReviewControllerImpl{
+		ReviewService: reviewService,
+	}
+}
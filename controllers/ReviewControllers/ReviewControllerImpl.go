@@ -0,0 +1,62 @@
+package reviewcontrollers
+
+import (
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListReviews lists receipts in the review queue
+// @Summary List the review queue
+// @Description List one page of receipts flagged for human review because arithmetic validation failed or some fields have low confidence, oldest first. Each entry shows the stored image URLs next to the extracted JSON, the review reasons, validation issues and low-confidence fields
+// @Tags Review
+// @Produce json
+// @Param status query string false "Review status to list (pending_review, approved, rejected or all)" default(pending_review)
+// @Param limit query int false "Number of receipts per page (1-200)" default(50)
+// @Param offset query int false "Number of receipts to skip" default(0)
+// @Success 200 {object} models.ReviewQueuePage "Review queue page"
+// @Failure 406 {object} models.ErrorResponse "Unknown status or invalid limit/offset"
+// @Router /reviews [get]
+func (reviewControllerImpl *ReviewControllerImpl) ListReviews(app *fiber.Ctx) error {
+	queue, err := reviewControllerImpl.ReviewService.ListReviews(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, queue)
+}
+
+// GetReview returns one receipt of the review queue
+// @Summary Get a review queue entry
+// @Description Get a flagged receipt with its stored image URLs next to the extracted JSON
+// @Tags Review
+// @Produce json
+// @Param id path string true "Receipt ID"
+// @Success 200 {object} models.ReviewQueueItem "Review queue entry"
+// @Failure 406 {object} models.ErrorResponse "Receipt not found or not in the review queue"
+// @Router /reviews/{id} [get]
+func (reviewControllerImpl *ReviewControllerImpl) GetReview(app *fiber.Ctx) error {
+	item, err := reviewControllerImpl.ReviewService.GetReview(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, item)
+}
+
+// Review approves, corrects or rejects a receipt pending review
+// @Summary Review a flagged receipt
+// @Description Decide on a receipt in "pending_review". "approve" accepts the extraction as is, "correct" replaces the items, store information, totals, transaction information and extensions with "data", re-validates and approves, and "reject" rejects the receipt so its bill cannot be locked. Receipts that are not pending review or already locked return 409
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Receipt ID"
+// @Param request body models.ReviewRequest true "Review decision (approve, correct or reject)"
+// @Success 200 {object} models.Receipt "Reviewed receipt"
+// @Failure 406 {object} models.ErrorResponse "Receipt not found, unknown action or missing corrected data"
+// @Failure 409 {object} models.ErrorResponse "Receipt is not pending review or is locked"
+// @Router /reviews/{id} [post]
+func (reviewControllerImpl *ReviewControllerImpl) Review(app *fiber.Ctx) error {
+	receipt, err := reviewControllerImpl.ReviewService.Review(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessUpdateJsonApi(app, receipt)
+}
@@ -7,6 +7,14 @@ import (
 
 type SplitbilController interface {
 	Splitbil(app *fiber.Ctx) error
+	SplitbilText(app *fiber.Ctx) error
+	GetReceipt(app *fiber.Ctx) error
+	ResolveDuplicate(app *fiber.Ctx) error
+	LockReceipt(app *fiber.Ctx) error
+	GetJob(app *fiber.Ctx) error
+	SubmitBatch(app *fiber.Ctx) error
+	GetBatch(app *fiber.Ctx) error
+	BatchCSV(app *fiber.Ctx) error
 }
 
 type SplitbillControllerImpl struct {
@@ -1,24 +1,266 @@
 package splitbillcontollers
 
 import (
+	"bufio"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/arifin2018/splitbill-arifin.git/models"
 	"github.com/gofiber/fiber/v2"
 )
 
-// Splitbil processes receipt image and extracts splitbill information
-// @Summary Extract splitbill information from receipt image
-// @Description Upload a receipt image and extract detailed splitbill information including items, store details, totals, and transaction information using OCR and AI
+// Splitbil processes receipt image or PDF and extracts splitbill information
+// @Summary Extract splitbill information from receipt image or PDF
+// @Description Upload a receipt image, a PDF receipt/e-invoice, or ordered section photos of one long receipt ("images"), and extract items, store details, totals and transaction information. The response is synchronous by default. With async=true it returns a job ID, and with stream=true it streams progress as Server-Sent Events. The result can also be delivered to a signed callback URL. Upload limits, quality checks, caching, duplicate detection, the review queue, rate limits, timeouts and Gemini key rotation are described per feature in API_DOCUMENTATION.md under POST /
 // @Tags Splitbill
 // @Accept multipart/form-data
 // @Produce json
-// @Param image formData file true "Receipt image file (jpg, jpeg, png)"
-// @Success 202 {object} models.SplitbillResponse "Successfully processed receipt"
+// @Param image formData file false "Receipt image file (jpg, jpeg, png, webp, heic, avif) or PDF receipt/invoice"
+// @Param images formData []file false "Ordered receipt section images (top to bottom) of one long receipt"
+// @Param async query bool false "Return 202 with a job ID immediately and extract in the background (also enabled by the header Prefer: respond-async)"
+// @Param stream query bool false "Stream progress as Server-Sent Events (also enabled by the header Accept: text/event-stream)"
+// @Param callback_url query string false "URL that receives the signed extraction result when extraction finishes (also accepted as a form field)"
+// @Param X-API-Key header string false "API key whose registered webhook receives the result"
+// @Success 202 {object} models.SplitbillResponse "Successfully processed receipt, or models.JobAccepted in async mode"
 // @Failure 406 {object} models.ErrorResponse "Failed to process receipt"
+// @Failure 413 {object} models.ErrorResponse "File or image dimensions exceed the upload limits"
+// @Failure 415 {object} models.ErrorResponse "Unsupported, mismatched or polyglot file"
+// @Failure 429 {object} models.ErrorResponse "Extraction queue is full (Retry-After header)"
+// @Failure 503 {object} models.ErrorResponse "Async job queue is full, no extraction slot became available in time, or every Gemini API key is out of quota (Retry-After header)"
+// @Failure 504 {object} models.ErrorResponse "Extraction did not finish within EXTRACTION_TIMEOUT"
 // @Router / [post]
 func (splitbillControllerImpl *SplitbillControllerImpl) Splitbil(app *fiber.Ctx) error {
+	if streamRequested(app) {
+		return splitbillControllerImpl.streamSplitbil(app)
+	}
+	if asyncRequested(app) {
+		job, err := splitbillControllerImpl.SplitbillService.SubmitSplitbil(app)
+		if err != nil {
+			return helpers.ResultErrorJsonApi(app, err)
+		}
+		return helpers.ResultSuccessJsonApi(app, job)
+	}
 	jsonData, err := splitbillControllerImpl.SplitbillService.Splitbil(app)
 	if err != nil {
-		return helpers.ResultFailedJsonApi(app, jsonData, err.Error())
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessJsonApi(app, jsonData)
+}
+
+// SplitbilText extracts splitbill information from receipt text or an HTML e-receipt
+// @Summary Extract splitbill information from receipt text or e-receipt
+// @Description Parse plain receipt text (OCR output, GoFood/GrabFood order summaries, bank notifications, pasted POS output) or an HTML e-receipt such as an email body. Send JSON with "text" or "html", or a raw text/plain or text/html body. Clean, well-formatted Indonesian receipts are parsed by the rule-based parser without an AI call; other text falls back to Gemini. The source is stored in the bucket and the result is validated like the image path. Model results include per-field confidence and "low_confidence_fields". Model calls are subject to the global extraction limit (429/503 with Retry-After) and rotate across the Gemini API key pool (503 model_quota_exhausted when every key is out of quota)
+// @Tags Splitbill
+// @Accept json,plain,html
+// @Produce json
+// @Param request body models.TextReceiptRequest true "Receipt text or HTML"
+// @Success 202 {object} models.SplitbillResponse "Successfully processed receipt"
+// @Failure 406 {object} models.ErrorResponse "Failed to process receipt"
+// @Failure 429 {object} models.ErrorResponse "Extraction queue is full (Retry-After header)"
+// @Failure 503 {object} models.ErrorResponse "No extraction slot became available in time, or every Gemini API key is out of quota (Retry-After header)"
+// @Failure 504 {object} models.ErrorResponse "Extraction did not finish within EXTRACTION_TIMEOUT"
+// @Router /text [post]
+func (splitbillControllerImpl *SplitbillControllerImpl) SplitbilText(app *fiber.Ctx) error {
+	jsonData, err := splitbillControllerImpl.SplitbillService.SplitbilText(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
 	}
 	return helpers.ResultSuccessJsonApi(app, jsonData)
 }
+
+// GetReceipt returns a stored receipt record
+// @Summary Get a stored receipt
+// @Description Get a stored receipt record with all of its source files (for example every section image of a long receipt) and the extraction result
+// @Tags Splitbill
+// @Produce json
+// @Param id path string true "Receipt ID"
+// @Success 200 {object} models.Receipt "Stored receipt"
+// @Failure 406 {object} models.ErrorResponse "Receipt not found"
+// @Router /receipts/{id} [get]
+func (splitbillControllerImpl *SplitbillControllerImpl) GetReceipt(app *fiber.Ctx) error {
+	receipt, err := splitbillControllerImpl.SplitbillService.GetReceipt(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, receipt)
+}
+
+// ResolveDuplicate merges or keeps a receipt flagged as a probable duplicate
+// @Summary Resolve a duplicate receipt warning
+// @Description Resolve the "duplicate_warning" of a receipt. "merge" deletes this receipt, adds its source files to the existing receipt and returns the existing receipt; "keep_both" keeps both receipts and clears the warning
+// @Tags Splitbill
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the receipt flagged as duplicate"
+// @Param request body models.DuplicateResolutionRequest true "Resolution action (merge or keep_both)"
+// @Success 200 {object} models.Receipt "Remaining receipt"
+// @Failure 406 {object} models.ErrorResponse "Receipt not found, no duplicate warning or unknown action"
+// @Failure 409 {object} models.ErrorResponse "Receipt is locked, or its image is still pending upload"
+// @Router /receipts/{id}/duplicate [post]
+func (splitbillControllerImpl *SplitbillControllerImpl) ResolveDuplicate(app *fiber.Ctx) error {
+	receipt, err := splitbillControllerImpl.SplitbillService.ResolveDuplicate(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessUpdateJsonApi(app, receipt)
+}
+
+// LockReceipt locks the bill of a receipt before it is split
+// @Summary Lock a receipt bill
+// @Description Lock the bill of a receipt so it can be split. Receipts in the review queue must be approved first; a receipt still in "pending_review" or "rejected" returns 409 with code "review_required". Locking an already locked receipt returns it unchanged
+// @Tags Splitbill
+// @Produce json
+// @Param id path string true "Receipt ID"
+// @Success 200 {object} models.Receipt "Locked receipt"
+// @Failure 406 {object} models.ErrorResponse "Receipt not found"
+// @Failure 409 {object} models.ErrorResponse "Receipt has not been approved"
+// @Router /receipts/{id}/lock [post]
+func (splitbillControllerImpl *SplitbillControllerImpl) LockReceipt(app *fiber.Ctx) error {
+	receipt, err := splitbillControllerImpl.SplitbillService.LockReceipt(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessUpdateJsonApi(app, receipt)
+}
+
+// GetJob returns the status of an asynchronous extraction job
+// @Summary Get an extraction job
+// @Description Poll an asynchronous extraction job created with POST /?async=true. "status" is queued, running, succeeded or failed; "stage" and "progress" (0-100) show how far the extraction is. A succeeded job carries the extraction result in "result", a failed job the HTTP status, code and message the synchronous request would have returned in "error"
+// @Tags Splitbill
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job "Job status"
+// @Failure 406 {object} models.ErrorResponse "Job not found"
+// @Router /jobs/{id} [get]
+func (splitbillControllerImpl *SplitbillControllerImpl) GetJob(app *fiber.Ctx) error {
+	job, err := splitbillControllerImpl.SplitbillService.GetJob(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, job)
+}
+
+// SubmitBatch creates one extraction job per receipt in a ZIP archive or multipart files
+// @Summary Upload a batch of receipts
+// @Description Upload a ZIP archive ("archive") and/or several receipt files ("files") and create one asynchronous extraction job per image or PDF. Batch jobs share BATCH_CONCURRENCY slots across all batches, in addition to the JOB_WORKERS pool; files are read and queued one at a time; files rejected while reading (too large, unreadable) are listed as failed without failing the batch. Folders, hidden files and macOS metadata in the archive are skipped. Batches with more than BATCH_MAX_FILES files or more than BATCH_MAX_BYTES uncompressed are rejected from the archive directory before anything is decompressed. Poll GET /batches/{id} for aggregate progress and per-file results, and download the combined result from GET /batches/{id}/csv
+// @Tags Batch
+// @Accept multipart/form-data
+// @Produce json
+// @Param archive formData file false "ZIP archive of receipt images or PDFs"
+// @Param files formData []file false "Receipt images or PDFs (ZIP archives are expanded too)"
+// @Success 202 {object} models.Batch "Accepted batch"
+// @Failure 406 {object} models.ErrorResponse "No files, unreadable archive or too many files"
+// @Router /batches [post]
+func (splitbillControllerImpl *SplitbillControllerImpl) SubmitBatch(app *fiber.Ctx) error {
+	batch, err := splitbillControllerImpl.SplitbillService.SubmitBatch(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessJsonApi(app, batch)
+}
+
+// GetBatch returns the progress and per-file results of a batch
+// @Summary Get a batch
+// @Description Get a batch with its status (queued, running, completed), counters, aggregate progress (0-100) and for each file its job status, stage, progress, extraction result or error
+// @Tags Batch
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} models.Batch "Batch status"
+// @Failure 406 {object} models.ErrorResponse "Batch not found"
+// @Router /batches/{id} [get]
+func (splitbillControllerImpl *SplitbillControllerImpl) GetBatch(app *fiber.Ctx) error {
+	batch, err := splitbillControllerImpl.SplitbillService.GetBatch(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, batch)
+}
+
+// BatchCSV downloads the results of a batch as CSV
+// @Summary Download a batch as CSV
+// @Description Download the results of a batch as one CSV with a row per item (file, status, error, receipt, store, date, item and totals columns). Failed files and receipts without items get one row; files still running have only their status
+// @Tags Batch
+// @Produce text/csv
+// @Param id path string true "Batch ID"
+// @Success 200 {file} file "Batch CSV"
+// @Failure 406 {object} models.ErrorResponse "Batch not found"
+// @Router /batches/{id}/csv [get]
+func (splitbillControllerImpl *SplitbillControllerImpl) BatchCSV(app *fiber.Ctx) error {
+	data, err := splitbillControllerImpl.SplitbillService.BatchCSV(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	app.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	app.Attachment("batch-" + app.Params("id") + ".csv")
+	return app.Status(fiber.StatusOK).Send(data)
+}
+
+// asyncRequested bernilai true jika client meminta mode async lewat query async=true atau header
+// Prefer: respond-async
+func asyncRequested(app *fiber.Ctx) bool {
+	return app.QueryBool("async") || strings.Contains(strings.ToLower(app.Get("Prefer")), "respond-async")
+}
+
+// streamRequested bernilai true jika client meminta stream progress lewat query stream=true atau
+// header Accept: text/event-stream
+func streamRequested(app *fiber.Ctx) bool {
+	return app.QueryBool("stream") || strings.Contains(strings.ToLower(app.Get(fiber.HeaderAccept)), "text/event-stream")
+}
+
+// streamSplitbil mengirim progress ekstraksi sebagai Server-Sent Events. Kegagalan membaca upload
+// dikembalikan sebagai respons error biasa sebelum stream dimulai; kegagalan setelahnya dikirim
+// sebagai event "error".
+func (splitbillControllerImpl *SplitbillControllerImpl) streamSplitbil(app *fiber.Ctx) error {
+	extract, err := splitbillControllerImpl.SplitbillService.StreamSplitbil(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	helpers.SetEventStreamHeaders(app)
+	userContext := app.UserContext()
+	app.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Client yang memutus koneksi terdeteksi saat event atau heartbeat gagal ditulis; ekstraksi
+		// yang sedang berjalan lalu dibatalkan agar panggilan model berhenti
+		ctx, cancel := context.WithCancel(userContext)
+		defer cancel()
+		var writeLock sync.Mutex
+		disconnected := false
+		write := func(send func() error) {
+			writeLock.Lock()
+			defer writeLock.Unlock()
+			if disconnected {
+				return
+			}
+			if err := send(); err != nil {
+				disconnected = true
+				cancel()
+			}
+		}
+
+		done := make(chan struct{})
+		var heartbeat sync.WaitGroup
+		heartbeat.Add(1)
+		go func() {
+			defer heartbeat.Done()
+			ticker := time.NewTicker(helpers.HeartbeatInterval())
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					write(func() error { return helpers.WriteHeartbeat(w) })
+				}
+			}
+		}()
+
+		extract(ctx, func(event models.ProgressEvent) {
+			write(func() error { return helpers.WriteEvent(w, event.Event, event) })
+		})
+		close(done)
+		heartbeat.Wait()
+	})
+	return nil
+}
@@ -0,0 +1,25 @@
+package webhookcontrollers
+
+import (
+	webhookservices "github.com/arifin2018/splitbill-arifin.git/services/WebhookServices"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookController interface {
+	Register(app *fiber.Ctx) error
+	GetRegistration(app *fiber.Ctx) error
+	DeleteRegistration(app *fiber.Ctx) error
+	ListDeliveries(app *fiber.Ctx) error
+	GetDelivery(app *fiber.Ctx) error
+	Redeliver(app *fiber.Ctx) error
+}
+
+type WebhookControllerImpl struct {
+	WebhookService webhookservices.WebhookService
+}
+
+func NewWebhookController(webhookService webhookservices.WebhookService) *WebhookControllerImpl {
+	return &WebhookControllerImpl{
+		WebhookService: webhookService,
+	}
+}
@@ -0,0 +1,120 @@
+package webhookcontrollers
+
+import (
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Register registers the callback URL of an API key
+// @Summary Register a webhook callback URL
+// @Description Register or replace the callback URL for the API key in the X-API-Key header. Extractions sent with the same X-API-Key POST their result to this URL when they finish. A signing secret is generated for a new registration when "secret" is empty. The secret is only returned when it is created or replaced; updating the URL without "secret" keeps the existing secret and does not return it. When WEBHOOK_API_KEYS is set only the listed API keys are accepted; deliveries carry X-Splitbill-Signature (sha256=HMAC-SHA256 of "<timestamp>.<body>") and X-Splitbill-Timestamp
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Param request body models.WebhookRegistrationRequest true "Callback URL and optional signing secret"
+// @Success 200 {object} models.WebhookRegistration "Registered webhook with its signing secret"
+// @Failure 401 {object} models.ErrorResponse "Missing or unknown X-API-Key"
+// @Failure 406 {object} models.ErrorResponse "Invalid callback URL or internal host"
+// @Router /webhooks [put]
+func (webhookControllerImpl *WebhookControllerImpl) Register(app *fiber.Ctx) error {
+	registration, err := webhookControllerImpl.WebhookService.Register(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessUpdateJsonApi(app, registration)
+}
+
+// GetRegistration returns the callback URL of an API key
+// @Summary Get the webhook of an API key
+// @Description Get the callback URL registered for the API key in the X-API-Key header. The signing secret is not returned
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Success 200 {object} models.WebhookRegistration "Registered webhook"
+// @Failure 401 {object} models.ErrorResponse "Missing or unknown X-API-Key"
+// @Failure 406 {object} models.ErrorResponse "No webhook registered"
+// @Router /webhooks [get]
+func (webhookControllerImpl *WebhookControllerImpl) GetRegistration(app *fiber.Ctx) error {
+	registration, err := webhookControllerImpl.WebhookService.GetRegistration(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, registration)
+}
+
+// DeleteRegistration removes the callback URL of an API key
+// @Summary Delete the webhook of an API key
+// @Description Remove the callback URL registered for the API key in the X-API-Key header. Pending deliveries signed with its secret fail
+// @Tags Webhooks
+// @Param X-API-Key header string true "API key"
+// @Success 204 "Webhook deleted"
+// @Failure 401 {object} models.ErrorResponse "Missing or unknown X-API-Key"
+// @Router /webhooks [delete]
+func (webhookControllerImpl *WebhookControllerImpl) DeleteRegistration(app *fiber.Ctx) error {
+	if err := webhookControllerImpl.WebhookService.DeleteRegistration(app); err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessDeleteJsonApi(app, nil)
+}
+
+// ListDeliveries lists webhook deliveries
+// @Summary List webhook deliveries
+// @Description List webhook deliveries with every attempt (time, response status, error and duration), newest first. Only deliveries of the API key in the X-API-Key header are listed
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Param status query string false "Delivery status (pending, delivered or failed)"
+// @Param job_id query string false "Job ID"
+// @Param receipt_id query string false "Receipt ID"
+// @Success 200 {array} models.WebhookDelivery "Webhook deliveries"
+// @Failure 401 {object} models.ErrorResponse "Missing or unknown X-API-Key"
+// @Failure 406 {object} models.ErrorResponse "Unknown status"
+// @Router /webhooks/deliveries [get]
+func (webhookControllerImpl *WebhookControllerImpl) ListDeliveries(app *fiber.Ctx) error {
+	deliveries, err := webhookControllerImpl.WebhookService.ListDeliveries(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, deliveries)
+}
+
+// GetDelivery returns one webhook delivery
+// @Summary Get a webhook delivery
+// @Description Get a webhook delivery of the API key in the X-API-Key header with its payload and every attempt
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery "Webhook delivery"
+// @Failure 401 {object} models.ErrorResponse "Missing or unknown X-API-Key"
+// @Failure 404 {object} models.ErrorResponse "Delivery not found or owned by another API key"
+// @Failure 406 {object} models.ErrorResponse "Delivery could not be retrieved"
+// @Router /webhooks/deliveries/{id} [get]
+func (webhookControllerImpl *WebhookControllerImpl) GetDelivery(app *fiber.Ctx) error {
+	delivery, err := webhookControllerImpl.WebhookService.GetDelivery(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessFindJsonApi(app, delivery)
+}
+
+// Redeliver sends a webhook delivery again
+// @Summary Redeliver a webhook
+// @Description Send a delivered or failed webhook again with a new timestamp and signature. One attempt is made immediately; if it fails the delivery is retried with backoff like a new delivery. Only deliveries of the API key in the X-API-Key header can be redelivered
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery "Webhook delivery after the attempt"
+// @Failure 401 {object} models.ErrorResponse "Missing or unknown X-API-Key"
+// @Failure 404 {object} models.ErrorResponse "Delivery not found or owned by another API key"
+// @Failure 406 {object} models.ErrorResponse "Delivery is being sent"
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (webhookControllerImpl *WebhookControllerImpl) Redeliver(app *fiber.Ctx) error {
+	delivery, err := webhookControllerImpl.WebhookService.Redeliver(app)
+	if err != nil {
+		return helpers.ResultErrorJsonApi(app, err)
+	}
+	return helpers.ResultSuccessUpdateJsonApi(app, delivery)
+}
@@ -27,7 +27,7 @@ const docTemplate = `{
     "paths": {
         "/": {
             "post": {
-                "description": "Upload a receipt image and extract detailed splitbill information including items, store details, totals, and transaction information using OCR and AI",
+                "description": "Upload a receipt image, a PDF receipt/e-invoice, or ordered section photos of one long receipt (\"images\"), and extract items, store details, totals and transaction information. The response is synchronous by default. With async=true it returns a job ID, and with stream=true it streams progress as Server-Sent Events. The result can also be delivered to a signed callback URL. Upload limits, quality checks, caching, duplicate detection, the review queue, rate limits, timeouts and Gemini key rotation are described per feature in API_DOCUMENTATION.md under POST /",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -37,19 +37,51 @@ const docTemplate = `{
                 "tags": [
                     "Splitbill"
                 ],
-                "summary": "Extract splitbill information from receipt image",
+                "summary": "Extract splitbill information from receipt image or PDF",
                 "parameters": [
                     {
                         "type": "file",
-                        "description": "Receipt image file (jpg, jpeg, png)",
+                        "description": "Receipt image file (jpg, jpeg, png, webp, heic, avif) or PDF receipt/invoice",
                         "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "description": "Ordered receipt section images (top to bottom) of one long receipt",
+                        "name": "images",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return 202 with a job ID immediately and extract in the background (also enabled by the header Prefer: respond-async)",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Stream progress as Server-Sent Events (also enabled by the header Accept: text/event-stream)",
+                        "name": "stream",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL that receives the signed extraction result when extraction finishes (also accepted as a form field)",
+                        "name": "callback_url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key whose registered webhook receives the result",
+                        "name": "X-API-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Successfully processed receipt",
+                        "description": "Successfully processed receipt, or models.JobAccepted in async mode",
                         "schema": {
                             "$ref": "#/definitions/models.SplitbillResponse"
                         }
@@ -59,54 +91,1712 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    },
+                    "413": {
+                        "description": "File or image dimensions exceed the upload limits",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported, mismatched or polyglot file",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Extraction queue is full (Retry-After header)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Async job queue is full, no extraction slot became available in time, or every Gemini API key is out of quota (Retry-After header)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Extraction did not finish within EXTRACTION_TIMEOUT",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/batches": {
+            "post": {
+                "description": "Upload a ZIP archive (\"archive\") and/or several receipt files (\"files\") and create one asynchronous extraction job per image or PDF. Batch jobs share BATCH_CONCURRENCY slots across all batches, in addition to the JOB_WORKERS pool; files are read and queued one at a time; files rejected while reading (too large, unreadable) are listed as failed without failing the batch. Folders, hidden files and macOS metadata in the archive are skipped. Batches with more than BATCH_MAX_FILES files or more than BATCH_MAX_BYTES uncompressed are rejected from the archive directory before anything is decompressed. Poll GET /batches/{id} for aggregate progress and per-file results, and download the combined result from GET /batches/{id}/csv",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batch"
+                ],
+                "summary": "Upload a batch of receipts",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "ZIP archive of receipt images or PDFs",
+                        "name": "archive",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "description": "Receipt images or PDFs (ZIP archives are expanded too)",
+                        "name": "files",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted batch",
+                        "schema": {
+                            "$ref": "#/definitions/models.Batch"
+                        }
+                    },
+                    "406": {
+                        "description": "No files, unreadable archive or too many files",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/batches/{id}": {
+            "get": {
+                "description": "Get a batch with its status (queued, running, completed), counters, aggregate progress (0-100) and for each file its job status, stage, progress, extraction result or error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batch"
+                ],
+                "summary": "Get a batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch status",
+                        "schema": {
+                            "$ref": "#/definitions/models.Batch"
+                        }
+                    },
+                    "406": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/batches/{id}/csv": {
+            "get": {
+                "description": "Download the results of a batch as one CSV with a row per item (file, status, error, receipt, store, date, item and totals columns). Failed files and receipts without items get one row; files still running have only their status",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Batch"
+                ],
+                "summary": "Download a batch as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch CSV",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "406": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Poll an asynchronous extraction job created with POST /?async=true. \"status\" is queued, running, succeeded or failed; \"stage\" and \"progress\" (0-100) show how far the extraction is. A succeeded job carries the extraction result in \"result\", a failed job the HTTP status, code and message the synchronous request would have returned in \"error\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Splitbill"
+                ],
+                "summary": "Get an extraction job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Job status",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "406": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Get the state of the global limiter on in-flight model calls: slots in use, queue depth (request waiters and background async/batch jobs), acquired, rejected (429), timed out (503) and canceled waits, and wait times (count, total, average, maximum and a histogram in milliseconds), plus per-key usage counters of the Gemini API key pool (requests, successes, failures, quota errors and whether the key is benched)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get extraction metrics",
+                "responses": {
+                    "200": {
+                        "description": "Extraction metrics",
+                        "schema": {
+                            "$ref": "#/definitions/models.Metrics"
+                        }
+                    }
+                }
+            }
+        },
+        "/receipts/{id}": {
+            "get": {
+                "description": "Get a stored receipt record with all of its source files (for example every section image of a long receipt) and the extraction result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Splitbill"
+                ],
+                "summary": "Get a stored receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receipt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored receipt",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "406": {
+                        "description": "Receipt not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/receipts/{id}/duplicate": {
+            "post": {
+                "description": "Resolve the \"duplicate_warning\" of a receipt. \"merge\" deletes this receipt, adds its source files to the existing receipt and returns the existing receipt; \"keep_both\" keeps both receipts and clears the warning",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Splitbill"
+                ],
+                "summary": "Resolve a duplicate receipt warning",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the receipt flagged as duplicate",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolution action (merge or keep_both)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateResolutionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Remaining receipt",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "406": {
+                        "description": "Receipt not found, no duplicate warning or unknown action",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Receipt is locked, or its image is still pending upload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/receipts/{id}/lock": {
+            "post": {
+                "description": "Lock the bill of a receipt so it can be split. Receipts in the review queue must be approved first; a receipt still in \"pending_review\" or \"rejected\" returns 409 with code \"review_required\". Locking an already locked receipt returns it unchanged",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Splitbill"
+                ],
+                "summary": "Lock a receipt bill",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receipt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Locked receipt",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "406": {
+                        "description": "Receipt not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Receipt has not been approved",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "description": "List one page of receipts flagged for human review because arithmetic validation failed or some fields have low confidence, oldest first. Each entry shows the stored image URLs next to the extracted JSON, the review reasons, validation issues and low-confidence fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "List the review queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "pending_review",
+                        "description": "Review status to list (pending_review, approved, rejected or all)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of receipts per page (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of receipts to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review queue page",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewQueuePage"
+                        }
+                    },
+                    "406": {
+                        "description": "Unknown status or invalid limit/offset",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "get": {
+                "description": "Get a flagged receipt with its stored image URLs next to the extracted JSON",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get a review queue entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receipt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review queue entry",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewQueueItem"
+                        }
+                    },
+                    "406": {
+                        "description": "Receipt not found or not in the review queue",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Decide on a receipt in \"pending_review\". \"approve\" accepts the extraction as is, \"correct\" replaces the items, store information, totals, transaction information and extensions with \"data\", re-validates and approves, and \"reject\" rejects the receipt so its bill cannot be locked. Receipts that are not pending review or already locked return 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Review a flagged receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receipt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review decision (approve, correct or reject)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reviewed receipt",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "406": {
+                        "description": "Receipt not found, unknown action or missing corrected data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Receipt is not pending review or is locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/text": {
+            "post": {
+                "description": "Parse plain receipt text (OCR output, GoFood/GrabFood order summaries, bank notifications, pasted POS output) or an HTML e-receipt such as an email body. Send JSON with \"text\" or \"html\", or a raw text/plain or text/html body. Clean, well-formatted Indonesian receipts are parsed by the rule-based parser without an AI call; other text falls back to Gemini. The source is stored in the bucket and the result is validated like the image path. Model results include per-field confidence and \"low_confidence_fields\". Model calls are subject to the global extraction limit (429/503 with Retry-After) and rotate across the Gemini API key pool (503 model_quota_exhausted when every key is out of quota)",
+                "consumes": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Splitbill"
+                ],
+                "summary": "Extract splitbill information from receipt text or e-receipt",
+                "parameters": [
+                    {
+                        "description": "Receipt text or HTML",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TextReceiptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully processed receipt",
+                        "schema": {
+                            "$ref": "#/definitions/models.SplitbillResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Failed to process receipt",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Extraction queue is full (Retry-After header)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "No extraction slot became available in time, or every Gemini API key is out of quota (Retry-After header)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Extraction did not finish within EXTRACTION_TIMEOUT",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Get the callback URL registered for the API key in the X-API-Key header. The signing secret is not returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get the webhook of an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registered webhook",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRegistration"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or unknown X-API-Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "No webhook registered",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Register or replace the callback URL for the API key in the X-API-Key header. Extractions sent with the same X-API-Key POST their result to this URL when they finish. A signing secret is generated for a new registration when \"secret\" is empty. The secret is only returned when it is created or replaced; updating the URL without \"secret\" keeps the existing secret and does not return it. When WEBHOOK_API_KEYS is set only the listed API keys are accepted; deliveries carry X-Splitbill-Signature (sha256=HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\") and X-Splitbill-Timestamp",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Register a webhook callback URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Callback URL and optional signing secret",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registered webhook with its signing secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRegistration"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or unknown X-API-Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Invalid callback URL or internal host",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the callback URL registered for the API key in the X-API-Key header. Pending deliveries signed with its secret fail",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete the webhook of an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "401": {
+                        "description": "Missing or unknown X-API-Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries": {
+            "get": {
+                "description": "List webhook deliveries with every attempt (time, response status, error and duration), newest first. Only deliveries of the API key in the X-API-Key header are listed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery status (pending, delivered or failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Receipt ID",
+                        "name": "receipt_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or unknown X-API-Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}": {
+            "get": {
+                "description": "Get a webhook delivery of the API key in the X-API-Key header with its payload and every attempt",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook delivery",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or unknown X-API-Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found or owned by another API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Delivery could not be retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/redeliver": {
+            "post": {
+                "description": "Send a delivered or failed webhook again with a new timestamp and signature. One attempt is made immediately; if it fails the delivery is retried with backoff like a new delivery. Only deliveries of the API key in the X-API-Key header can be redelivered",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Redeliver a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook delivery after the attempt",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or unknown X-API-Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found or owned by another API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Delivery is being sent",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.Batch": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchFile"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0d6f3b2a-5c8e-4f1a-9b7d-2e4c6a8f0b1d"
+                },
+                "progress": {
+                    "type": "integer",
+                    "example": 41
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 18
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 52
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchFile": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/models.JobError"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "2025-08/struk-parkir.jpg"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "job_id": {
+                    "type": "string",
+                    "example": "3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"
+                },
+                "progress": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "example": "6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.SplitbillResponse"
+                },
+                "stage": {
+                    "type": "string",
+                    "example": "done"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "models.BoundingBox": {
+            "type": "object",
+            "properties": {
+                "section": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "x_max": {
+                    "type": "number",
+                    "example": 0.92
+                },
+                "x_min": {
+                    "type": "number",
+                    "example": 0.08
+                },
+                "y_max": {
+                    "type": "number",
+                    "example": 0.44
+                },
+                "y_min": {
+                    "type": "number",
+                    "example": 0.41
+                }
+            }
+        },
+        "models.DocumentClass": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number",
+                    "example": 0.97
+                },
+                "label": {
+                    "type": "string",
+                    "example": "receipt"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Printed store receipt with item list and total"
+                }
+            }
+        },
+        "models.DuplicateResolutionRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "merge"
+                }
+            }
+        },
+        "models.DuplicateWarning": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "merge",
+                        "keep_both"
+                    ]
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "same_transaction_id",
+                        "same_total"
+                    ]
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "example": "6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/receipts/6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "not_a_receipt"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Error uploading image"
+                }
+            }
+        },
+        "models.ExtractionMetrics": {
+            "type": "object",
+            "properties": {
+                "acquired": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "canceled": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "in_flight": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "max_in_flight": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "queue_size": {
+                    "type": "integer",
+                    "example": 32
+                },
+                "queue_timeout_ms": {
+                    "type": "integer",
+                    "example": 30000
+                },
+                "queued": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "queued_background": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "timed_out": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "wait": {
+                    "$ref": "#/definitions/models.WaitMetrics"
+                }
+            }
+        },
+        "models.FuelExtension": {
+            "type": "object",
+            "properties": {
+                "fuel_type": {
+                    "type": "string",
+                    "example": "PERTALITE"
+                },
+                "liters": {
+                    "type": "string",
+                    "example": "20.50"
+                },
+                "price_per_liter": {
+                    "type": "string",
+                    "example": "10000.00"
+                },
+                "pump_number": {
+                    "type": "string",
+                    "example": "3"
+                },
+                "vehicle_plate": {
+                    "type": "string",
+                    "example": "B 1234 XYZ"
+                }
+            }
+        },
+        "models.GeminiKeyMetrics": {
+            "type": "object",
+            "properties": {
+                "benched": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "benched_until": {
+                    "type": "string"
+                },
+                "benches": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 22
+                },
+                "id": {
+                    "type": "string",
+                    "example": "key-1"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "...x9Qa"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "quota_errors": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 812
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 790
+                }
+            }
+        },
+        "models.HotelExtension": {
+            "type": "object",
+            "properties": {
+                "check_in": {
+                    "type": "string",
+                    "example": "10/08/2025"
+                },
+                "check_out": {
+                    "type": "string",
+                    "example": "12/08/2025"
+                },
+                "guest_name": {
+                    "type": "string",
+                    "example": "ARIFIN"
+                },
+                "room_nights": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "room_number": {
+                    "type": "string",
+                    "example": "1207"
+                }
+            }
+        },
+        "models.ImageQuality": {
+            "type": "object",
+            "properties": {
+                "brightness": {
+                    "type": "number",
+                    "example": 168.2
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 1920
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "overexposed_ratio": {
+                    "type": "number",
+                    "example": 0.04
+                },
+                "passed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sharpness": {
+                    "type": "number",
+                    "example": 412.7
+                },
+                "underexposed_ratio": {
+                    "type": "number",
+                    "example": 0.01
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1080
+                }
+            }
+        },
+        "models.Item": {
+            "type": "object",
+            "properties": {
+                "bounding_box": {
+                    "description": "BoundingBox adalah lokasi baris item di gambar jika EXTRACTION_BOUNDING_BOXES aktif",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BoundingBox"
+                        }
+                    ]
+                },
+                "confidence": {
+                    "description": "Confidence berisi confidence model untuk baris item dan setiap field-nya",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ItemConfidence"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Nasi Goreng"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "25000.00"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "50000.00"
+                }
+            }
+        },
+        "models.ItemConfidence": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "number",
+                    "example": 0.82
+                },
+                "name": {
+                    "type": "number",
+                    "example": 0.97
+                },
+                "price": {
+                    "type": "number",
+                    "example": 0.55
+                },
+                "quantity": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "total": {
+                    "type": "number",
+                    "example": 0.9
+                }
+            }
+        },
+        "models.ItemUnit": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "BERAS PANDAN WANGI"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "kg"
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "batch_id": {
+                    "type": "string",
+                    "example": "0d6f3b2a-5c8e-4f1a-9b7d-2e4c6a8f0b1d"
+                },
+                "callback": {
+                    "$ref": "#/definitions/models.WebhookTarget"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/models.JobError"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"
+                },
+                "progress": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "result": {
+                    "$ref": "#/definitions/models.SplitbillResponse"
+                },
+                "stage": {
+                    "type": "string",
+                    "example": "extracting"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "image_blurry"
+                },
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "photo quality is too low (image_blurry), please retake the photo"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 406
+                }
+            }
+        },
+        "models.Metrics": {
+            "type": "object",
+            "properties": {
+                "extraction": {
+                    "$ref": "#/definitions/models.ExtractionMetrics"
+                },
+                "gemini_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GeminiKeyMetrics"
+                    }
+                }
+            }
+        },
+        "models.ParkingExtension": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string",
+                    "example": "9 jam 25 menit"
+                },
+                "entry_time": {
+                    "type": "string",
+                    "example": "08:15"
+                },
+                "exit_time": {
+                    "type": "string",
+                    "example": "17:40"
+                },
+                "vehicle_plate": {
+                    "type": "string",
+                    "example": "B 1234 XYZ"
+                },
+                "vehicle_type": {
+                    "type": "string",
+                    "example": "MOBIL"
+                }
+            }
+        },
+        "models.Receipt": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "$ref": "#/definitions/models.SplitbillResponse"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                },
+                "image_hash": {
+                    "type": "string",
+                    "example": "f0e4c2d8a1b3c5e7"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "source_group_id": {
+                    "type": "string",
+                    "example": "0b9d6c1e-7f3a-4c2d-8e5b-1a2b3c4d5e6f"
+                },
+                "source_index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "source_type": {
+                    "type": "string",
+                    "example": "sections"
+                },
+                "source_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
+        },
+        "models.ReceiptExtensions": {
             "type": "object",
             "properties": {
-                "data": {
+                "fuel": {
+                    "$ref": "#/definitions/models.FuelExtension"
+                },
+                "hotel": {
+                    "$ref": "#/definitions/models.HotelExtension"
+                },
+                "parking": {
+                    "$ref": "#/definitions/models.ParkingExtension"
+                },
+                "restaurant": {
+                    "$ref": "#/definitions/models.RestaurantExtension"
+                },
+                "supermarket": {
+                    "$ref": "#/definitions/models.SupermarketExtension"
+                }
+            }
+        },
+        "models.ReceiptReview": {
+            "type": "object",
+            "properties": {
+                "corrected": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Harga item kedua dikoreksi dari foto"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "validation_failed",
+                        "low_confidence"
+                    ]
+                },
+                "reviewed_at": {
                     "type": "string"
                 },
+                "reviewer": {
+                    "type": "string",
+                    "example": "budi@example.com"
+                },
                 "status": {
                     "type": "string",
-                    "example": "Error uploading image"
+                    "example": "pending_review"
                 }
             }
         },
-        "models.Item": {
+        "models.ReceiptValidation": {
             "type": "object",
             "properties": {
-                "name": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.RestaurantExtension": {
+            "type": "object",
+            "properties": {
+                "pax": {
                     "type": "string",
-                    "example": "Nasi Goreng"
+                    "example": "4"
                 },
-                "price": {
+                "table_number": {
                     "type": "string",
-                    "example": "25000.00"
+                    "example": "12"
                 },
-                "quantity": {
+                "waiter": {
                     "type": "string",
-                    "example": "2"
+                    "example": "Budi"
+                }
+            }
+        },
+        "models.ReviewQueueItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "$ref": "#/definitions/models.SplitbillResponse"
+                },
+                "image_status": {
+                    "type": "string",
+                    "example": "stored"
+                },
+                "image_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "low_confidence_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "items[2].price"
+                    ]
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "validation_failed",
+                        "low_confidence"
+                    ]
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "example": "6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending_review"
+                },
+                "validation_issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ReviewQueuePage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewQueueItem"
+                    }
                 },
                 "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "models.ReviewRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
                     "type": "string",
-                    "example": "50000.00"
+                    "example": "correct"
+                },
+                "data": {
+                    "$ref": "#/definitions/models.SplitbillResponse"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Harga item kedua dikoreksi dari foto"
+                },
+                "reviewer": {
+                    "type": "string",
+                    "example": "budi@example.com"
                 }
             }
         },
         "models.SplitbillResponse": {
             "type": "object",
             "properties": {
+                "cached": {
+                    "description": "Cached bernilai true jika hasil ekstraksi diambil dari cache karena file yang sama pernah diunggah",
+                    "type": "boolean",
+                    "example": false
+                },
+                "classification": {
+                    "$ref": "#/definitions/models.DocumentClass"
+                },
+                "duplicate_warning": {
+                    "description": "DuplicateWarning terisi jika struk yang sama kemungkinan besar sudah pernah diunggah",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DuplicateWarning"
+                        }
+                    ]
+                },
+                "extensions": {
+                    "$ref": "#/definitions/models.ReceiptExtensions"
+                },
+                "field_bounding_boxes": {
+                    "description": "FieldBoundingBoxes berisi lokasi teks sumber field di gambar jika EXTRACTION_BOUNDING_BOXES aktif",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.BoundingBox"
+                    }
+                },
+                "field_confidence": {
+                    "description": "FieldConfidence berisi confidence model (0-1) untuk field di luar items, dengan key berupa\npath field seperti \"totals.total\" atau \"store_information.store_name\"",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "image_status": {
+                    "description": "ImageStatus adalah status penyimpanan file sumber di bucket (stored, pending, failed)",
+                    "type": "string",
+                    "example": "stored"
+                },
                 "items": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Item"
                     }
                 },
+                "low_confidence_fields": {
+                    "description": "LowConfidenceFields berisi path field yang confidence-nya di bawah CONFIDENCE_LOW_THRESHOLD,\nmisalnya \"items[2].price\", sebagai target review",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "items[2].price",
+                        "totals.tax.amount"
+                    ]
+                },
+                "preprocessing_steps": {
+                    "description": "PreprocessingSteps mencatat langkah preprocessing yang diterapkan pada gambar sebelum diekstrak",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "auto_orient",
+                        "deskew",
+                        "contrast"
+                    ]
+                },
+                "quality": {
+                    "description": "Quality berisi skor kualitas foto yang diukur sebelum preprocessing dan ekstraksi",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ImageQuality"
+                        }
+                    ]
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "example": "6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                },
+                "receipt_type": {
+                    "type": "string",
+                    "example": "restaurant"
+                },
+                "receipts": {
+                    "description": "Receipts berisi semua struk yang terdeteksi jika satu foto memuat lebih dari satu struk.\nField lain pada respons ini sama dengan struk pertama.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SplitbillResponse"
+                    }
+                },
+                "review": {
+                    "description": "Review berisi status review receipt; receipt harus approved (atau tidak perlu direview)\nsebelum bisa dikunci",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReceiptReview"
+                        }
+                    ]
+                },
+                "source_url": {
+                    "type": "string",
+                    "example": "https://firebasestorage.googleapis.com/v0/b/bucket/o/images/20250802193000_struk.jpg?alt=media"
+                },
+                "storage_error": {
+                    "description": "StorageError terisi jika upload ke bucket gagal tetapi request tetap dilanjutkan",
+                    "type": "string"
+                },
                 "store_information": {
                     "$ref": "#/definitions/models.StoreInformation"
                 },
@@ -115,6 +1805,9 @@ const docTemplate = `{
                 },
                 "transaction_information": {
                     "$ref": "#/definitions/models.TransactionInfo"
+                },
+                "validation": {
+                    "$ref": "#/definitions/models.ReceiptValidation"
                 }
             }
         },
@@ -143,6 +1836,21 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SupermarketExtension": {
+            "type": "object",
+            "properties": {
+                "item_units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemUnit"
+                    }
+                },
+                "member_id": {
+                    "type": "string",
+                    "example": "8800123456"
+                }
+            }
+        },
         "models.Tax": {
             "type": "object",
             "properties": {
@@ -168,6 +1876,19 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TextReceiptRequest": {
+            "type": "object",
+            "properties": {
+                "html": {
+                    "type": "string",
+                    "example": "\u003ctable\u003e\u003ctr\u003e\u003ctd\u003e2x Nasi Goreng\u003c/td\u003e\u003ctd\u003eRp50.000\u003c/td\u003e\u003c/tr\u003e\u003c/table\u003e"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "INDOMARET\nINDOMIE GORENG 2 X 3.500 7.000\nTOTAL 7.000"
+                }
+            }
+        },
         "models.Totals": {
             "type": "object",
             "properties": {
@@ -212,6 +1933,183 @@ const docTemplate = `{
                     "example": "TXN123456789"
                 }
             }
+        },
+        "models.WaitMetrics": {
+            "type": "object",
+            "properties": {
+                "avg_ms": {
+                    "type": "integer",
+                    "example": 55
+                },
+                "buckets": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "max_ms": {
+                    "type": "integer",
+                    "example": 12840
+                },
+                "total_ms": {
+                    "type": "integer",
+                    "example": 84210
+                }
+            }
+        },
+        "models.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 183
+                },
+                "error": {
+                    "type": "string",
+                    "example": "unexpected status 500"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 500
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookAttempt"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "extraction.succeeded"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b7e2c1d4-8a3f-4e6b-9c2d-1f0e3a5b7c9d"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "$ref": "#/definitions/models.WebhookEvent"
+                },
+                "receipt_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "target": {
+                    "$ref": "#/definitions/models.WebhookTarget"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/models.JobError"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b7e2c1d4-8a3f-4e6b-9c2d-1f0e3a5b7c9d"
+                },
+                "job_id": {
+                    "type": "string",
+                    "example": "3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"
+                },
+                "receipt": {
+                    "$ref": "#/definitions/models.SplitbillResponse"
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "example": "6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "extraction.succeeded"
+                }
+            }
+        },
+        "models.WebhookRegistration": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_3f2a9c..."
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://api.example.com/splitbill/callback"
+                }
+            }
+        },
+        "models.WebhookRegistrationRequest": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string",
+                    "example": ""
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://api.example.com/splitbill/callback"
+                }
+            }
+        },
+        "models.WebhookTarget": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://api.example.com/splitbill/callback"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kode error yang dikembalikan di field "code" sehingga client bisa membedakan jenis kegagalan
+const (
+	ErrCodeNotAReceipt         = "not_a_receipt"
+	ErrCodeUnreadableImage     = "unreadable_image"
+	ErrCodeImageBlurry         = "image_blurry"
+	ErrCodeOverexposed         = "image_overexposed"
+	ErrCodeUnderexposed        = "image_underexposed"
+	ErrCodeLowResolution       = "image_resolution_too_low"
+	ErrCodeUnsupportedMedia    = "unsupported_media_type"
+	ErrCodeFileTooLarge        = "file_too_large"
+	ErrCodeDimensionsTooBig    = "image_dimensions_too_large"
+	ErrCodeTooManyPixels       = "image_too_many_pixels"
+	ErrCodeTypeMismatch        = "media_type_mismatch"
+	ErrCodePolyglotFile        = "polyglot_file"
+	ErrCodeReviewRequired      = "review_required"
+	ErrCodeReviewNotPending    = "review_not_pending"
+	ErrCodeReceiptLocked       = "receipt_locked"
+	ErrCodeImagePending        = "image_pending"
+	ErrCodeJobQueueFull        = "job_queue_full"
+	ErrCodeJobAttemptsExceeded = "job_attempts_exceeded"
+	ErrCodeAPIKeyRequired      = "api_key_required"
+	ErrCodeAPIKeyInvalid       = "api_key_invalid"
+	ErrCodeDeliveryNotFound    = "delivery_not_found"
+
+	ErrCodeExtractionQueueFull    = "extraction_queue_full"
+	ErrCodeExtractionQueueTimeout = "extraction_queue_timeout"
+	ErrCodeExtractionTimeout      = "extraction_timeout"
+	ErrCodeModelQuotaExhausted    = "model_quota_exhausted"
+)
+
+// ApiError adalah error dengan kode yang bisa dibaca client dan HTTP status sendiri.
+// Data opsional dikirim di field "data" respons error. RetryAfter yang diisi dikirim sebagai header
+// Retry-After (detik).
+type ApiError struct {
+	Status     int
+	Code       string
+	Message    string
+	Data       any
+	RetryAfter time.Duration
+}
+
+func NewApiError(status int, code string, message string, data any) *ApiError {
+	return &ApiError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// WithRetryAfter mengisi waktu tunggu yang disarankan sebelum client mencoba lagi
+func (apiError *ApiError) WithRetryAfter(retryAfter time.Duration) *ApiError {
+	apiError.RetryAfter = retryAfter
+	return apiError
+}
+
+func (apiError *ApiError) Error() string {
+	return apiError.Message
+}
+
+// ResultErrorJsonApi menulis respons error. ApiError dikirim dengan status dan kodenya sendiri,
+// error lain sama seperti ResultFailedJsonApi.
+func ResultErrorJsonApi(c *fiber.Ctx, err error) error {
+	var apiError *ApiError
+	if !errors.As(err, &apiError) {
+		return ResultFailedJsonApi(c, nil, err.Error())
+	}
+	if apiError.RetryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(apiError.RetryAfter.Seconds()))))
+	}
+	return c.Status(apiError.Status).JSON(fiber.Map{
+		"data":   apiError.Data,
+		"status": apiError.Message,
+		"code":   apiError.Code,
+	})
+}
@@ -0,0 +1,82 @@
+package caches
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	jobs "github.com/arifin2018/splitbill-arifin.git/helpers/Jobs"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+const extractionCacheCollection = "extraction_cache"
+
+// Database menyimpan hasil ekstraksi di document store sehingga cache tetap ada setelah restart.
+// Entry yang lebih tua dari TTL tidak dipakai lagi dan dihapus oleh purge di background.
+type Database struct {
+	Store documents.DocumentStoreInterface
+	TTL   time.Duration
+}
+
+type cachedExtraction struct {
+	Receipts  []models.SplitbillResponse `json:"receipts"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+// NewDatabase membaca umur entry dari EXTRACTION_CACHE_TTL (default 720h) dan menjalankan purge
+// entry kedaluwarsa setiap jam sampai jobs.Shutdown
+func NewDatabase(store documents.DocumentStoreInterface) *Database {
+	database := &Database{Store: store, TTL: cacheTTL()}
+	jobs.Go(func(ctx context.Context) {
+		database.purgeExpired(ctx, time.Hour)
+	})
+	return database
+}
+
+func (database *Database) Get(key string) ([]models.SplitbillResponse, bool) {
+	var cached cachedExtraction
+	if err := database.Store.Find(extractionCacheCollection, key, &cached); err != nil {
+		return nil, false
+	}
+	if time.Since(cached.CreatedAt) > database.TTL {
+		return nil, false
+	}
+	return cached.Receipts, len(cached.Receipts) > 0
+}
+
+func (database *Database) Set(key string, receipts []models.SplitbillResponse) {
+	cached := cachedExtraction{Receipts: receipts, CreatedAt: time.Now()}
+	if err := database.Store.Save(extractionCacheCollection, key, cached); err != nil {
+		config.GeneralLogger.Printf("Failed to save extraction cache: %v\n", err.Error())
+	}
+}
+
+// purgeExpired menghapus entry yang lebih tua dari TTL setiap interval, sehingga tabel cache tidak
+// tumbuh tanpa batas. Purge pertama dijalankan langsung; loop berhenti saat ctx dibatalkan.
+func (database *Database) purgeExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := database.Store.DeleteBefore(extractionCacheCollection, time.Now().Add(-database.TTL))
+		if err != nil {
+			config.GeneralLogger.Printf("Failed to purge extraction cache: %v\n", err.Error())
+		} else if deleted > 0 {
+			config.GeneralLogger.Printf("Purged %d expired extraction cache entries\n", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func cacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("EXTRACTION_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return ttl
+}
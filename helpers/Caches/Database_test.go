@@ -0,0 +1,28 @@
+package caches
+
+import (
+	"testing"
+	"time"
+
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+)
+
+func TestDatabaseTTL(t *testing.T) {
+	store := documents.NewFile(t.TempDir())
+	database := &Database{Store: store, TTL: time.Hour}
+	database.Set("fresh", cachedReceipt("1000"))
+	stale := cachedExtraction{Receipts: cachedReceipt("2000"), CreatedAt: time.Now().Add(-2 * time.Hour)}
+	if err := store.Save(extractionCacheCollection, "stale", stale); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if receipts, ok := database.Get("fresh"); !ok || receipts[0].Totals.Total != "1000" {
+		t.Errorf("Get(fresh) = %v, %v, want total 1000", receipts, ok)
+	}
+	if _, ok := database.Get("stale"); ok {
+		t.Errorf("Get(stale) hit, want expired")
+	}
+	if _, ok := database.Get("missing"); ok {
+		t.Errorf("Get(missing) hit")
+	}
+}
@@ -0,0 +1,54 @@
+package caches
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// ExtractionCacheInterface menyimpan hasil ekstraksi berdasarkan hash isi input, sehingga foto yang
+// sama tidak dikirim ulang ke model
+type ExtractionCacheInterface interface {
+	Get(key string) ([]models.SplitbillResponse, bool)
+	Set(key string, receipts []models.SplitbillResponse)
+}
+
+// NewExtractionCache memilih backend cache berdasarkan EXTRACTION_CACHE: "memory" (LRU di memori,
+// default), "database" (tabel documents, bertahan setelah restart) atau "off". Backend "database"
+// membutuhkan koneksi database; tanpa koneksi aplikasi berhenti saat start.
+func NewExtractionCache() ExtractionCacheInterface {
+	switch os.Getenv("EXTRACTION_CACHE") {
+	case "off":
+		return nil
+	case "database":
+		if config.DB == nil {
+			log.Fatalf("EXTRACTION_CACHE=database requires a database connection, but none is configured\n")
+		}
+		return NewDatabase(documents.NewDatabase(config.DB))
+	}
+	size, err := strconv.Atoi(os.Getenv("EXTRACTION_CACHE_SIZE"))
+	if err != nil || size <= 0 {
+		size = 500
+	}
+	return NewLRU(size)
+}
+
+// Key menghitung SHA-256 dari jenis input, versi extractor (model dan versi prompt) dan bytes input
+// yang sudah dinormalisasi. Setiap bagian diberi prefix panjang agar batas antar bagian tidak ambigu.
+func Key(kind string, version string, inputs ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range append([][]byte{[]byte(kind), []byte(version)}, inputs...) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		hash.Write(length[:])
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,31 @@
+package caches
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	base := Key("image", "gemini-2.5-flash/v3", []byte("foto"))
+	if base != Key("image", "gemini-2.5-flash/v3", []byte("foto")) {
+		t.Fatalf("Key() is not deterministic")
+	}
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "other kind", key: Key("pdf", "gemini-2.5-flash/v3", []byte("foto"))},
+		{name: "other model", key: Key("image", "gemini-2.5-pro/v3", []byte("foto"))},
+		{name: "other prompt version", key: Key("image", "gemini-2.5-flash/v4", []byte("foto"))},
+		{name: "other input", key: Key("image", "gemini-2.5-flash/v3", []byte("foto2"))},
+		// tanpa prefix panjang kedua input ini akan menghasilkan bytes yang sama
+		{name: "moved boundary", key: Key("image", "gemini-2.5-flash/v3", []byte("fo"), []byte("to"))},
+		{name: "boundary with version", key: Key("image", "gemini-2.5-flash/v3fo", []byte("to"))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.key == base {
+				t.Errorf("Key() = %s, want a different key", test.key)
+			}
+		})
+	}
+}
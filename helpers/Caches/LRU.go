@@ -0,0 +1,74 @@
+package caches
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// LRU adalah cache di memori dengan kapasitas tetap; entry yang paling lama tidak dipakai dibuang
+// lebih dulu. Hasil disimpan sebagai JSON sehingga perubahan pada hasil yang dikembalikan tidak
+// mengubah isi cache.
+type LRU struct {
+	Capacity int
+	mutex    sync.Mutex
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	data []byte
+}
+
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		Capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (lru *LRU) Get(key string) ([]models.SplitbillResponse, bool) {
+	lru.mutex.Lock()
+	element, ok := lru.entries[key]
+	var data []byte
+	if ok {
+		lru.order.MoveToFront(element)
+		// Set bisa mengganti data entry, jadi slice diambil selagi lock dipegang
+		data = element.Value.(*lruEntry).data
+	}
+	lru.mutex.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	var receipts []models.SplitbillResponse
+	if err := json.Unmarshal(data, &receipts); err != nil {
+		return nil, false
+	}
+	return receipts, true
+}
+
+func (lru *LRU) Set(key string, receipts []models.SplitbillResponse) {
+	data, err := json.Marshal(receipts)
+	if err != nil {
+		return
+	}
+
+	lru.mutex.Lock()
+	defer lru.mutex.Unlock()
+	if element, ok := lru.entries[key]; ok {
+		element.Value.(*lruEntry).data = data
+		lru.order.MoveToFront(element)
+		return
+	}
+	lru.entries[key] = lru.order.PushFront(&lruEntry{key: key, data: data})
+	for lru.order.Len() > lru.Capacity {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.entries, oldest.Value.(*lruEntry).key)
+	}
+}
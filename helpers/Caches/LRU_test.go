@@ -0,0 +1,46 @@
+package caches
+
+import (
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func cachedReceipt(total string) []models.SplitbillResponse {
+	var receipt models.SplitbillResponse
+	receipt.Totals.Total = total
+	return []models.SplitbillResponse{receipt}
+}
+
+func TestLRU(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Set("a", cachedReceipt("1000"))
+	lru.Set("b", cachedReceipt("2000"))
+	// membaca "a" membuatnya paling baru dipakai, sehingga "b" yang dibuang
+	if _, ok := lru.Get("a"); !ok {
+		t.Fatalf("Get(a) missed")
+	}
+	lru.Set("c", cachedReceipt("3000"))
+
+	if _, ok := lru.Get("b"); ok {
+		t.Errorf("Get(b) hit, want evicted")
+	}
+	for key, total := range map[string]string{"a": "1000", "c": "3000"} {
+		receipts, ok := lru.Get(key)
+		if !ok || len(receipts) != 1 || receipts[0].Totals.Total != total {
+			t.Errorf("Get(%s) = %v, %v, want total %s", key, receipts, ok, total)
+		}
+	}
+
+	// hasil yang dikembalikan adalah salinan, bukan isi cache
+	receipts, _ := lru.Get("a")
+	receipts[0].Totals.Total = "0"
+	if receipts, _ := lru.Get("a"); receipts[0].Totals.Total != "1000" {
+		t.Errorf("cached total = %s after changing the result, want 1000", receipts[0].Totals.Total)
+	}
+
+	lru.Set("a", cachedReceipt("1500"))
+	if receipts, _ := lru.Get("a"); receipts[0].Totals.Total != "1500" {
+		t.Errorf("total after Set = %s, want 1500", receipts[0].Totals.Total)
+	}
+}
@@ -0,0 +1,79 @@
+package documents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Document adalah baris tabel documents yang menyimpan dokumen JSON per koleksi
+type Document struct {
+	Collection string `gorm:"primaryKey;size:64"`
+	ID         string `gorm:"primaryKey;size:191"`
+	Data       []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Database menyimpan dokumen di tabel documents menggunakan GORM
+type Database struct {
+	DB *gorm.DB
+}
+
+func NewDatabase(db *gorm.DB) *Database {
+	if err := db.AutoMigrate(&Document{}); err != nil {
+		log.Fatalf("Error migrating documents table: %v\n", err)
+	}
+	return &Database{DB: db}
+}
+
+func (database *Database) Save(collection string, id string, document any) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("error encoding document: %w", err)
+	}
+	row := Document{Collection: collection, ID: id, Data: data}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "collection"}, {Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "updated_at"}),
+	}).Create(&row).Error
+}
+
+func (database *Database) Find(collection string, id string, document any) error {
+	var row Document
+	err := database.DB.Where("collection = ? AND id = ?", collection, id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDocumentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(row.Data, document)
+}
+
+func (database *Database) All(collection string, each func(data []byte) error) error {
+	var rows []Document
+	if err := database.DB.Where("collection = ?", collection).Order("created_at").Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := each(row.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (database *Database) Delete(collection string, id string) error {
+	return database.DB.Where("collection = ? AND id = ?", collection, id).Delete(&Document{}).Error
+}
+
+func (database *Database) DeleteBefore(collection string, before time.Time) (int64, error) {
+	result := database.DB.Where("collection = ? AND updated_at < ?", collection, before).Delete(&Document{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,35 @@
+package documents
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+)
+
+var ErrDocumentNotFound = errors.New("document not found")
+
+// DocumentStoreInterface menyimpan dokumen JSON per koleksi (receipt, job, dll.).
+// DeleteBefore menghapus dokumen koleksi yang terakhir disimpan sebelum waktu tertentu dan
+// mengembalikan jumlah dokumen yang dihapus.
+type DocumentStoreInterface interface {
+	Save(collection string, id string, document any) error
+	Find(collection string, id string, document any) error
+	All(collection string, each func(data []byte) error) error
+	Delete(collection string, id string) error
+	DeleteBefore(collection string, before time.Time) (int64, error)
+}
+
+// NewDocumentStore memilih penyimpanan dokumen berdasarkan DATA_STORAGE (DATABASE atau FILE).
+// DATABASE membutuhkan koneksi database; tanpa koneksi aplikasi berhenti saat start.
+func NewDocumentStore() DocumentStoreInterface {
+	if os.Getenv("DATA_STORAGE") == "DATABASE" {
+		if config.DB == nil {
+			log.Fatalf("DATA_STORAGE=DATABASE requires a database connection, but none is configured\n")
+		}
+		return NewDatabase(config.DB)
+	}
+	return NewFile("./storage/data")
+}
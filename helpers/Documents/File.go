@@ -0,0 +1,133 @@
+package documents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File menyimpan setiap dokumen sebagai file JSON di <dir>/<collection>/<id>.json
+type File struct {
+	Dir   string
+	mutex sync.RWMutex
+}
+
+func NewFile(dir string) *File {
+	return &File{Dir: dir}
+}
+
+func (file *File) Save(collection string, id string, document any) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("error encoding document: %w", err)
+	}
+
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	path, err := file.path(collection, id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating document directory: %w", err)
+	}
+	// Tulis ke file sementara lalu rename agar dokumen tidak pernah terbaca setengah jadi
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing document: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func (file *File) Find(collection string, id string, document any) error {
+	file.mutex.RLock()
+	defer file.mutex.RUnlock()
+
+	path, err := file.path(collection, id)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrDocumentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error reading document: %w", err)
+	}
+	return json.Unmarshal(data, document)
+}
+
+func (file *File) All(collection string, each func(data []byte) error) error {
+	file.mutex.RLock()
+	paths, err := filepath.Glob(filepath.Join(file.Dir, collection, "*.json"))
+	file.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		file.mutex.RLock()
+		data, err := os.ReadFile(path)
+		file.mutex.RUnlock()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error reading document: %w", err)
+		}
+		if err := each(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (file *File) Delete(collection string, id string) error {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	path, err := file.path(collection, id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting document: %w", err)
+	}
+	return nil
+}
+
+// DeleteBefore memakai waktu modifikasi file sebagai waktu dokumen terakhir disimpan
+func (file *File) DeleteBefore(collection string, before time.Time) (int64, error) {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(file.Dir, collection, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, fmt.Errorf("error deleting document: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (file *File) path(collection string, id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return "", fmt.Errorf("invalid document id %q", id)
+	}
+	return filepath.Join(file.Dir, collection, id+".json"), nil
+}
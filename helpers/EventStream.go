@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetEventStreamHeaders menyiapkan respons Server-Sent Events. Buffering proxy dimatikan agar
+// setiap event langsung sampai ke client.
+func SetEventStreamHeaders(c *fiber.Ctx) {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+}
+
+// WriteEvent menulis satu event SSE dengan data JSON lalu langsung mengirimnya. Error dikembalikan
+// jika client sudah memutus koneksi.
+func WriteEvent(w *bufio.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// WriteHeartbeat menulis komentar SSE yang diabaikan client. Dikirim berkala agar proxy tidak
+// menutup koneksi dan agar client yang sudah pergi terdeteksi meskipun belum ada event baru.
+func WriteHeartbeat(w *bufio.Writer) error {
+	if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// HeartbeatInterval membaca jeda heartbeat SSE dari STREAM_HEARTBEAT_INTERVAL (default 10s)
+func HeartbeatInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("STREAM_HEARTBEAT_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return 10 * time.Second
+	}
+	return interval
+}
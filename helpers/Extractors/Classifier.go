@@ -0,0 +1,20 @@
+package extractors
+
+import (
+	"context"
+
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// ClassifierInterface mengklasifikasikan gambar sebelum ekstraksi (struk, invoice, menu, bukan
+// dokumen, atau tidak terbaca) sehingga gambar yang bukan struk bisa ditolak tanpa menjalankan
+// prompt ekstraksi lengkap.
+type ClassifierInterface interface {
+	Classify(ctx context.Context, imageData []byte, mimeType string) (models.DocumentClass, error)
+}
+
+// NewClassifier mengembalikan classifier default (Gemini dengan prompt klasifikasi singkat)
+func NewClassifier(keys *GeminiKeyPool, limiter limits.LimiterInterface) ClassifierInterface {
+	return NewGemini(keys, limiter)
+}
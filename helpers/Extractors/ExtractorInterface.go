@@ -0,0 +1,50 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// ErrUnsupportedInput dikembalikan oleh extractor yang tidak bisa memproses jenis input tertentu
+// (misalnya parser berbasis aturan yang hanya bisa membaca teks).
+var ErrUnsupportedInput = errors.New("input type not supported by extractor")
+
+// ErrUnreconciled dikembalikan bersama hasil parsial ketika total struk tidak cocok dengan item-itemnya.
+var ErrUnreconciled = errors.New("receipt could not be reconciled")
+
+// ImageInput adalah satu gambar beserta MIME type-nya
+type ImageInput struct {
+	Data     []byte
+	MIMEType string
+}
+
+// ExtractorInterface adalah kontrak penyedia ekstraksi struk. ExtractFromImage juga menerima
+// dokumen PDF (mimeType "application/pdf"). ExtractReceiptsFromImage mendeteksi beberapa struk
+// terpisah dalam satu foto. ExtractFromImages menerima beberapa foto berurutan dari satu struk
+// panjang dan mengembalikan satu struk gabungan.
+type ExtractorInterface interface {
+	ExtractFromImage(ctx context.Context, imageData []byte, mimeType string) (models.SplitbillResponse, error)
+	ExtractReceiptsFromImage(ctx context.Context, imageData []byte, mimeType string) ([]models.SplitbillResponse, error)
+	ExtractFromImages(ctx context.Context, images []ImageInput) (models.SplitbillResponse, error)
+	ExtractFromText(ctx context.Context, text string) (models.SplitbillResponse, error)
+}
+
+// VersionedInterface diimplementasikan extractor yang hasilnya bergantung pada model dan versi
+// prompt. Versi ini menjadi bagian dari key cache hasil ekstraksi.
+type VersionedInterface interface {
+	Version() string
+}
+
+// NewExtractor menyusun rantai extractor default: parser berbasis aturan dicoba lebih dulu
+// (tanpa biaya AI), lalu Gemini sebagai cadangan.
+func NewExtractor(keys *GeminiKeyPool, limiter limits.LimiterInterface) ExtractorInterface {
+	return &Fallback{
+		Extractors: []ExtractorInterface{
+			new(RuleBased),
+			NewGemini(keys, limiter),
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// Fallback menjalankan beberapa extractor secara berurutan sampai salah satunya berhasil.
+// Hasil parsial (ErrUnreconciled) disimpan dan dikembalikan bersama error yang membungkus
+// ErrUnreconciled jika semua extractor berikutnya gagal.
+type Fallback struct {
+	Extractors []ExtractorInterface
+}
+
+func (fallback *Fallback) ExtractFromImage(ctx context.Context, imageData []byte, mimeType string) (models.SplitbillResponse, error) {
+	return runExtractors(fallback, func(extractor ExtractorInterface) (models.SplitbillResponse, error) {
+		return extractor.ExtractFromImage(ctx, imageData, mimeType)
+	}, hasItems)
+}
+
+func (fallback *Fallback) ExtractReceiptsFromImage(ctx context.Context, imageData []byte, mimeType string) ([]models.SplitbillResponse, error) {
+	return runExtractors(fallback, func(extractor ExtractorInterface) ([]models.SplitbillResponse, error) {
+		return extractor.ExtractReceiptsFromImage(ctx, imageData, mimeType)
+	}, func(results []models.SplitbillResponse) bool {
+		return len(results) > 0
+	})
+}
+
+func (fallback *Fallback) ExtractFromImages(ctx context.Context, images []ImageInput) (models.SplitbillResponse, error) {
+	return runExtractors(fallback, func(extractor ExtractorInterface) (models.SplitbillResponse, error) {
+		return extractor.ExtractFromImages(ctx, images)
+	}, hasItems)
+}
+
+func (fallback *Fallback) ExtractFromText(ctx context.Context, text string) (models.SplitbillResponse, error) {
+	return runExtractors(fallback, func(extractor ExtractorInterface) (models.SplitbillResponse, error) {
+		return extractor.ExtractFromText(ctx, text)
+	}, hasItems)
+}
+
+// Version menggabungkan versi semua extractor di dalam rantai yang memilikinya
+func (fallback *Fallback) Version() string {
+	versions := []string{}
+	for _, extractor := range fallback.Extractors {
+		if versioned, ok := extractor.(VersionedInterface); ok {
+			versions = append(versions, versioned.Version())
+		}
+	}
+	return strings.Join(versions, ",")
+}
+
+// runExtractors mencoba setiap extractor secara berurutan. usable menentukan apakah hasil parsial
+// dari ErrUnreconciled cukup berguna untuk dikembalikan jika semua extractor gagal; hasil tersebut
+// dikembalikan bersama error-nya sehingga pemanggil tahu hasilnya belum cocok.
+func runExtractors[T any](fallback *Fallback, extract func(extractor ExtractorInterface) (T, error), usable func(result T) bool) (T, error) {
+	var partial *T
+	var partialErr error
+	var lastErr error = ErrUnsupportedInput
+
+	for _, extractor := range fallback.Extractors {
+		result, err := extract(extractor)
+		if err == nil {
+			return result, nil
+		}
+		if errors.Is(err, ErrUnsupportedInput) {
+			continue
+		}
+		if errors.Is(err, ErrUnreconciled) && partial == nil && usable(result) {
+			partial, partialErr = &result, err
+		}
+		config.GeneralLogger.Printf("[Extractor Info] %T failed, trying next extractor: %v\n", extractor, err)
+		lastErr = err
+	}
+
+	if partial != nil {
+		config.GeneralLogger.Printf("[Extractor Info] All extractors failed, returning partial result: %v\n", lastErr)
+		return *partial, fmt.Errorf("all extractors failed, partial result: %w", partialErr)
+	}
+	var empty T
+	return empty, fmt.Errorf("all extractors failed: %w", lastErr)
+}
+
+func hasItems(result models.SplitbillResponse) bool {
+	return len(result.Items) > 0
+}
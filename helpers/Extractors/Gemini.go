@@ -0,0 +1,506 @@
+package extractors
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"google.golang.org/genai"
+)
+
+const receiptJSONPrompt = `Kembalikan hasilnya dalam format JSON dengan struktur berikut:
+{
+  "items": [
+    {
+      "name": "[Nama Barang 1]",
+      "price": "[Harga per Unit 1] - Jika tidak tersedia secara eksplisit sebagai kolom terpisah, hitung sebagai [Total Harga Item 1] dibagi [Kuantitas 1]. Jika pembagian menghasilkan angka tidak terbatas (misalnya, total 0 dan kuantitas 0), gunakan 0.",
+      "quantity": "[Kuantitas 1]",
+      "total": "[Total Harga Item 1]"
+    },
+    {
+      "name": "[Nama Barang 2]",
+      "price": "[Harga per Unit 2] - Jika tidak tersedia secara eksplisit sebagai kolom terpisah, hitung sebagai [Total Harga Item 2] dibagi [Kuantitas 2]. Jika pembagian menghasilkan angka tidak terbatas (misalnya, total 0 dan kuantitas 0), gunakan 0.",
+      "quantity": "[Kuantitas 2]",
+      "total": "[Total Harga Item 2]"
+    }
+    // ... (dan seterusnya untuk semua item)
+  ],
+  "store_information": {
+    "address": "[Alamat Toko]",
+    "email": "[Email Toko]",
+    "npwp": "[NPWP Toko]",
+    "phone_number": "[Nomor Telepon Toko]",
+    "store_name": "[Nama Toko]"
+  },
+  "totals": {
+    "change": "[Uang Kembali]",
+    "discount": "[Nilai Diskon/Nilai Yang Dikurangi]. Kembalikan angka desimal tanpa pengurangan. Jika tidak ada diskon, gunakan 0.",
+    "payment": "[Jumlah Pembayaran]",
+    "subtotal": "[Subtotal]",
+    "tax": {
+      "amount": "[Nilai Pajak]",
+      "service_charge": "[Biaya Layanan]",
+      "dpp": "[Dasar Pengenaan Pajak]",
+      "name": "[Nama Pajak]",
+      "total_tax": "[Total Pajak dari service_charge + amount]"
+    },
+    "total": "[Total Belanja]"
+  },
+  "transaction_information": {
+    "date": "[Tanggal Transaksi] dalam format DD/MM/YYYY",
+    "time": "[Waktu Transaksi] dalam format HH:MM",
+    "transaction_id": "[ID Transaksi]"
+  },
+  "receipt_type": "[Jenis struk: restaurant, supermarket, fuel, parking, hotel, atau other]",
+  "extensions": {
+    "restaurant": {
+      "table_number": "[Nomor Meja]",
+      "pax": "[Jumlah Tamu]",
+      "waiter": "[Nama Pelayan]"
+    },
+    "supermarket": {
+      "member_id": "[Nomor Member]",
+      "item_units": [
+        {
+          "name": "[Nama Barang yang dijual per satuan ukur, sama dengan name di items]",
+          "quantity": "[Jumlah dalam satuan ukur]",
+          "unit": "[Satuan ukur, misalnya kg, g, l, ml, pcs]"
+        }
+      ]
+    },
+    "fuel": {
+      "fuel_type": "[Jenis BBM]",
+      "liters": "[Jumlah Liter]",
+      "price_per_liter": "[Harga per Liter]",
+      "pump_number": "[Nomor Pompa]",
+      "vehicle_plate": "[Nomor Polisi Kendaraan]"
+    },
+    "parking": {
+      "entry_time": "[Jam Masuk] dalam format HH:MM",
+      "exit_time": "[Jam Keluar] dalam format HH:MM",
+      "duration": "[Lama Parkir]",
+      "vehicle_plate": "[Nomor Polisi Kendaraan]",
+      "vehicle_type": "[Jenis Kendaraan]"
+    },
+    "hotel": {
+      "guest_name": "[Nama Tamu]",
+      "room_number": "[Nomor Kamar]",
+      "check_in": "[Tanggal Check-in] dalam format DD/MM/YYYY",
+      "check_out": "[Tanggal Check-out] dalam format DD/MM/YYYY",
+      "room_nights": "[Jumlah Malam]"
+    }
+  }
+}
+
+Isi hanya satu blok extensions yang sesuai dengan receipt_type dan hilangkan blok lainnya. Untuk receipt_type other, kosongkan extensions.
+
+Pastikan semua nilai diisi sesuai dengan informasi yang tertera pada struk. Jika suatu informasi tidak ditemukan, gunakan nilai null atau string kosong untuk field yang sesuai. Untuk nilai numerik (harga, kuantitas, total, totals, discount, dll.), kembalikan dalam format desimal tanpa pemisah ribuan (misalnya, "220000.00" bukan "220,000.00").`
+
+// confidencePrompt meminta confidence per field dan per baris item di samping struktur JSON struk
+const confidencePrompt = `
+
+Tambahkan juga confidence antara 0 dan 1 yang menunjukkan seberapa yakin kamu bahwa nilai yang diekstrak sesuai dengan teks di struk. Gunakan nilai rendah untuk teks yang buram, terpotong, tertutup, atau hasil tebakan/perhitungan:
+- Pada setiap objek item, tambahkan field "confidence": {"line": [confidence baris item], "name": [confidence], "price": [confidence], "quantity": [confidence], "total": [confidence]}
+- Pada objek struk, tambahkan field "field_confidence" berisi confidence untuk setiap field yang terisi di luar items, dengan key berupa path field, misalnya {"store_information.store_name": 0.98, "transaction_information.date": 0.9, "totals.subtotal": 0.95, "totals.tax.amount": 0.6, "totals.total": 0.97}`
+
+// boundingBoxPrompt meminta lokasi teks sumber di gambar. Hanya dipakai untuk input gambar jika
+// EXTRACTION_BOUNDING_BOXES aktif karena menambah panjang jawaban model.
+const boundingBoxPrompt = `
+
+Tambahkan juga bounding box teks sumber di gambar dalam format [ymin, xmin, ymax, xmax] dengan skala 0-1000 relatif terhadap ukuran gambar:
+- Pada setiap objek item, tambahkan field "bounding_box" yang mencakup seluruh baris item
+- Pada objek struk, tambahkan field "field_bounding_boxes" dengan key yang sama seperti field_confidence, misalnya {"totals.total": [812, 540, 836, 930]}`
+
+const imagePrompt = "Tolong lakukan Optical Character Recognition (OCR) pada gambar struk ini dan ekstrak informasi belanja. " + receiptJSONPrompt + confidencePrompt
+
+const textPrompt = "Berikut adalah teks dari sebuah struk belanja. Tolong ekstrak informasi belanja dari teks tersebut. " + receiptJSONPrompt + confidencePrompt
+
+const pdfPrompt = "Dokumen PDF ini adalah struk atau invoice (misalnya folio hotel atau e-invoice maskapai) yang bisa terdiri dari beberapa halaman. Tolong baca semua halaman dan gabungkan menjadi satu struk: masukkan item dari setiap halaman tanpa duplikasi, lalu ambil subtotal, pajak, dan total dari halaman ringkasan atau halaman terakhir. " + receiptJSONPrompt + confidencePrompt
+
+const sectionsPrompt = "Gambar-gambar berikut adalah foto berurutan dari bagian-bagian satu struk panjang, dimulai dari bagian paling atas. Bagian yang berdekatan bisa tumpang tindih sehingga beberapa item terlihat di dua foto. Untuk setiap gambar, ekstrak hanya informasi yang terlihat di gambar tersebut, lalu kembalikan sebuah array JSON berisi satu objek per gambar sesuai urutan gambar. Setiap objek dalam array menggunakan struktur berikut. " + receiptJSONPrompt + confidencePrompt
+
+const multiReceiptPrompt = "Tolong lakukan Optical Character Recognition (OCR) pada gambar ini. Gambar bisa berisi satu atau beberapa struk terpisah, misalnya dua atau tiga struk yang difoto berdampingan di atas meja. Deteksi setiap struk secara terpisah dan jangan pernah menggabungkan item, informasi toko, atau total dari struk yang berbeda. Kembalikan sebuah array JSON berisi satu objek per struk, diurutkan dari kiri ke kanan lalu dari atas ke bawah. Jika hanya ada satu struk, kembalikan array berisi satu objek. Setiap objek dalam array menggunakan struktur berikut. " + receiptJSONPrompt + confidencePrompt
+
+const classifyPrompt = `Klasifikasikan gambar ini sebelum diproses. Pilih tepat satu label:
+- "receipt": struk belanja/restoran/parkir/SPBU atau bukti pembayaran berisi item atau total
+- "invoice": invoice, tagihan, atau folio hotel
+- "menu": daftar menu atau daftar harga, bukan bukti transaksi
+- "non_document": foto yang bukan dokumen (selfie, pemandangan, makanan, objek lain)
+- "unreadable": dokumen terlihat tetapi terlalu buram, gelap, terpotong, atau kecil untuk dibaca
+Berikan confidence antara 0 dan 1. Kembalikan hanya JSON tanpa teks lain dengan struktur:
+{"label": "receipt", "confidence": 0.95, "reason": "alasan singkat"}`
+
+// promptVersion berubah otomatis setiap kali salah satu prompt ekstraksi atau klasifikasi diubah,
+// sehingga hasil cache dari prompt lama tidak dipakai lagi
+var promptVersion = func() string {
+	hash := sha256.Sum256([]byte(imagePrompt + textPrompt + pdfPrompt + sectionsPrompt + multiReceiptPrompt + classifyPrompt + boundingBoxPrompt))
+	return hex.EncodeToString(hash[:])[:12]
+}()
+
+// maxInlineDataSize adalah batas ukuran data inline Gemini. File yang lebih besar diunggah lewat
+// File API dan otomatis dihapus Gemini setelah 48 jam.
+const maxInlineDataSize = 20 * 1024 * 1024
+
+// Gemini mengekstrak struk menggunakan model Gemini.
+// BoundingBoxes meminta model mengembalikan lokasi teks sumber untuk input gambar. Setiap panggilan
+// model memakai key dari Keys yang dibuat sekali saat startup dan menunggu slot dari Limiter yang
+// dipakai bersama seluruh aplikasi.
+type Gemini struct {
+	Keys          *GeminiKeyPool
+	Model         string
+	BoundingBoxes bool
+	Limiter       limits.LimiterInterface
+}
+
+func NewGemini(keys *GeminiKeyPool, limiter limits.LimiterInterface) *Gemini {
+	model := os.Getenv("GEMINI_MODEL")
+	if model == "" {
+		model = "gemini-2.0-flash"
+	}
+	boundingBoxes, _ := strconv.ParseBool(os.Getenv("EXTRACTION_BOUNDING_BOXES"))
+	return &Gemini{
+		Keys:          keys,
+		Model:         model,
+		BoundingBoxes: boundingBoxes,
+		Limiter:       limiter,
+	}
+}
+
+// Version mengembalikan model dan versi prompt yang dipakai
+func (gemini *Gemini) Version() string {
+	if gemini.BoundingBoxes {
+		return fmt.Sprintf("gemini/%s/%s/boxes", gemini.Model, promptVersion)
+	}
+	return fmt.Sprintf("gemini/%s/%s", gemini.Model, promptVersion)
+}
+
+// withBoundingBoxes menambahkan permintaan bounding box pada prompt input gambar jika diaktifkan
+func (gemini *Gemini) withBoundingBoxes(prompt string) string {
+	if gemini.BoundingBoxes {
+		return prompt + boundingBoxPrompt
+	}
+	return prompt
+}
+
+// ExtractFromImage mengekstrak struk dari gambar, atau dari PDF (termasuk PDF multi-halaman) yang
+// dikirim langsung sebagai input native ke model.
+func (gemini *Gemini) ExtractFromImage(ctx context.Context, imageData []byte, mimeType string) (models.SplitbillResponse, error) {
+	prompt := gemini.withBoundingBoxes(imagePrompt)
+	if mimeType == "application/pdf" {
+		// Koordinat bounding box tidak bisa dikaitkan ke halaman PDF, jadi hanya confidence yang diminta
+		prompt = pdfPrompt
+	}
+	return gemini.generate(ctx, prompt, func(ctx context.Context, client *genai.Client) ([]*genai.Part, error) {
+		part, err := gemini.dataPart(ctx, client, imageData, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		return []*genai.Part{part}, nil
+	})
+}
+
+// Classify menjalankan prompt klasifikasi singkat yang jauh lebih murah dari prompt ekstraksi
+func (gemini *Gemini) Classify(ctx context.Context, imageData []byte, mimeType string) (models.DocumentClass, error) {
+	// Klasifikasi bukan bagian dari ekstraksi yang dilaporkan ke trace
+	ctx = WithTrace(ctx, nil)
+	responseText, err := gemini.generateText(ctx, classifyPrompt, func(ctx context.Context, client *genai.Client) ([]*genai.Part, error) {
+		part, err := gemini.dataPart(ctx, client, imageData, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		return []*genai.Part{part}, nil
+	})
+	if err != nil {
+		return models.DocumentClass{}, err
+	}
+
+	var raw struct {
+		Label      string `json:"label"`
+		Confidence string `json:"confidence"`
+		Reason     string `json:"reason"`
+	}
+	if err := decodeModelJSON(responseText, &raw); err != nil {
+		return models.DocumentClass{}, errors.New(fmt.Sprintf("Failed to unmarshal classification: %v", err.Error()))
+	}
+	confidence, err := strconv.ParseFloat(raw.Confidence, 64)
+	if err != nil {
+		confidence = 0
+	}
+	return models.DocumentClass{
+		Label:      strings.ToLower(strings.TrimSpace(raw.Label)),
+		Confidence: confidence,
+		Reason:     raw.Reason,
+	}, nil
+}
+
+// ExtractReceiptsFromImage mendeteksi setiap struk dalam satu foto dan mengembalikan hasilnya
+// secara terpisah, bukan satu daftar item gabungan.
+func (gemini *Gemini) ExtractReceiptsFromImage(ctx context.Context, imageData []byte, mimeType string) ([]models.SplitbillResponse, error) {
+	responseText, err := gemini.generateText(ctx, gemini.withBoundingBoxes(multiReceiptPrompt), func(ctx context.Context, client *genai.Client) ([]*genai.Part, error) {
+		part, err := gemini.dataPart(ctx, client, imageData, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		return []*genai.Part{part}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	detected, err := decodeReceiptList(responseText)
+	if err != nil {
+		config.GeneralLogger.Println("\nFailed to unmarshal JSON after cleaning:")
+		return nil, errors.New(fmt.Sprintf("Failed to unmarshal JSON after cleaning: %v", err.Error()))
+	}
+
+	receiptsFound := []models.SplitbillResponse{}
+	for _, receipt := range detected {
+		if len(receipt.Items) > 0 || receipts.AmountOrZero(receipt.Totals.Total) != 0 {
+			receiptsFound = append(receiptsFound, receipt)
+		}
+	}
+	if len(receiptsFound) == 0 {
+		return nil, errors.New("no receipt found in image")
+	}
+	config.GeneralLogger.Printf("Detected %d receipt(s) in image\n", len(receiptsFound))
+	return receiptsFound, nil
+}
+
+// ExtractFromImages mengirim semua foto bagian struk dalam satu permintaan agar model melihat
+// konteks bagian yang berdekatan. Model mengembalikan hasil per bagian, lalu digabung dan
+// item yang tumpang tindih dihapus oleh receipts.MergeSections.
+func (gemini *Gemini) ExtractFromImages(ctx context.Context, images []ImageInput) (models.SplitbillResponse, error) {
+	responseText, err := gemini.generateText(ctx, gemini.withBoundingBoxes(sectionsPrompt), func(ctx context.Context, client *genai.Client) ([]*genai.Part, error) {
+		parts := []*genai.Part{}
+		for i, image := range images {
+			part, err := gemini.dataPart(ctx, client, image.Data, image.MIMEType)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, genai.NewPartFromText(fmt.Sprintf("Bagian %d:", i+1)), part)
+		}
+		return parts, nil
+	})
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+
+	sections, err := decodeReceiptList(responseText)
+	if err != nil {
+		config.GeneralLogger.Println("\nFailed to unmarshal JSON after cleaning:")
+		return models.SplitbillResponse{}, errors.New(fmt.Sprintf("Failed to unmarshal JSON after cleaning: %v", err.Error()))
+	}
+	config.GeneralLogger.Printf("Merging %d receipt sections\n", len(sections))
+	return receipts.MergeSections(sections), nil
+}
+
+func (gemini *Gemini) ExtractFromText(ctx context.Context, text string) (models.SplitbillResponse, error) {
+	return gemini.generate(ctx, textPrompt, func(ctx context.Context, client *genai.Client) ([]*genai.Part, error) {
+		return []*genai.Part{genai.NewPartFromText("Teks struk:\n" + text)}, nil
+	})
+}
+
+func (gemini *Gemini) generate(ctx context.Context, prompt string, input func(ctx context.Context, client *genai.Client) ([]*genai.Part, error)) (models.SplitbillResponse, error) {
+	responseText, err := gemini.generateText(ctx, prompt, input)
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+
+	receipt, err := DecodeReceiptJSON(responseText)
+	if err != nil {
+		config.GeneralLogger.Println("\nFailed to unmarshal JSON after cleaning:")
+		return models.SplitbillResponse{}, errors.New(fmt.Sprintf("Failed to unmarshal JSON after cleaning: %v", err.Error()))
+	}
+
+	config.GeneralLogger.Printf("Number of items: %d\n", len(receipt.Items))
+	config.GeneralLogger.Printf("Store Name: %v\n", receipt.StoreInformation.StoreName)
+	config.GeneralLogger.Printf("Total belanja: %v\n", receipt.Totals.Total)
+	config.GeneralLogger.Printf("Transaction Date: %v\n", receipt.TransactionInfo.Date)
+	return receipt, nil
+}
+
+// generateText menjalankan satu panggilan model. ctx request sudah membawa deadline sejak request
+// diterima; ctx tanpa deadline (job async) diberi EXTRACTION_TIMEOUT setelah slot didapat, sehingga
+// waktu menunggu antrean tidak ikut dihitung. Jika key mendapat error kuota, panggilan diulang
+// dengan key berikutnya sampai semua key di pool diistirahatkan.
+func (gemini *Gemini) generateText(ctx context.Context, prompt string, input func(ctx context.Context, client *genai.Client) ([]*genai.Part, error)) (string, error) {
+	if err := gemini.Keys.Err(); err != nil {
+		return "", err
+	}
+	if gemini.Limiter != nil {
+		release, err := gemini.Limiter.Acquire(ctx)
+		if err != nil {
+			return "", limits.ContextError(ctx, err)
+		}
+		defer release()
+	}
+	ctx, cancel := limits.WithDefaultDeadline(ctx)
+	defer cancel()
+
+	// Progress stream dibagi semua percobaan agar failover ke key lain tidak mengulang event
+	progress := newStreamProgress(traceFrom(ctx))
+	for attempt := 0; attempt < gemini.Keys.Size(); attempt++ {
+		key, err := gemini.Keys.acquire()
+		if err != nil {
+			return "", err
+		}
+		responseText, err := gemini.generateWithClient(ctx, key.client, prompt, input, progress)
+		if gemini.Keys.report(key, err) {
+			continue
+		}
+		if err != nil {
+			return "", limits.ContextError(ctx, err)
+		}
+		return responseText, nil
+	}
+	return "", gemini.Keys.exhaustedError()
+}
+
+// generateWithClient menjalankan panggilan model dengan client milik satu key. Jika progress tidak
+// nil, jawaban model dibaca sebagai stream.
+func (gemini *Gemini) generateWithClient(ctx context.Context, client *genai.Client, prompt string, input func(ctx context.Context, client *genai.Client) ([]*genai.Part, error), progress *streamProgress) (string, error) {
+	inputParts, err := input(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	parts := append([]*genai.Part{genai.NewPartFromText(prompt)}, inputParts...)
+	contents := []*genai.Content{
+		genai.NewContentFromParts(parts, genai.RoleUser),
+	}
+
+	var responseText string
+	if progress != nil {
+		responseText, err = gemini.generateStream(ctx, client, contents, progress)
+	} else {
+		var result *genai.GenerateContentResponse
+		result, err = client.Models.GenerateContent(ctx, gemini.Model, contents, nil)
+		if result != nil {
+			responseText = result.Text()
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to generate content: %w", err)
+	}
+
+	config.GeneralLogger.Println("Raw response from Gemini:")
+	config.GeneralLogger.Println(responseText)
+	return responseText, nil
+}
+
+// generateStream memakai streaming generation API sehingga item yang sudah lengkap bisa dilaporkan
+// ke trace sebelum model selesai menjawab
+func (gemini *Gemini) generateStream(ctx context.Context, client *genai.Client, contents []*genai.Content, progress *streamProgress) (string, error) {
+	progress.modelStarted()
+	var responseText strings.Builder
+	for chunk, err := range client.Models.GenerateContentStream(ctx, gemini.Model, contents, nil) {
+		if err != nil {
+			return "", err
+		}
+		responseText.WriteString(chunk.Text())
+		progress.items(responseText.String())
+	}
+	return responseText.String(), nil
+}
+
+// dataPart mengirim data inline, atau lewat File API jika melebihi batas ukuran inline
+func (gemini *Gemini) dataPart(ctx context.Context, client *genai.Client, data []byte, mimeType string) (*genai.Part, error) {
+	if len(data) <= maxInlineDataSize {
+		return genai.NewPartFromBytes(data, mimeType), nil
+	}
+	file, err := client.Files.Upload(ctx, bytes.NewReader(data), &genai.UploadFileConfig{MIMEType: mimeType})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload file to Gemini: %w", err)
+	}
+	return genai.NewPartFromURI(file.URI, file.MIMEType), nil
+}
+
+// DecodeReceiptJSON membersihkan pembungkus markdown dari jawaban model lalu mengurai JSON-nya.
+// Angka yang dikembalikan model tanpa tanda kutip tetap diterima dan diubah menjadi string.
+func DecodeReceiptJSON(responseText string) (models.SplitbillResponse, error) {
+	var receipt models.SplitbillResponse
+	if err := decodeModelJSON(responseText, &receipt); err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	return receipts.NormalizeConfidence(normalizeReceiptType(receipt)), nil
+}
+
+// decodeReceiptList mengurai array struk dari jawaban model. Model kadang langsung mengembalikan
+// satu objek, bukan array, sehingga objek tunggal juga diterima.
+func decodeReceiptList(responseText string) ([]models.SplitbillResponse, error) {
+	var list []models.SplitbillResponse
+	if err := decodeModelJSON(responseText, &list); err != nil {
+		receipt, errSingle := DecodeReceiptJSON(responseText)
+		if errSingle != nil {
+			return nil, err
+		}
+		list = []models.SplitbillResponse{receipt}
+	}
+	for i := range list {
+		list[i] = receipts.NormalizeConfidence(normalizeReceiptType(list[i]))
+	}
+	return list, nil
+}
+
+// normalizeReceiptType merapikan receipt_type dari model dan membuang blok extensions yang tidak
+// sesuai dengan jenis struk.
+func normalizeReceiptType(receipt models.SplitbillResponse) models.SplitbillResponse {
+	receipt.ReceiptType = strings.ToLower(strings.TrimSpace(receipt.ReceiptType))
+	switch receipt.ReceiptType {
+	case models.ReceiptTypeRestaurant, models.ReceiptTypeSupermarket, models.ReceiptTypeFuel, models.ReceiptTypeParking, models.ReceiptTypeHotel:
+	default:
+		receipt.ReceiptType = models.ReceiptTypeOther
+	}
+	receipt.Extensions = receipts.KeepTypeExtension(receipt.ReceiptType, receipt.Extensions)
+	return receipt
+}
+
+func decodeModelJSON(responseText string, out any) error {
+	cleanedJSON := strings.TrimSpace(responseText)
+	cleanedJSON = strings.TrimPrefix(cleanedJSON, "```json")
+	cleanedJSON = strings.TrimPrefix(cleanedJSON, "```")
+	cleanedJSON = strings.TrimSuffix(cleanedJSON, "```")
+	cleanedJSON = strings.TrimSpace(cleanedJSON)
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(cleanedJSON)))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalized, err := json.Marshal(stringifyNumbers(raw))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, out)
+}
+
+func stringifyNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = stringifyNumbers(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = stringifyNumbers(child)
+		}
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return v
+	}
+}
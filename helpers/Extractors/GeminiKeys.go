@@ -0,0 +1,227 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/genai"
+)
+
+// geminiKey adalah satu API key di pool beserta client genai dan penghitung pemakaiannya
+type geminiKey struct {
+	id           string
+	suffix       string
+	client       *genai.Client
+	benchedUntil time.Time
+	lastUsedAt   time.Time
+	requests     int64
+	succeeded    int64
+	failed       int64
+	quotaErrors  int64
+	benches      int64
+}
+
+// GeminiKeyPool menyimpan satu client genai per API key yang dibuat sekali saat startup dan dipakai
+// bersama oleh extractor dan classifier. Key dipilih bergiliran (round-robin); key yang mendapat
+// 429 atau error kuota diistirahatkan selama Cooldown dan panggilan dicoba lagi dengan key berikutnya.
+type GeminiKeyPool struct {
+	Cooldown time.Duration
+
+	mutex sync.Mutex
+	keys  []*geminiKey
+	next  int
+	err   error
+}
+
+// NewGeminiKeyPool membaca daftar API key dari GEMINI_API_KEYS (dipisah koma) dan GEMINI_API_KEY,
+// serta lama istirahat key dari GEMINI_KEY_COOLDOWN (default 1m). Semua client memakai satu
+// http.Client sehingga koneksi ke Gemini dipakai ulang. Jika tidak ada key yang bisa dipakai,
+// aplikasi tetap berjalan dan setiap panggilan model mengembalikan error pembuatan client.
+func NewGeminiKeyPool() *GeminiKeyPool {
+	pool := &GeminiKeyPool{Cooldown: geminiKeyCooldown()}
+	httpClient := &http.Client{}
+	for _, apiKey := range geminiAPIKeys() {
+		client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+			APIKey:     apiKey,
+			Backend:    genai.BackendGeminiAPI,
+			HTTPClient: httpClient,
+		})
+		id := fmt.Sprintf("key-%d", len(pool.keys)+1)
+		if err != nil {
+			config.GeneralLogger.Printf("Failed to create Gemini client for %s: %v\n", id, err.Error())
+			pool.err = errors.New(fmt.Sprintf("Failed to create client: %v", err.Error()))
+			continue
+		}
+		pool.keys = append(pool.keys, &geminiKey{id: id, suffix: keySuffix(apiKey), client: client})
+	}
+	if len(pool.keys) > 0 {
+		pool.err = nil
+		config.GeneralLogger.Printf("Gemini key pool ready with %d key(s)\n", len(pool.keys))
+	} else if pool.err == nil {
+		pool.err = errors.New("Failed to create client: GEMINI_API_KEY or GEMINI_API_KEYS is not set")
+	}
+	return pool
+}
+
+// Size mengembalikan jumlah key di pool
+func (pool *GeminiKeyPool) Size() int {
+	if pool == nil {
+		return 0
+	}
+	return len(pool.keys)
+}
+
+// Err mengembalikan error pembuatan client jika tidak ada key yang bisa dipakai
+func (pool *GeminiKeyPool) Err() error {
+	if pool == nil {
+		return errors.New("Gemini client is not configured")
+	}
+	return pool.err
+}
+
+// acquire memilih key berikutnya yang tidak sedang diistirahatkan. Jika semua key diistirahatkan,
+// error berupa *helpers.ApiError dari exhaustedError.
+func (pool *GeminiKeyPool) acquire() (*geminiKey, error) {
+	if err := pool.Err(); err != nil {
+		return nil, err
+	}
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(pool.keys); i++ {
+		key := pool.keys[(pool.next+i)%len(pool.keys)]
+		if now.Before(key.benchedUntil) {
+			continue
+		}
+		pool.next = (pool.next + i + 1) % len(pool.keys)
+		key.requests++
+		key.lastUsedAt = now
+		return key, nil
+	}
+	return nil, pool.quotaExhausted(now)
+}
+
+// exhaustedError mengembalikan ApiError 503 model_quota_exhausted dengan Retry-After sampai key
+// pertama selesai diistirahatkan
+func (pool *GeminiKeyPool) exhaustedError() error {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	return pool.quotaExhausted(time.Now())
+}
+
+func (pool *GeminiKeyPool) quotaExhausted(now time.Time) error {
+	retryAfter := pool.Cooldown
+	for _, key := range pool.keys {
+		if wait := key.benchedUntil.Sub(now); wait > 0 && wait < retryAfter {
+			retryAfter = wait
+		}
+	}
+	return helpers.NewApiError(fiber.StatusServiceUnavailable, helpers.ErrCodeModelQuotaExhausted,
+		fmt.Sprintf("all %d Gemini API key(s) have exhausted their quota, please retry later", len(pool.keys)), nil).WithRetryAfter(retryAfter)
+}
+
+// report mencatat hasil panggilan model dengan key. Key yang mendapat error kuota diistirahatkan
+// selama Cooldown; report mengembalikan true jika itu terjadi.
+func (pool *GeminiKeyPool) report(key *geminiKey, err error) bool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if err == nil {
+		key.succeeded++
+		return false
+	}
+	key.failed++
+	if !isQuotaError(err) {
+		return false
+	}
+	key.quotaErrors++
+	key.benches++
+	key.benchedUntil = time.Now().Add(pool.Cooldown)
+	config.GeneralLogger.Printf("Gemini %s hit its quota, benched for %v: %v\n", key.id, pool.Cooldown, err.Error())
+	return true
+}
+
+// Metrics mengembalikan penghitung pemakaian setiap key. API key tidak pernah dikembalikan utuh,
+// hanya 4 karakter terakhirnya.
+func (pool *GeminiKeyPool) Metrics() []models.GeminiKeyMetrics {
+	metrics := []models.GeminiKeyMetrics{}
+	if pool == nil {
+		return metrics
+	}
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	now := time.Now()
+	for _, key := range pool.keys {
+		keyMetrics := models.GeminiKeyMetrics{
+			ID:          key.id,
+			Key:         "..." + key.suffix,
+			Requests:    key.requests,
+			Succeeded:   key.succeeded,
+			Failed:      key.failed,
+			QuotaErrors: key.quotaErrors,
+			Benches:     key.benches,
+			Benched:     now.Before(key.benchedUntil),
+		}
+		if keyMetrics.Benched {
+			benchedUntil := key.benchedUntil
+			keyMetrics.BenchedUntil = &benchedUntil
+		}
+		if !key.lastUsedAt.IsZero() {
+			lastUsedAt := key.lastUsedAt
+			keyMetrics.LastUsedAt = &lastUsedAt
+		}
+		metrics = append(metrics, keyMetrics)
+	}
+	return metrics
+}
+
+// isQuotaError bernilai true untuk error 429 atau RESOURCE_EXHAUSTED dari Gemini
+func isQuotaError(err error) bool {
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == http.StatusTooManyRequests ||
+		apiErr.Status == "RESOURCE_EXHAUSTED" ||
+		strings.Contains(strings.ToLower(apiErr.Message), "quota")
+}
+
+// geminiAPIKeys menggabungkan GEMINI_API_KEYS dan GEMINI_API_KEY tanpa duplikat
+func geminiAPIKeys() []string {
+	apiKeys := []string{}
+	seen := map[string]bool{}
+	for _, apiKey := range append(strings.Split(os.Getenv("GEMINI_API_KEYS"), ","), os.Getenv("GEMINI_API_KEY")) {
+		apiKey = strings.TrimSpace(apiKey)
+		if apiKey == "" || seen[apiKey] {
+			continue
+		}
+		seen[apiKey] = true
+		apiKeys = append(apiKeys, apiKey)
+	}
+	return apiKeys
+}
+
+func keySuffix(apiKey string) string {
+	if len(apiKey) <= 4 {
+		return ""
+	}
+	return apiKey[len(apiKey)-4:]
+}
+
+func geminiKeyCooldown() time.Duration {
+	cooldown, err := time.ParseDuration(os.Getenv("GEMINI_KEY_COOLDOWN"))
+	if err != nil || cooldown <= 0 {
+		return time.Minute
+	}
+	return cooldown
+}
@@ -0,0 +1,97 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/genai"
+)
+
+func TestGeminiKeyPoolRoundRobin(t *testing.T) {
+	gemini, fake := newTestGemini(t, validKey+"-1", validKey+"-2")
+	for i := 0; i < 4; i++ {
+		if _, err := gemini.ExtractFromText(context.Background(), "Teh 5000"); err != nil {
+			t.Fatalf("ExtractFromText() error = %v", err)
+		}
+	}
+	if fake.requestCount(validKey+"-1") != 2 || fake.requestCount(validKey+"-2") != 2 {
+		t.Errorf("requests = %v, want 2 per key", fake.requests)
+	}
+	for _, metrics := range gemini.Keys.Metrics() {
+		if metrics.Requests != 2 || metrics.Succeeded != 2 {
+			t.Errorf("%s metrics = %+v, want 2 requests and 2 successes", metrics.ID, metrics)
+		}
+	}
+}
+
+// TestGeminiKeyPoolQuotaStream memastikan key yang mendapat error kuota saat stream diistirahatkan
+// dan tidak dipakai lagi selama Cooldown
+func TestGeminiKeyPoolQuotaStream(t *testing.T) {
+	gemini, fake := newTestGemini(t, quotaKey, validKey)
+	for i := 0; i < 3; i++ {
+		recorder := &recordingTrace{}
+		if _, err := gemini.ExtractFromText(WithTrace(context.Background(), recorder.trace()), "Teh 5000"); err != nil {
+			t.Fatalf("ExtractFromText() error = %v", err)
+		}
+	}
+	if fake.requestCount(quotaKey) != 1 || fake.requestCount(validKey) != 3 {
+		t.Errorf("requests = %v, want the quota key to be used once", fake.requests)
+	}
+
+	metrics := gemini.Keys.Metrics()
+	if quota := metrics[0]; !quota.Benched || quota.BenchedUntil == nil || quota.QuotaErrors != 1 || quota.Failed != 1 {
+		t.Errorf("quota key metrics = %+v, want benched after one quota error", quota)
+	}
+	if valid := metrics[1]; valid.Benched || valid.Succeeded != 3 {
+		t.Errorf("valid key metrics = %+v, want 3 successes", valid)
+	}
+}
+
+func TestGeminiKeyPoolExhausted(t *testing.T) {
+	gemini, fake := newTestGemini(t, quotaKey+"-1", quotaKey+"-2")
+	for i := 0; i < 2; i++ {
+		_, err := gemini.ExtractFromText(context.Background(), "Teh 5000")
+		var apiError *helpers.ApiError
+		if !errors.As(err, &apiError) {
+			t.Fatalf("ExtractFromText() error = %v, want *helpers.ApiError", err)
+		}
+		if apiError.Status != fiber.StatusServiceUnavailable || apiError.Code != helpers.ErrCodeModelQuotaExhausted {
+			t.Errorf("ExtractFromText() error = %d %s, want 503 %s", apiError.Status, apiError.Code, helpers.ErrCodeModelQuotaExhausted)
+		}
+		if apiError.RetryAfter <= 0 || apiError.RetryAfter > gemini.Keys.Cooldown {
+			t.Errorf("RetryAfter = %v, want between 0 and %v", apiError.RetryAfter, gemini.Keys.Cooldown)
+		}
+	}
+
+	// Panggilan kedua ditolak tanpa mengirim request karena semua key sedang diistirahatkan
+	if fake.requestCount(quotaKey+"-1") != 1 || fake.requestCount(quotaKey+"-2") != 1 {
+		t.Errorf("requests = %v, want one per key", fake.requests)
+	}
+}
+
+func TestIsQuotaError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "429", err: genai.APIError{Code: http.StatusTooManyRequests}, want: true},
+		{name: "resource exhausted", err: genai.APIError{Code: http.StatusForbidden, Status: "RESOURCE_EXHAUSTED"}, want: true},
+		{name: "quota message", err: genai.APIError{Code: http.StatusBadRequest, Message: "Quota exceeded for project"}, want: true},
+		{name: "wrapped", err: errors.Join(errors.New("Failed to generate content"), genai.APIError{Code: http.StatusTooManyRequests}), want: true},
+		{name: "server error", err: genai.APIError{Code: http.StatusInternalServerError, Status: "INTERNAL"}},
+		{name: "not an api error", err: errors.New("quota")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isQuotaError(test.err); got != test.want {
+				t.Errorf("isQuotaError(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genai"
+)
+
+const (
+	quotaKey = "test-key-quota"
+	validKey = "test-key-valid"
+)
+
+// testReceiptJSON adalah jawaban model yang dikirim fake Gemini, dipotong menjadi beberapa chunk
+const testReceiptJSON = `{"items":[{"name":"Teh","quantity":"1","price":"5000","total":"5000"},{"name":"Nasi","quantity":"1","price":"25000","total":"25000"}],"store_information":{"store_name":"Toko Maju"},"totals":{"total":"30000"}}`
+
+// fakeGemini meniru endpoint generateContent dan streamGenerateContent Gemini API. Key berawalan
+// quotaKey selalu mendapat 429 RESOURCE_EXHAUSTED; key lain mendapat testReceiptJSON.
+type fakeGemini struct {
+	mutex    sync.Mutex
+	requests map[string]int
+}
+
+func (fake *fakeGemini) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("x-goog-api-key")
+	fake.mutex.Lock()
+	fake.requests[key]++
+	fake.mutex.Unlock()
+
+	if strings.HasPrefix(key, quotaKey) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"error":{"code":429,"message":"Resource has been exhausted (e.g. check quota).","status":"RESOURCE_EXHAUSTED"}}`)
+		return
+	}
+	if !strings.Contains(r.URL.Path, ":streamGenerateContent") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(modelResponse(testReceiptJSON))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	for offset := 0; offset < len(testReceiptJSON); offset += 40 {
+		chunk, _ := json.Marshal(modelResponse(testReceiptJSON[offset:min(offset+40, len(testReceiptJSON))]))
+		fmt.Fprintf(w, "data: %s\n\n", chunk)
+	}
+}
+
+func (fake *fakeGemini) requestCount(key string) int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.requests[key]
+}
+
+func modelResponse(text string) map[string]any {
+	return map[string]any{
+		"candidates": []any{
+			map[string]any{"content": map[string]any{"role": "model", "parts": []any{map[string]any{"text": text}}}},
+		},
+	}
+}
+
+// newTestGemini menjalankan fakeGemini dan membuat extractor dengan pool berisi apiKeys sesuai urutan
+func newTestGemini(t *testing.T, apiKeys ...string) (*Gemini, *fakeGemini) {
+	t.Helper()
+	if config.GeneralLogger == nil {
+		config.GeneralLogger = logrus.New()
+		config.GeneralLogger.SetOutput(io.Discard)
+	}
+	fake := &fakeGemini{requests: map[string]int{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	pool := &GeminiKeyPool{Cooldown: time.Minute}
+	for _, apiKey := range apiKeys {
+		client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+			APIKey:      apiKey,
+			Backend:     genai.BackendGeminiAPI,
+			HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+		})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		pool.keys = append(pool.keys, &geminiKey{id: fmt.Sprintf("key-%d", len(pool.keys)+1), suffix: keySuffix(apiKey), client: client})
+	}
+	return &Gemini{Keys: pool, Model: "gemini-test"}, fake
+}
+
+// recordingTrace mencatat event trace sesuai urutan pemanggilan
+type recordingTrace struct {
+	events []string
+}
+
+func (recorder *recordingTrace) trace() *Trace {
+	return &Trace{
+		ModelStarted: func() { recorder.events = append(recorder.events, "model_started") },
+		Item:         func(item models.Item) { recorder.events = append(recorder.events, "item:"+item.Name) },
+	}
+}
+
+func TestGeminiStreamFailover(t *testing.T) {
+	gemini, fake := newTestGemini(t, quotaKey, validKey)
+	recorder := &recordingTrace{}
+	receipt, err := gemini.ExtractFromText(WithTrace(context.Background(), recorder.trace()), "Teh 5000")
+	if err != nil {
+		t.Fatalf("ExtractFromText() error = %v", err)
+	}
+	if receipt.Totals.Total != "30000" || len(receipt.Items) != 2 {
+		t.Errorf("receipt = %+v, want 2 items with total 30000", receipt)
+	}
+
+	// Key pertama ditolak karena kuota dan panggilan diulang dengan key kedua dalam satu stream
+	want := []string{"model_started", "item:Teh", "item:Nasi"}
+	if !slices.Equal(recorder.events, want) {
+		t.Errorf("trace events = %v, want %v", recorder.events, want)
+	}
+	if fake.requestCount(quotaKey) != 1 || fake.requestCount(validKey) != 1 {
+		t.Errorf("requests = %v, want one per key", fake.requests)
+	}
+}
+
+// TestStreamProgressRetry memastikan jawaban ulang setelah failover di tengah stream hanya
+// melaporkan item yang belum pernah dilaporkan, dan model_started hanya sekali
+func TestStreamProgressRetry(t *testing.T) {
+	recorder := &recordingTrace{}
+	progress := newStreamProgress(recorder.trace())
+
+	// Percobaan pertama terputus setelah item pertama selesai diurai
+	progress.modelStarted()
+	partial := testReceiptJSON[:strings.Index(testReceiptJSON, `{"name":"Nasi"`)+10]
+	progress.items(partial)
+
+	// Percobaan kedua dengan key lain menjawab dari awal
+	progress.modelStarted()
+	for end := 10; end <= len(testReceiptJSON); end += 10 {
+		progress.items(testReceiptJSON[:end])
+	}
+	progress.items(testReceiptJSON)
+
+	want := []string{"model_started", "item:Teh", "item:Nasi"}
+	if !slices.Equal(recorder.events, want) {
+		t.Errorf("trace events = %v, want %v", recorder.events, want)
+	}
+}
+
+func TestNewStreamProgressWithoutTrace(t *testing.T) {
+	if progress := newStreamProgress(nil); progress != nil {
+		t.Errorf("newStreamProgress(nil) = %+v, want nil so the model is called without streaming", progress)
+	}
+}
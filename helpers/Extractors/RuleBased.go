@@ -0,0 +1,364 @@
+package extractors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// RuleBased mengurai teks struk (hasil OCR atau teks tempelan) secara deterministik tanpa memanggil AI.
+type RuleBased struct {
+}
+
+func (ruleBased *RuleBased) ExtractFromImage(ctx context.Context, imageData []byte, mimeType string) (models.SplitbillResponse, error) {
+	return models.SplitbillResponse{}, ErrUnsupportedInput
+}
+
+func (ruleBased *RuleBased) ExtractReceiptsFromImage(ctx context.Context, imageData []byte, mimeType string) ([]models.SplitbillResponse, error) {
+	return nil, ErrUnsupportedInput
+}
+
+func (ruleBased *RuleBased) ExtractFromImages(ctx context.Context, images []ImageInput) (models.SplitbillResponse, error) {
+	return models.SplitbillResponse{}, ErrUnsupportedInput
+}
+
+func (ruleBased *RuleBased) ExtractFromText(ctx context.Context, text string) (models.SplitbillResponse, error) {
+	return ParseReceiptText(text)
+}
+
+var (
+	reAmount       = regexp.MustCompile(`^\(?-?(?:RP\.?\s*)?-?\d[\d.,]*\)?-?$`)
+	reTrailAmount  = regexp.MustCompile(`^(.*?)[\s:]+\(?(-?(?:RP\.?\s*)?-?\d[\d.,]*)\)?-?$`)
+	reQtyPrice     = regexp.MustCompile(`^(?:(.*?)\s+)?(\d+(?:[.,]\d+)?)\s*(?:(KG|GR|G|LTR|L|ML|PCS|PC)\s*)?[X@]\s*(?:RP\.?\s*)?(\d[\d.,]*)(?:\s+(?:RP\.?\s*)?(\d[\d.,]*))?$`)
+	reQtyPrefix    = regexp.MustCompile(`^(\d+)\s*[X×]\s+(.+?)\s+(?:RP\.?\s*)?(\d[\d.,]*)$`)
+	reQtyPriceSum  = regexp.MustCompile(`^(.+?)\s+(\d{1,3})\s+(\d[\d.,]*)\s+(\d[\d.,]*)$`)
+	reDateDMY      = regexp.MustCompile(`\b(\d{1,2})[/\-.](\d{1,2})[/\-.](\d{4}|\d{2})\b`)
+	reDateYMD      = regexp.MustCompile(`\b(\d{4})[/\-.](\d{1,2})[/\-.](\d{1,2})\b`)
+	reDateText     = regexp.MustCompile(`\b(\d{1,2})[\s\-]+(JAN|FEB|MAR|APR|MEI|MAY|JUN|JUL|AGU|AGS|AUG|SEP|OKT|OCT|NOV|DES|DEC)[A-Z]*[\s\-]+(\d{4}|\d{2})\b`)
+	reTime         = regexp.MustCompile(`\b([01]?\d|2[0-3]):([0-5]\d)(?::[0-5]\d)?\b`)
+	reTransaction  = regexp.MustCompile(`\b(?:NO\.?\s*(?:TRANS(?:AKSI)?|STRUK|NOTA|BILL|INV(?:OICE)?|ORDER|TRX|REF)|INVOICE|BILL\s*NO|TRX\s*ID|TRANS(?:AKSI)?\s*ID|ORDER\s*ID|REF\b)\s*[:#.]?\s*([A-Z0-9][A-Z0-9\-/.]{3,})`)
+	reEmail        = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	rePhone        = regexp.MustCompile(`\b(?:TELP|TLP|TEL|PHONE|HP|WA|NO\.?\s*TELP)\b\.?\s*:?\s*([+\d][\d\s\-()]{5,})`)
+	reNPWP         = regexp.MustCompile(`\bNPWP\b\.?\s*:?\s*([\d.\-]{10,})`)
+	reLetters      = regexp.MustCompile(`[A-Z]{2,}`)
+	reKembali      = regexp.MustCompile(`\b(KEMBALI|KEMBALIAN|CHANGE)\b`)
+	reSubtotal     = regexp.MustCompile(`\bSUB[\s\-]?TOTAL\b`)
+	reDiskon       = regexp.MustCompile(`\b(DISKON|DISC|DISCOUNT|POTONGAN|HEMAT|VOUCHER|PROMO)\b`)
+	reService      = regexp.MustCompile(`\b(SERVICE|SERVIS|SVC|S\.?CHARGE|ONGKIR|ONGKOS\s+KIRIM|DELIVERY|PLATFORM\s+FEE|BIAYA\s+(LAYANAN|PENGIRIMAN|PENANGANAN|KIRIM|ADMIN|APLIKASI|KEMASAN))\b`)
+	reDPP          = regexp.MustCompile(`\bDPP\b`)
+	reTax          = regexp.MustCompile(`\b(PPN|PB1|PB-1|PAJAK|TAX)\b`)
+	reTotal        = regexp.MustCompile(`\b(GRAND\s*TOTAL|TOTAL)\b`)
+	reTotalIgnored = regexp.MustCompile(`\bTOTAL\s*(ITEM|ITEMS|QTY|JUMLAH|PCS)\b`)
+	rePayment      = regexp.MustCompile(`\b(TUNAI|CASH|BAYAR|PEMBAYARAN|DEBIT|KREDIT|CREDIT|KARTU|QRIS|GOPAY|OVO|DANA|SHOPEEPAY|EDC)\b`)
+)
+
+var indonesianMonths = map[string]string{
+	"JAN": "01", "FEB": "02", "MAR": "03", "APR": "04", "MEI": "05", "MAY": "05",
+	"JUN": "06", "JUL": "07", "AGU": "08", "AGS": "08", "AUG": "08", "SEP": "09",
+	"OKT": "10", "OCT": "10", "NOV": "11", "DES": "12", "DEC": "12",
+}
+
+// ParseReceiptText mengubah teks struk Indonesia menjadi SplitbillResponse.
+// Jika item, subtotal, pajak dan total tidak bisa dicocokkan, hasil parsial tetap dikembalikan
+// bersama ErrUnreconciled.
+func ParseReceiptText(text string) (models.SplitbillResponse, error) {
+	var receipt models.SplitbillResponse
+	receipt.Items = []models.Item{}
+
+	var (
+		subtotal, discount, taxAmount, service, dpp, total, payment, change float64
+		hasSubtotal, hasTotal, hasPayment, hasChange                        bool
+		itemsStarted, itemsEnded                                            bool
+		pendingName                                                         string
+		addressLines                                                        []string
+	)
+
+	for _, rawLine := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		line := strings.Join(strings.Fields(rawLine), " ")
+		if line == "" || isSeparatorLine(line) {
+			continue
+		}
+		upper := strings.ToUpper(line)
+		if len(upper) != len(line) {
+			line = upper
+		}
+
+		if email := reEmail.FindString(line); email != "" && receipt.StoreInformation.Email == "" {
+			receipt.StoreInformation.Email = email
+			continue
+		}
+		if m := reNPWP.FindStringSubmatch(upper); m != nil {
+			receipt.StoreInformation.NPWP = strings.TrimSpace(m[1])
+			continue
+		}
+		if m := rePhone.FindStringSubmatch(upper); m != nil && !itemsStarted {
+			receipt.StoreInformation.PhoneNumber = strings.TrimSpace(m[1])
+			continue
+		}
+
+		foundDateOrTime := false
+		if receipt.TransactionInfo.Date == "" {
+			if date := parseDate(upper); date != "" {
+				receipt.TransactionInfo.Date = date
+				foundDateOrTime = true
+			}
+		}
+		if receipt.TransactionInfo.Time == "" {
+			if m := reTime.FindStringSubmatch(upper); m != nil {
+				receipt.TransactionInfo.Time = fmt.Sprintf("%02s:%s", m[1], m[2])
+				foundDateOrTime = true
+			}
+		}
+		if foundDateOrTime {
+			itemsStarted = true
+		}
+		if m := reTransaction.FindStringSubmatch(upper); m != nil && receipt.TransactionInfo.TransactionID == "" {
+			receipt.TransactionInfo.TransactionID = m[1]
+			continue
+		}
+		if foundDateOrTime {
+			continue
+		}
+		if receipts.IsTypeDetailLine(upper) {
+			// Nomor meja, nomor pompa, jam masuk parkir, nomor kamar dan sejenisnya dibaca oleh ExtractTypeDetails
+			continue
+		}
+
+		if label, amount, ok := splitTrailingAmount(upper); ok && isSummaryLabel(label) {
+			// Diskon per item bisa muncul di tengah daftar item, jadi tidak mengakhiri daftar item
+			itemsStarted = true
+			itemsEnded = itemsEnded || !reDiskon.MatchString(label)
+			switch {
+			case reKembali.MatchString(label):
+				change, hasChange = math.Abs(amount), true
+			case reSubtotal.MatchString(label):
+				subtotal, hasSubtotal = amount, true
+			case reDiskon.MatchString(label):
+				discount += math.Abs(amount)
+			case reService.MatchString(label):
+				service += amount
+			case reDPP.MatchString(label):
+				dpp = amount
+			case reTax.MatchString(label):
+				taxAmount += amount
+				receipt.Totals.Tax.Name = taxName(label)
+			case reTotal.MatchString(label):
+				if !reTotalIgnored.MatchString(label) {
+					total, hasTotal = amount, true
+				}
+			case rePayment.MatchString(label):
+				payment += amount
+				hasPayment = true
+			}
+			continue
+		}
+
+		if itemsEnded {
+			continue
+		}
+
+		if item, ok := parseItemLine(upper, line, pendingName, itemsStarted); ok {
+			receipt.Items = append(receipt.Items, item)
+			itemsStarted = true
+			pendingName = ""
+			continue
+		}
+
+		if !reLetters.MatchString(upper) {
+			continue
+		}
+		if !itemsStarted {
+			if receipt.StoreInformation.StoreName == "" {
+				receipt.StoreInformation.StoreName = line
+			} else {
+				addressLines = append(addressLines, line)
+			}
+			continue
+		}
+		// Baris teks tanpa harga biasanya nama item yang harganya ada di baris berikutnya
+		pendingName = line
+	}
+
+	receipt.StoreInformation.Address = strings.Join(addressLines, ", ")
+
+	itemsSum := 0.0
+	for _, item := range receipt.Items {
+		itemsSum += receipts.AmountOrZero(item.Total)
+	}
+	if !hasSubtotal && len(receipt.Items) > 0 {
+		subtotal = itemsSum
+	}
+	if !hasTotal {
+		total = subtotal - discount + taxAmount + service
+	}
+	if hasPayment && !hasChange && payment > total {
+		change = payment - total
+	}
+
+	receipt.Totals.Subtotal = receipts.FormatAmount(subtotal)
+	receipt.Totals.Discount = receipts.FormatAmount(discount)
+	receipt.Totals.Total = receipts.FormatAmount(total)
+	receipt.Totals.Payment = receipts.FormatAmount(payment)
+	receipt.Totals.Change = receipts.FormatAmount(change)
+	receipt.Totals.Tax.Amount = receipts.FormatAmount(taxAmount)
+	receipt.Totals.Tax.ServiceCharge = receipts.FormatAmount(service)
+	receipt.Totals.Tax.TotalTax = receipts.FormatAmount(taxAmount + service)
+	if dpp > 0 {
+		receipt.Totals.Tax.DPP = receipts.FormatAmount(dpp)
+	}
+
+	receipt.ReceiptType = receipts.DetectReceiptType(text)
+	receipt.Extensions = receipts.ExtractTypeDetails(receipt.ReceiptType, text)
+	if receipt.Extensions != nil && receipt.Extensions.Parking != nil && receipt.Extensions.Parking.ExitTime != "" {
+		// Tiket parkir dibayar saat keluar, bukan saat jam masuk yang tercetak lebih dulu
+		receipt.TransactionInfo.Time = receipt.Extensions.Parking.ExitTime
+	}
+
+	if len(receipt.Items) == 0 {
+		return receipt, fmt.Errorf("%w: no line items found", ErrUnreconciled)
+	}
+	if !hasTotal {
+		return receipt, fmt.Errorf("%w: no total found", ErrUnreconciled)
+	}
+	if validation := receipts.Validate(receipt); !validation.Valid {
+		return receipt, fmt.Errorf("%w: %s", ErrUnreconciled, strings.Join(validation.Issues, "; "))
+	}
+	return receipt, nil
+}
+
+// parseItemLine mengenali baris item "NAMA QTY x HARGA [TOTAL]", "QTY x NAMA TOTAL" (format
+// ringkasan pesanan GoFood/GrabFood), "NAMA QTY HARGA TOTAL" dan "NAMA TOTAL". Sebelum daftar item dimulai, nominal wajib memakai pemisah ribuan agar
+// nomor pada alamat tidak terbaca sebagai harga.
+func parseItemLine(upper, original, pendingName string, itemsStarted bool) (models.Item, bool) {
+	if m := reQtyPrice.FindStringSubmatch(upper); m != nil {
+		name := strings.TrimSpace(original[:len(m[1])])
+		if name == "" {
+			name = pendingName
+		}
+		qty, okQty := receipts.ParseAmount(m[2])
+		if m[3] != "" {
+			// Kuantitas bersatuan ukur (liter, kg) memakai koma sebagai pemisah desimal: "20,500 L"
+			parsed, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", "."), 64)
+			qty, okQty = parsed, err == nil
+		}
+		price, okPrice := receipts.ParseAmount(m[4])
+		if name == "" || !okQty || !okPrice || qty <= 0 {
+			return models.Item{}, false
+		}
+		lineTotal := qty * price
+		if m[5] != "" {
+			if parsed, ok := receipts.ParseAmount(m[5]); ok {
+				lineTotal = parsed
+			}
+		}
+		return newItem(name, qty, price, lineTotal), true
+	}
+
+	if m := reQtyPrefix.FindStringSubmatchIndex(upper); m != nil && reLetters.MatchString(upper[m[4]:m[5]]) {
+		qty, okQty := receipts.ParseAmount(upper[m[2]:m[3]])
+		lineTotal, okTotal := receipts.ParseAmount(upper[m[6]:m[7]])
+		if okQty && okTotal && qty > 0 {
+			return newItem(original[m[4]:m[5]], qty, lineTotal/qty, lineTotal), true
+		}
+	}
+
+	if m := reQtyPriceSum.FindStringSubmatch(upper); m != nil && reLetters.MatchString(m[1]) {
+		qty, okQty := receipts.ParseAmount(m[2])
+		price, okPrice := receipts.ParseAmount(m[3])
+		lineTotal, okTotal := receipts.ParseAmount(m[4])
+		if okQty && okPrice && okTotal && qty > 0 && math.Abs(qty*price-lineTotal) <= 1 {
+			return newItem(strings.TrimSpace(original[:len(m[1])]), qty, price, lineTotal), true
+		}
+	}
+
+	if label, amount, ok := splitTrailingAmount(upper); ok && reLetters.MatchString(label) {
+		if !itemsStarted && !strings.ContainsAny(upper[len(label):], ".,") {
+			return models.Item{}, false
+		}
+		name := strings.TrimSpace(original[:len(label)])
+		return newItem(name, 1, amount, amount), true
+	}
+
+	if reAmount.MatchString(upper) && pendingName != "" {
+		if amount, ok := receipts.ParseAmount(upper); ok {
+			return newItem(pendingName, 1, amount, amount), true
+		}
+	}
+	return models.Item{}, false
+}
+
+func newItem(name string, qty, price, total float64) models.Item {
+	return models.Item{
+		Name:     name,
+		Price:    receipts.FormatAmount(price),
+		Quantity: strconv.FormatFloat(qty, 'f', -1, 64),
+		Total:    receipts.FormatAmount(total),
+	}
+}
+
+func splitTrailingAmount(upper string) (string, float64, bool) {
+	m := reTrailAmount.FindStringSubmatch(upper)
+	if m == nil {
+		return "", 0, false
+	}
+	amount, ok := receipts.ParseAmount(m[2])
+	if !ok {
+		return "", 0, false
+	}
+	if strings.HasPrefix(strings.TrimSpace(upper[len(m[1]):]), "(") || strings.HasSuffix(upper, "-") {
+		amount = -math.Abs(amount)
+	}
+	return strings.TrimSpace(strings.TrimRight(m[1], ":")), amount, true
+}
+
+func isSummaryLabel(label string) bool {
+	for _, re := range []*regexp.Regexp{reKembali, reSubtotal, reDiskon, reService, reDPP, reTax, reTotal, rePayment} {
+		if re.MatchString(label) {
+			return true
+		}
+	}
+	return false
+}
+
+func taxName(label string) string {
+	m := reTax.FindString(label)
+	if m == "PB-1" {
+		return "PB1"
+	}
+	return m
+}
+
+func isSeparatorLine(line string) bool {
+	return strings.Trim(line, "-=*_.~# ") == ""
+}
+
+func parseDate(upper string) string {
+	if m := reDateYMD.FindStringSubmatch(upper); m != nil {
+		return fmt.Sprintf("%02s/%02s/%s", m[3], m[2], m[1])
+	}
+	if m := reDateDMY.FindStringSubmatch(upper); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if day < 1 || day > 31 || month < 1 || month > 12 {
+			return ""
+		}
+		return fmt.Sprintf("%02d/%02d/%s", day, month, expandYear(m[3]))
+	}
+	if m := reDateText.FindStringSubmatch(upper); m != nil {
+		return fmt.Sprintf("%02s/%s/%s", m[1], indonesianMonths[m[2]], expandYear(m[3]))
+	}
+	return ""
+}
+
+func expandYear(year string) string {
+	if len(year) == 2 {
+		return "20" + year
+	}
+	return year
+}
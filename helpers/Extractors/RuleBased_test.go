@@ -0,0 +1,134 @@
+package extractors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func TestParseReceiptTextTotals(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    models.Totals
+		items   int
+		partial bool
+	}{
+		{
+			name: "subtotal ppn tunai kembali",
+			text: `TOKO MAJU JAYA
+JL. MERDEKA NO. 10
+NASI GORENG 2 X 25.000 50.000
+ES TEH 5.000
+SUBTOTAL 55.000
+PPN 11% 6.050
+TOTAL 61.050
+TUNAI 100.000
+KEMBALI 38.950`,
+			want: models.Totals{
+				Subtotal: "55000.00",
+				Discount: "0.00",
+				Total:    "61050.00",
+				Payment:  "100000.00",
+				Change:   "38950.00",
+				Tax:      models.Tax{Name: "PPN", Amount: "6050.00", ServiceCharge: "0.00", TotalTax: "6050.00"},
+			},
+			items: 2,
+		},
+		{
+			name: "pb1 service dan diskon",
+			text: `WARUNG SEDAP
+AYAM BAKAR 40.000
+DISKON (4.000)
+SUBTOTAL 36.000
+SERVICE 5% 1.800
+PB-1 10% 3.780
+GRAND TOTAL 41.580`,
+			want: models.Totals{
+				Subtotal: "36000.00",
+				Discount: "4000.00",
+				Total:    "41580.00",
+				Payment:  "0.00",
+				Change:   "0.00",
+				Tax:      models.Tax{Name: "PB1", Amount: "3780.00", ServiceCharge: "1800.00", TotalTax: "5580.00"},
+			},
+			items: 1,
+		},
+		{
+			name: "kembalian dihitung dari tunai tanpa baris kembali",
+			text: `KEDAI KOPI
+KOPI SUSU 2 X 18.000 36.000
+TOTAL 36.000
+CASH 50.000`,
+			want: models.Totals{
+				Subtotal: "36000.00",
+				Discount: "0.00",
+				Total:    "36000.00",
+				Payment:  "50000.00",
+				Change:   "14000.00",
+				Tax:      models.Tax{Amount: "0.00", ServiceCharge: "0.00", TotalTax: "0.00"},
+			},
+			items: 1,
+		},
+		{
+			name: "tanpa total dikembalikan sebagai hasil parsial",
+			text: `TOKO ROTI
+ROTI TAWAR 15.000
+ROTI SOBEK 12.000`,
+			want: models.Totals{
+				Subtotal: "27000.00",
+				Discount: "0.00",
+				Total:    "27000.00",
+				Payment:  "0.00",
+				Change:   "0.00",
+				Tax:      models.Tax{Amount: "0.00", ServiceCharge: "0.00", TotalTax: "0.00"},
+			},
+			items:   2,
+			partial: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			receipt, err := ParseReceiptText(test.text)
+			if test.partial != errors.Is(err, ErrUnreconciled) || (!test.partial && err != nil) {
+				t.Fatalf("ParseReceiptText() error = %v, partial %v", err, test.partial)
+			}
+			if receipt.Totals != test.want {
+				t.Errorf("Totals = %+v, want %+v", receipt.Totals, test.want)
+			}
+			if len(receipt.Items) != test.items {
+				t.Errorf("got %d items, want %d: %+v", len(receipt.Items), test.items, receipt.Items)
+			}
+		})
+	}
+}
+
+func TestParseReceiptTextDateTime(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		date string
+		time string
+	}{
+		{name: "dd/mm/yyyy", line: "02/08/2025 19:30", date: "02/08/2025", time: "19:30"},
+		{name: "d-m-yy", line: "2-8-25 7:05", date: "02/08/2025", time: "07:05"},
+		{name: "yyyy-mm-dd", line: "2025-08-02 19:30:45", date: "02/08/2025", time: "19:30"},
+		{name: "bulan indonesia", line: "TGL 2 AGUSTUS 2025 JAM 21:15", date: "02/08/2025", time: "21:15"},
+		{name: "bulan singkat", line: "17 DES 24", date: "17/12/2024"},
+		{name: "tanggal tidak valid", line: "32/13/2025"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			receipt, _ := ParseReceiptText("TOKO MAJU\n" + test.line + "\nTEH 5.000\nTOTAL 5.000")
+			if receipt.TransactionInfo.Date != test.date {
+				t.Errorf("Date = %q, want %q", receipt.TransactionInfo.Date, test.date)
+			}
+			if receipt.TransactionInfo.Time != test.time {
+				t.Errorf("Time = %q, want %q", receipt.TransactionInfo.Time, test.time)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package extractors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// Trace berisi callback yang dipanggil extractor selama ekstraksi berjalan, seperti httptrace
+// untuk HTTP client. Extractor yang mendukung streaming memanggil ModelStarted saat permintaan ke
+// model dikirim dan Item untuk setiap item yang sudah lengkap diurai, sebelum jawaban model selesai.
+type Trace struct {
+	ModelStarted func()
+	Item         func(item models.Item)
+}
+
+type traceKey struct{}
+
+// WithTrace mengembalikan context yang membawa trace. Trace nil menghapus trace dari context.
+func WithTrace(ctx context.Context, trace *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+func traceFrom(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+func (trace *Trace) modelStarted() {
+	if trace != nil && trace.ModelStarted != nil {
+		trace.ModelStarted()
+	}
+}
+
+func (trace *Trace) item(item models.Item) {
+	if trace != nil && trace.Item != nil {
+		trace.Item(item)
+	}
+}
+
+// streamProgress menyimpan progress trace satu panggilan model lintas percobaan ulang dengan key
+// lain. ModelStarted hanya dilaporkan sekali dan item dilaporkan berdasarkan urutannya, sehingga
+// jawaban ulang dari awal tidak mengirim item yang sudah dilaporkan untuk kedua kalinya.
+type streamProgress struct {
+	trace   *Trace
+	started bool
+	scanner itemScanner
+}
+
+func newStreamProgress(trace *Trace) *streamProgress {
+	if trace == nil {
+		return nil
+	}
+	return &streamProgress{trace: trace}
+}
+
+func (progress *streamProgress) modelStarted() {
+	if !progress.started {
+		progress.started = true
+		progress.trace.modelStarted()
+	}
+}
+
+// items melaporkan item di jawaban sebagian text yang urutannya belum pernah dilaporkan
+func (progress *streamProgress) items(text string) {
+	for _, item := range progress.scanner.scan(text) {
+		progress.trace.item(item)
+	}
+}
+
+// itemScanner mencari objek item yang sudah lengkap di jawaban model yang masih sebagian. Setiap
+// objek di dalam array "items" dikembalikan sekali, sesuai urutan kemunculannya.
+type itemScanner struct {
+	emitted int
+}
+
+// scan mengembalikan item baru di text sejak pemanggilan sebelumnya. Objek yang belum lengkap
+// atau tidak bisa diurai dilewati.
+func (scanner *itemScanner) scan(text string) []models.Item {
+	found := []models.Item{}
+	for _, object := range itemObjects(text) {
+		var item models.Item
+		if err := decodeModelJSON(object, &item); err != nil {
+			continue
+		}
+		found = append(found, item)
+	}
+	if len(found) <= scanner.emitted {
+		return nil
+	}
+	newItems := found[scanner.emitted:]
+	scanner.emitted = len(found)
+	return newItems
+}
+
+// itemObjects mengembalikan teks setiap objek lengkap di dalam array "items", termasuk array
+// "items" milik beberapa struk atau bagian struk dalam satu jawaban
+func itemObjects(text string) []string {
+	objects := []string{}
+	for offset := 0; ; {
+		key := strings.Index(text[offset:], `"items"`)
+		if key < 0 {
+			return objects
+		}
+		offset += key + len(`"items"`)
+		start := strings.IndexByte(text[offset:], '[')
+		if start < 0 || strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text[offset:offset+start]), ":")) != "" {
+			continue
+		}
+		offset += start + 1
+
+		depth, objectStart, inString, escaped := 0, -1, false, false
+	scan:
+		for ; offset < len(text); offset++ {
+			char := text[offset]
+			switch {
+			case escaped:
+				escaped = false
+			case inString:
+				if char == '\\' {
+					escaped = true
+				} else if char == '"' {
+					inString = false
+				}
+			case char == '"':
+				inString = true
+			case char == '{' || char == '[':
+				if depth == 0 && char == '{' {
+					objectStart = offset
+				}
+				depth++
+			case char == '}' || char == ']':
+				if depth == 0 {
+					// Akhir array items
+					break scan
+				}
+				depth--
+				if depth == 0 && objectStart >= 0 {
+					objects = append(objects, text[objectStart:offset+1])
+					objectStart = -1
+				}
+			}
+		}
+	}
+}
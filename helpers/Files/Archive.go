@@ -0,0 +1,34 @@
+package files
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path"
+	"strings"
+)
+
+var zipMagic = []byte("PK\x03\x04")
+
+// IsZip memeriksa magic bytes arsip ZIP
+func IsZip(header []byte) bool {
+	return bytes.HasPrefix(header, zipMagic)
+}
+
+// ZipEntries mengembalikan file di dalam arsip ZIP sesuai urutannya di arsip. Folder, file
+// tersembunyi dan metadata macOS (__MACOSX, ._*) dilewati.
+func ZipEntries(reader io.ReaderAt, size int64) ([]*zip.File, error) {
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*zip.File{}
+	for _, entry := range archive.File {
+		name := path.Clean(strings.ReplaceAll(entry.Name, "\\", "/"))
+		if entry.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,115 @@
+package files
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+const PDFMimeType = "application/pdf"
+
+// maxObjectStreamBytes membatasi total isi object stream yang didekompresi saat menghitung halaman,
+// sehingga PDF kecil yang berisi stream bom tidak menghabiskan memori
+const maxObjectStreamBytes = 32 << 20
+
+var pdfMagic = []byte("%PDF-")
+
+var (
+	// rePDFPage mencocokkan objek halaman (/Type /Page) tetapi bukan daftar halaman (/Type /Pages)
+	rePDFPage = regexp.MustCompile(`/Type\s*/Page[^s]`)
+	// rePDFPages mencocokkan node page tree (/Type /Pages)
+	rePDFPages = regexp.MustCompile(`/Type\s*/Pages\b`)
+	rePDFCount = regexp.MustCompile(`/Count\s+(\d+)`)
+	// rePDFObjStm mencocokkan awal data object stream: dictionary dengan /Type /ObjStm lalu keyword stream
+	rePDFObjStm = regexp.MustCompile(`/Type\s*/ObjStm\b[^>]*(?:>[^>][^>]*)*>>\s*stream\r?\n`)
+)
+
+// IsPDF memeriksa magic bytes file, bukan Content-Type yang dikirim client
+func IsPDF(data []byte) bool {
+	return bytes.HasPrefix(data, pdfMagic)
+}
+
+// CountPDFPages membaca jumlah halaman dari /Count root page tree (node /Type /Pages dengan /Count
+// terbesar), termasuk objek yang disimpan di object stream terkompresi. Jika page tree tidak
+// ditemukan, objek /Type /Page dihitung satu per satu. Nilai 0 berarti jumlah halaman tidak diketahui.
+func CountPDFPages(data []byte) int {
+	objects := append([]byte{}, data...)
+	for _, stream := range pdfObjectStreams(data) {
+		objects = append(append(objects, '\n'), stream...)
+	}
+
+	pages := 0
+	for _, match := range rePDFPages.FindAllIndex(objects, -1) {
+		count := rePDFCount.FindSubmatch(pdfDictionary(objects, match[0]))
+		if count == nil {
+			continue
+		}
+		if n, err := strconv.Atoi(string(count[1])); err == nil && n > pages {
+			pages = n
+		}
+	}
+	if pages > 0 {
+		return pages
+	}
+	return len(rePDFPage.FindAllIndex(objects, -1))
+}
+
+// pdfObjectStreams mendekompresi isi semua object stream (/Type /ObjStm) yang memakai FlateDecode.
+// Panjang stream dibaca dari akhir data zlib sehingga /Length yang berupa referensi tidak perlu
+// diselesaikan.
+func pdfObjectStreams(data []byte) [][]byte {
+	streams := [][]byte{}
+	remaining := int64(maxObjectStreamBytes)
+	for _, match := range rePDFObjStm.FindAllIndex(data, -1) {
+		if remaining <= 0 {
+			break
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(data[match[1]:]))
+		if err != nil {
+			continue
+		}
+		stream, _ := io.ReadAll(io.LimitReader(reader, remaining))
+		reader.Close()
+		remaining -= int64(len(stream))
+		streams = append(streams, stream)
+	}
+	return streams
+}
+
+// pdfDictionary mengembalikan dictionary (<< ... >>) yang melingkupi posisi offset, termasuk
+// dictionary bersarang di dalamnya
+func pdfDictionary(data []byte, offset int) []byte {
+	start, depth := -1, 0
+	for i := offset - 1; i > 0; i-- {
+		if data[i-1] == '>' && data[i] == '>' {
+			depth++
+			i--
+		} else if data[i-1] == '<' && data[i] == '<' {
+			if depth == 0 {
+				start = i - 1
+				break
+			}
+			depth--
+			i--
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	depth = 0
+	for i := start; i+1 < len(data); i++ {
+		if data[i] == '<' && data[i+1] == '<' {
+			depth++
+			i++
+		} else if data[i] == '>' && data[i+1] == '>' {
+			depth--
+			i++
+			if depth == 0 {
+				return data[start : i+1]
+			}
+		}
+	}
+	return data[start:]
+}
@@ -0,0 +1,76 @@
+package files
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pdfPageObjects membuat objek halaman /Type /Page sebanyak pages dengan nomor objek mulai dari first
+func pdfPageObjects(first int, pages int) []string {
+	objects := []string{}
+	for i := 0; i < pages; i++ {
+		objects = append(objects, fmt.Sprintf("%d 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 400] >>\nendobj\n", first+i))
+	}
+	return objects
+}
+
+// objectStreamPDF menyimpan catalog, page tree dan semua halaman di satu object stream FlateDecode,
+// seperti PDF yang dibuat dengan kompresi objek (PDF 1.5+)
+func objectStreamPDF(t *testing.T, pages int) []byte {
+	t.Helper()
+	kids := []string{}
+	bodies := []string{"<< /Type /Catalog /Pages 2 0 R >>", ""}
+	for i := 0; i < pages; i++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 3+i))
+		bodies = append(bodies, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 400] >>")
+	}
+	bodies[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages)
+
+	var header, content strings.Builder
+	for i, body := range bodies {
+		fmt.Fprintf(&header, "%d %d ", 1+i, content.Len())
+		content.WriteString(body + "\n")
+	}
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	writer.Write([]byte(header.String() + content.String()))
+	writer.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.5\n")
+	fmt.Fprintf(&pdf, "%d 0 obj\n<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n", len(bodies)+1, len(bodies), header.Len(), compressed.Len())
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestCountPDFPages(t *testing.T) {
+	plain := "%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 >>\nendobj\n" + strings.Join(pdfPageObjects(3, 3), "") + "%%EOF\n"
+	nested := "%PDF-1.4\n2 0 obj\n<< /Type /Pages /Kids [6 0 R 7 0 R] /Count 25 >>\nendobj\n" +
+		"6 0 obj\n<< /Type /Pages /Parent 2 0 R /Kids [] /Count 10 >>\nendobj\n" +
+		"7 0 obj\n<< /Count 15 /Type /Pages /Parent 2 0 R /Resources << /Font << /F1 8 0 R >> >> /Kids [] >>\nendobj\n%%EOF\n"
+	withoutTree := "%PDF-1.4\n" + strings.Join(pdfPageObjects(3, 2), "") + "%%EOF\n"
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "plain page tree", data: []byte(plain), want: 3},
+		{name: "root count of nested page tree", data: []byte(nested), want: 25},
+		{name: "page objects without page tree", data: []byte(withoutTree), want: 2},
+		{name: "object stream", data: objectStreamPDF(t, 30), want: 30},
+		{name: "no pages", data: []byte("%PDF-1.4\n%%EOF\n"), want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CountPDFPages(test.data); got != test.want {
+				t.Errorf("CountPDFPages() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpoolEntry adalah file yang gagal diunggah ke bucket dan menunggu diunggah ulang. ReceiptIDs
+// adalah receipt record yang SourceURLs[Index]-nya diisi setelah upload berhasil.
+type SpoolEntry struct {
+	ID          string    `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Document    bool      `json:"document"`
+	ReceiptIDs  []string  `json:"receipt_ids"`
+	Index       int       `json:"index"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SpoolInterface menyimpan file sementara di disk lokal selama bucket tidak tersedia
+type SpoolInterface interface {
+	Enabled() bool
+	Enqueue(entry *SpoolEntry, data []byte) error
+	Pending() ([]SpoolEntry, error)
+	Read(entry SpoolEntry) ([]byte, error)
+	Update(entry SpoolEntry) error
+	Remove(entry SpoolEntry) error
+}
+
+// Spool menyimpan setiap file sebagai <dir>/<id>.bin dengan metadata di <dir>/<id>.json.
+// Spool nonaktif jika Dir kosong.
+type Spool struct {
+	Dir   string
+	mutex sync.Mutex
+}
+
+// NewSpool membaca direktori spool dari STORAGE_SPOOL_DIR
+func NewSpool() *Spool {
+	return &Spool{Dir: os.Getenv("STORAGE_SPOOL_DIR")}
+}
+
+func (spool *Spool) Enabled() bool {
+	return spool.Dir != ""
+}
+
+func (spool *Spool) Enqueue(entry *SpoolEntry, data []byte) error {
+	if !spool.Enabled() {
+		return errors.New("storage spool is not configured")
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	spool.mutex.Lock()
+	defer spool.mutex.Unlock()
+	if err := os.MkdirAll(spool.Dir, 0755); err != nil {
+		return fmt.Errorf("error creating spool directory: %w", err)
+	}
+	if err := os.WriteFile(spool.path(entry.ID, ".bin"), data, 0644); err != nil {
+		return fmt.Errorf("error writing spooled file: %w", err)
+	}
+	// Metadata ditulis terakhir sehingga entry tidak pernah terbaca tanpa datanya
+	return spool.writeEntry(*entry)
+}
+
+// Pending mengembalikan semua entry yang belum diunggah, diurutkan dari yang paling lama
+func (spool *Spool) Pending() ([]SpoolEntry, error) {
+	spool.mutex.Lock()
+	defer spool.mutex.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(spool.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]SpoolEntry, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading spool entry: %w", err)
+		}
+		var entry SpoolEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("error decoding spool entry %s: %w", filepath.Base(path), err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+func (spool *Spool) Read(entry SpoolEntry) ([]byte, error) {
+	return os.ReadFile(spool.path(entry.ID, ".bin"))
+}
+
+func (spool *Spool) Update(entry SpoolEntry) error {
+	spool.mutex.Lock()
+	defer spool.mutex.Unlock()
+	return spool.writeEntry(entry)
+}
+
+func (spool *Spool) Remove(entry SpoolEntry) error {
+	spool.mutex.Lock()
+	defer spool.mutex.Unlock()
+	if err := os.Remove(spool.path(entry.ID, ".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(spool.path(entry.ID, ".bin")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (spool *Spool) writeEntry(entry SpoolEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding spool entry: %w", err)
+	}
+	path := spool.path(entry.ID, ".json")
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("error writing spool entry: %w", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (spool *Spool) path(id string, extension string) string {
+	return filepath.Join(spool.Dir, strings.ReplaceAll(id, string(filepath.Separator), "_")+extension)
+}
@@ -0,0 +1,62 @@
+package files
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSpool(t *testing.T) {
+	spool := &Spool{Dir: t.TempDir()}
+	start := time.Now()
+	newer := SpoolEntry{Filename: "b.jpg", CreatedAt: start.Add(time.Second)}
+	older := SpoolEntry{Filename: "a.jpg", CreatedAt: start, ReceiptIDs: []string{"receipt-1"}, Index: 1}
+	if err := spool.Enqueue(&newer, []byte("bbb")); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if err := spool.Enqueue(&older, []byte("aaa")); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if older.ID == "" || older.ID == newer.ID {
+		t.Fatalf("Enqueue() ids = %q, %q, want unique ids", older.ID, newer.ID)
+	}
+
+	entries, err := spool.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != older.ID || entries[1].ID != newer.ID {
+		t.Fatalf("Pending() = %+v, want oldest first", entries)
+	}
+	if entries[0].Index != 1 || !slices.Equal(entries[0].ReceiptIDs, []string{"receipt-1"}) {
+		t.Errorf("entry = %+v, want metadata kept", entries[0])
+	}
+	if data, err := spool.Read(entries[0]); err != nil || string(data) != "aaa" {
+		t.Errorf("Read() = %q, %v, want aaa", data, err)
+	}
+
+	entries[0].Attempts = 2
+	if err := spool.Update(entries[0]); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := spool.Remove(entries[1]); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	entries, err = spool.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != older.ID || entries[0].Attempts != 2 {
+		t.Errorf("Pending() after update and remove = %+v", entries)
+	}
+}
+
+func TestSpoolDisabled(t *testing.T) {
+	spool := &Spool{}
+	if spool.Enabled() {
+		t.Fatalf("Enabled() = true without a directory")
+	}
+	if err := spool.Enqueue(&SpoolEntry{}, []byte("a")); err == nil {
+		t.Errorf("Enqueue() error = nil, want spool is not configured")
+	}
+}
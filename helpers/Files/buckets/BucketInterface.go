@@ -4,7 +4,8 @@ import (
 	"github.com/arifin2018/splitbill-arifin.git/helpers/files/buckets/models"
 )
 
+// BucketInterface hanya menyimpan bytes; resize dan re-encode gambar dilakukan oleh image pipeline
+// (helpers/Images) sebelum file sampai ke bucket.
 type BucketInterface interface {
-	CheckFileSizeAndResizeFileIfNecessary(imageData []byte) (imageDataReader models.ImagerDataReader, err error)
 	CreateFileStorageAndPublish(objectName string, imageDataReader models.ReaderFileHeader) (string, error)
 }
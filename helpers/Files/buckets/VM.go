@@ -1,53 +1,19 @@
 package buckets
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"image"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/arifin2018/splitbill-arifin.git/config"
 	"github.com/arifin2018/splitbill-arifin.git/helpers/files/buckets/models"
-	"github.com/disintegration/imaging"
 )
 
 type VM struct {
 }
 
-// func (vm *VM) CheckFileSizeAndResizeFileIfNecessary(imageData []byte) (imageDataReader models.ImagerDataReader, err error) {
-func (vm *VM) CheckFileSizeAndResizeFileIfNecessary(imageData []byte) (imageDataReader models.ImagerDataReader, err error) {
-	config.GeneralLogger.Printf("[Firebase Upload Info] File size (%d bytes) exceeds limit. Resizing...\n", len(imageData))
-	var reader *bytes.Reader
-	// img, _, err := image.Decode(bytes.NewReader(imageData))
-	img, _, err := image.Decode(bytes.NewReader(imageData))
-	if err != nil {
-		if err != nil {
-			config.GeneralLogger.Printf("[Firebase Upload Error] Error decoding image for resizing: %v\n", err)
-			return models.ImagerDataReader{}, errors.New(fmt.Sprintf("error decoding image for resizing: %s", err))
-		}
-
-		if img.Bounds().Dx() > 1000 {
-			img = imaging.Resize(img, 1000, 0, imaging.Lanczos)
-		}
-
-		var buf bytes.Buffer
-		err = imaging.Encode(&buf, img, imaging.JPEG)
-		if err != nil {
-			config.GeneralLogger.Printf("[Firebase Upload Error] Error encoding resized image: %v\n", err)
-			return models.ImagerDataReader{}, errors.New(fmt.Sprintf("error encoding resized image: %w", err))
-		}
-
-		reader = bytes.NewReader(buf.Bytes())
-		config.GeneralLogger.Printf("[Firebase Upload Info] Resizing complete. New size: %d bytes\n", buf.Len())
-	}
-	return models.ImagerDataReader{
-		Reader:    reader,
-		ImageData: nil,
-	}, nil
-}
-
 // func (vm *VM) CreateFileStorageAndPublish(objectName string, imageDataReader models.ReaderFileHeader) (string, error) {
 func (vm *VM) CreateFileStorageAndPublish(objectName string, imageDataReader models.ReaderFileHeader) (string, error) {
 	// 5. Create storage directory if not exists
@@ -58,6 +24,9 @@ func (vm *VM) CreateFileStorageAndPublish(objectName string, imageDataReader mod
 
 	// Create the file
 	filePath := fmt.Sprintf("%s/%s", storagePath, objectName)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", errors.New(fmt.Sprintf("error creating storage directory: %s", err))
+	}
 	dst, err := os.Create(filePath)
 	if err != nil {
 		return "", errors.New(fmt.Sprintf("error creating file: %s", err))
@@ -66,11 +35,11 @@ func (vm *VM) CreateFileStorageAndPublish(objectName string, imageDataReader mod
 
 	// Copy the file data
 	if _, err = io.Copy(dst, imageDataReader.Reader); err != nil {
-		return "", errors.New(fmt.Sprintf("error copying file: %w", err))
+		return "", fmt.Errorf("error copying file: %w", err)
 	}
 
-	// Return the relative path to the file
-	publicURL := fmt.Sprintf("/storage/images/%s", objectName)
+	// Return the relative path to the file; objectName already starts with its folder (images/ or receipts/)
+	publicURL := fmt.Sprintf("/storage/%s", objectName)
 	config.GeneralLogger.Printf("[Upload Info] Upload successful. Path: %s\n", publicURL)
 	return publicURL, nil
 }
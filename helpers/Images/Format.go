@@ -0,0 +1,139 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	_ "golang.org/x/image/webp"
+)
+
+// MIME type gambar yang dikenali dari magic bytes
+const (
+	MIMETypeJPEG = "image/jpeg"
+	MIMETypePNG  = "image/png"
+	MIMETypeWebP = "image/webp"
+	MIMETypeHEIC = "image/heic"
+	MIMETypeHEIF = "image/heif"
+	MIMETypeAVIF = "image/avif"
+)
+
+// ErrUnsupportedMedia dikembalikan untuk file yang formatnya tidak dikenali atau tidak bisa diproses
+var ErrUnsupportedMedia = errors.New("unsupported media type")
+
+// transcodeTimeout membatasi lama perintah transcoder eksternal
+const transcodeTimeout = 30 * time.Second
+
+// DetectMIMEType menebak format gambar dari isi file (magic bytes), bukan dari Content-Type yang
+// dikirim client. Hasilnya string kosong jika format tidak dikenali.
+func DetectMIMEType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return MIMETypeJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return MIMETypePNG
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return MIMETypeWebP
+	case len(data) >= 12 && string(data[4:8]) == "ftyp":
+		return isoMediaType(data)
+	}
+	return ""
+}
+
+// isoMediaType membaca major brand dan compatible brands pada box "ftyp" HEIF/AVIF
+func isoMediaType(data []byte) string {
+	boxSize := int(data[0])<<24 | int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+	if boxSize < 16 || boxSize > len(data) {
+		boxSize = min(len(data), 64)
+	}
+	brands := []string{string(data[8:12])}
+	for offset := 16; offset+4 <= boxSize; offset += 4 {
+		brands = append(brands, string(data[offset:offset+4]))
+	}
+
+	mimeType := ""
+	for _, brand := range brands {
+		switch brand {
+		case "avif", "avis":
+			return MIMETypeAVIF
+		case "heic", "heix", "hevc", "hevx", "heim", "heis":
+			mimeType = MIMETypeHEIC
+		case "mif1", "msf1":
+			if mimeType == "" {
+				mimeType = MIMETypeHEIF
+			}
+		}
+	}
+	return mimeType
+}
+
+// Normalized adalah gambar dalam format yang bisa diproses. Decodable bernilai false untuk
+// format yang hanya bisa dikirim apa adanya ke model (HEIC tanpa transcoder).
+type Normalized struct {
+	Data      []byte
+	MIMEType  string
+	Decodable bool
+}
+
+// NormalizerInterface mengenali format gambar dan mengubah format yang tidak bisa didekode menjadi
+// format standar
+type NormalizerInterface interface {
+	Normalize(imageData []byte) (Normalized, error)
+}
+
+// Normalizer mendeteksi format dari magic bytes. JPEG, PNG dan WebP didekode langsung. HEIC/HEIF
+// dan AVIF di-transcode ke JPEG dengan TranscoderCommand (misalnya "magick - jpeg:-", membaca stdin
+// dan menulis stdout). Tanpa transcoder, HEIC/HEIF dikirim apa adanya ke Gemini yang mendukungnya
+// secara native, sedangkan AVIF ditolak.
+type Normalizer struct {
+	TranscoderCommand []string
+}
+
+// NewNormalizer membaca perintah transcoder dari IMAGE_TRANSCODER_COMMAND
+func NewNormalizer() *Normalizer {
+	return &Normalizer{
+		TranscoderCommand: strings.Fields(os.Getenv("IMAGE_TRANSCODER_COMMAND")),
+	}
+}
+
+func (normalizer *Normalizer) Normalize(imageData []byte) (Normalized, error) {
+	mimeType := DetectMIMEType(imageData)
+	switch mimeType {
+	case MIMETypeJPEG, MIMETypePNG, MIMETypeWebP:
+		return Normalized{Data: imageData, MIMEType: mimeType, Decodable: true}, nil
+	case MIMETypeHEIC, MIMETypeHEIF, MIMETypeAVIF:
+		if len(normalizer.TranscoderCommand) > 0 {
+			transcoded, err := normalizer.transcode(imageData)
+			if err == nil && DetectMIMEType(transcoded) == MIMETypeJPEG {
+				return Normalized{Data: transcoded, MIMEType: MIMETypeJPEG, Decodable: true}, nil
+			}
+			config.GeneralLogger.Printf("[Normalize Error] Failed to transcode %s: %v\n", mimeType, err)
+		}
+		if mimeType != MIMETypeAVIF {
+			return Normalized{Data: imageData, MIMEType: mimeType}, nil
+		}
+		return Normalized{}, fmt.Errorf("%w: %s cannot be processed without a transcoder", ErrUnsupportedMedia, mimeType)
+	}
+	return Normalized{}, fmt.Errorf("%w: unrecognized image format", ErrUnsupportedMedia)
+}
+
+func (normalizer *Normalizer) transcode(imageData []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), transcodeTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, normalizer.TranscoderCommand[0], normalizer.TranscoderCommand[1:]...)
+	command.Stdin = bytes.NewReader(imageData)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,96 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/sirupsen/logrus"
+)
+
+// ftyp membuat header ISO media (HEIF/AVIF) dengan major brand dan compatible brands
+func ftyp(major string, compatible ...string) []byte {
+	box := append([]byte{0, 0, 0, byte(16 + 4*len(compatible))}, []byte("ftyp"+major+"\x00\x00\x00\x00")...)
+	for _, brand := range compatible {
+		box = append(box, []byte(brand)...)
+	}
+	return append(box, make([]byte, 32)...)
+}
+
+func TestDetectMIMEType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "jpeg", data: []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0x10}, want: MIMETypeJPEG},
+		{name: "png", data: []byte("\x89PNG\r\n\x1a\n\x00\x00"), want: MIMETypePNG},
+		{name: "webp", data: []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), want: MIMETypeWebP},
+		{name: "heic", data: ftyp("heic", "mif1", "heic"), want: MIMETypeHEIC},
+		{name: "heif with heic brand", data: ftyp("mif1", "mif1", "heic"), want: MIMETypeHEIC},
+		{name: "heif", data: ftyp("mif1", "mif1"), want: MIMETypeHEIF},
+		{name: "avif", data: ftyp("avif", "mif1", "miaf"), want: MIMETypeAVIF},
+		{name: "avif sequence", data: ftyp("msf1", "avis"), want: MIMETypeAVIF},
+		{name: "mp4", data: ftyp("isom", "mp41"), want: ""},
+		{name: "pdf", data: []byte("%PDF-1.7\n"), want: ""},
+		{name: "short", data: []byte{0xFF, 0xD8}, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DetectMIMEType(test.data); got != test.want {
+				t.Errorf("DetectMIMEType() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE0}
+	heic := ftyp("heic", "mif1", "heic")
+	avif := ftyp("avif", "mif1")
+	toJPEG := []string{"sh", "-c", `cat >/dev/null; printf '\377\330\377\340'`}
+	failing := []string{"sh", "-c", "cat >/dev/null; exit 1"}
+	tests := []struct {
+		name       string
+		transcoder []string
+		data       []byte
+		want       []byte
+		mimeType   string
+		decodable  bool
+		err        bool
+	}{
+		{name: "jpeg", data: jpeg, want: jpeg, mimeType: MIMETypeJPEG, decodable: true},
+		{name: "heic without transcoder", data: heic, want: heic, mimeType: MIMETypeHEIC},
+		{name: "avif without transcoder", data: avif, err: true},
+		{name: "heic transcoded", transcoder: toJPEG, data: heic, want: jpeg, mimeType: MIMETypeJPEG, decodable: true},
+		{name: "avif transcoded", transcoder: toJPEG, data: avif, want: jpeg, mimeType: MIMETypeJPEG, decodable: true},
+		{name: "heic with failing transcoder", transcoder: failing, data: heic, want: heic, mimeType: MIMETypeHEIC},
+		{name: "avif with failing transcoder", transcoder: failing, data: avif, err: true},
+		{name: "unknown format", data: []byte("GIF89a"), err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalized, err := (&Normalizer{TranscoderCommand: test.transcoder}).Normalize(test.data)
+			if test.err {
+				if !errors.Is(err, ErrUnsupportedMedia) {
+					t.Fatalf("error = %v, want ErrUnsupportedMedia", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !bytes.Equal(normalized.Data, test.want) || normalized.MIMEType != test.mimeType || normalized.Decodable != test.decodable {
+				t.Errorf("Normalize() = %q %q decodable %v, want %q %q decodable %v", normalized.Data, normalized.MIMEType, normalized.Decodable, test.want, test.mimeType, test.decodable)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package images
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// analysisWidth adalah lebar gambar kecil yang dipakai untuk deteksi kertas dan kemiringan
+const analysisWidth = 600
+
+// paperRegion adalah area kertas struk yang terdeteksi dalam koordinat gambar asli.
+// corners berurutan kiri atas, kanan atas, kanan bawah, kiri bawah.
+type paperRegion struct {
+	corners [4]point
+	bounds  image.Rectangle
+	width   float64
+	height  float64
+}
+
+type point struct {
+	x, y float64
+}
+
+// findPaper mencari komponen terang terbesar (kertas struk) pada gambar. Kertas yang mengisi
+// hampir seluruh foto atau terlalu kecil dianggap tidak terdeteksi.
+func findPaper(img image.Image) (paperRegion, bool) {
+	small, scale := downscale(img)
+	width, height := small.Bounds().Dx(), small.Bounds().Dy()
+	threshold := otsuThreshold(small)
+
+	visited := make([]bool, width*height)
+	bright := func(index int) bool { return int(small.Pix[index*4]) > threshold }
+
+	var largest []int
+	queue := []int{}
+	for start := range visited {
+		if visited[start] || !bright(start) {
+			continue
+		}
+		component := []int{}
+		visited[start] = true
+		queue = append(queue[:0], start)
+		for len(queue) > 0 {
+			index := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			component = append(component, index)
+			x, y := index%width, index/width
+			for _, next := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if next[0] < 0 || next[1] < 0 || next[0] >= width || next[1] >= height {
+					continue
+				}
+				neighbour := next[1]*width + next[0]
+				if !visited[neighbour] && bright(neighbour) {
+					visited[neighbour] = true
+					queue = append(queue, neighbour)
+				}
+			}
+		}
+		if len(component) > len(largest) {
+			largest = component
+		}
+	}
+
+	area := float64(width * height)
+	if float64(len(largest)) < area*0.15 {
+		return paperRegion{}, false
+	}
+
+	// Sudut kertas adalah titik ekstrem dari x+y dan x-y pada komponen terbesar
+	minSum, maxSum, minDiff, maxDiff := math.MaxInt, math.MinInt, math.MaxInt, math.MinInt
+	var corners [4]point
+	minX, minY, maxX, maxY := width, height, 0, 0
+	for _, index := range largest {
+		x, y := index%width, index/width
+		minX, minY, maxX, maxY = min(minX, x), min(minY, y), max(maxX, x), max(maxY, y)
+		if sum := x + y; sum < minSum {
+			minSum, corners[0] = sum, point{float64(x), float64(y)}
+		}
+		if diff := x - y; diff > maxDiff {
+			maxDiff, corners[1] = diff, point{float64(x), float64(y)}
+		}
+		if sum := x + y; sum > maxSum {
+			maxSum, corners[2] = sum, point{float64(x), float64(y)}
+		}
+		if diff := x - y; diff < minDiff {
+			minDiff, corners[3] = diff, point{float64(x), float64(y)}
+		}
+	}
+
+	region := paperRegion{width: float64(width), height: float64(height)}
+	for i := range corners {
+		region.corners[i] = point{corners[i].x * scale, corners[i].y * scale}
+	}
+	margin := int(math.Max(float64(maxX-minX), float64(maxY-minY)) * 0.01)
+	region.bounds = image.Rect(
+		int(float64(max(minX-margin, 0))*scale), int(float64(max(minY-margin, 0))*scale),
+		int(float64(min(maxX+margin+1, width))*scale), int(float64(min(maxY+margin+1, height))*scale),
+	).Intersect(img.Bounds())
+	region.width, region.height = region.width*scale, region.height*scale
+	return region, true
+}
+
+// skewed bernilai true jika keempat sudut kertas cukup jauh dari persegi panjang sejajar sumbu,
+// sehingga koreksi perspektif diperlukan.
+func (region paperRegion) skewed() bool {
+	tolerance := math.Max(region.width, region.height) * 0.02
+	tl, tr, br, bl := region.corners[0], region.corners[1], region.corners[2], region.corners[3]
+	insideFrame := region.bounds.Dx() < int(region.width*0.98) || region.bounds.Dy() < int(region.height*0.98)
+	return insideFrame && (math.Abs(tl.y-tr.y) > tolerance || math.Abs(bl.y-br.y) > tolerance ||
+		math.Abs(tl.x-bl.x) > tolerance || math.Abs(tr.x-br.x) > tolerance)
+}
+
+// croppable bernilai true jika kertas lebih kecil dari foto sehingga latar belakang bisa dibuang
+func (region paperRegion) croppable(bounds image.Rectangle) bool {
+	return !region.bounds.Empty() &&
+		(region.bounds.Dx() < bounds.Dx()*95/100 || region.bounds.Dy() < bounds.Dy()*95/100)
+}
+
+// warpPerspective meluruskan kertas dengan sudut corners menjadi persegi panjang
+func warpPerspective(img image.Image, corners [4]point) *image.NRGBA {
+	tl, tr, br, bl := corners[0], corners[1], corners[2], corners[3]
+	width := int(math.Max(distance(tl, tr), distance(bl, br)))
+	height := int(math.Max(distance(tl, bl), distance(tr, br)))
+	if width < 1 || height < 1 {
+		return imaging.Clone(img)
+	}
+
+	src := imaging.Clone(img)
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	a, b, c, d, e, f, g, h := squareToQuad(corners)
+	for y := 0; y < height; y++ {
+		v := (float64(y) + 0.5) / float64(height)
+		for x := 0; x < width; x++ {
+			u := (float64(x) + 0.5) / float64(width)
+			denominator := g*u + h*v + 1
+			sx := (a*u + b*v + c) / denominator
+			sy := (d*u + e*v + f) / denominator
+			dst.SetNRGBA(x, y, sample(src, sx, sy))
+		}
+	}
+	return dst
+}
+
+// squareToQuad menghitung koefisien homografi dari persegi satuan ke quadrilateral (Heckbert)
+func squareToQuad(corners [4]point) (a, b, c, d, e, f, g, h float64) {
+	x0, y0 := corners[0].x, corners[0].y
+	x1, y1 := corners[1].x, corners[1].y
+	x2, y2 := corners[2].x, corners[2].y
+	x3, y3 := corners[3].x, corners[3].y
+
+	dx1, dx2, dx3 := x1-x2, x3-x2, x0-x1+x2-x3
+	dy1, dy2, dy3 := y1-y2, y3-y2, y0-y1+y2-y3
+	if denominator := dx1*dy2 - dx2*dy1; denominator != 0 && (dx3 != 0 || dy3 != 0) {
+		g = (dx3*dy2 - dx2*dy3) / denominator
+		h = (dx1*dy3 - dx3*dy1) / denominator
+	}
+	a, b, c = x1-x0+g*x1, x3-x0+h*x3, x0
+	d, e, f = y1-y0+g*y1, y3-y0+h*y3, y0
+	return
+}
+
+// sample mengambil warna di koordinat pecahan dengan interpolasi bilinear; di luar gambar putih
+func sample(src *image.NRGBA, x, y float64) color.NRGBA {
+	x, y = x-0.5, y-0.5
+	bounds := src.Bounds()
+	if x < 0 || y < 0 || x > float64(bounds.Dx()-1) || y > float64(bounds.Dy()-1) {
+		return color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, bounds.Dx()-1), min(y0+1, bounds.Dy()-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	var result [4]uint8
+	for channel := 0; channel < 4; channel++ {
+		top := float64(src.Pix[src.PixOffset(x0, y0)+channel])*(1-fx) + float64(src.Pix[src.PixOffset(x1, y0)+channel])*fx
+		bottom := float64(src.Pix[src.PixOffset(x0, y1)+channel])*(1-fx) + float64(src.Pix[src.PixOffset(x1, y1)+channel])*fx
+		result[channel] = uint8(top*(1-fy) + bottom*fy + 0.5)
+	}
+	return color.NRGBA{R: result[0], G: result[1], B: result[2], A: result[3]}
+}
+
+// detectSkew mencari sudut (derajat, berlawanan arah jarum jam) yang membuat baris teks paling
+// horizontal dengan metode projection profile. Sudut kecil di bawah 0.5 derajat diabaikan.
+func detectSkew(img image.Image) float64 {
+	small, _ := downscale(img)
+	width, height := small.Bounds().Dx(), small.Bounds().Dy()
+	threshold := otsuThreshold(small)
+
+	dark := []point{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if int(small.Pix[small.PixOffset(x, y)]) < threshold {
+				dark = append(dark, point{float64(x), float64(y)})
+			}
+		}
+	}
+	if len(dark) < 100 || len(dark) > width*height/2 {
+		return 0
+	}
+
+	bestAngle, bestScore := 0.0, -1.0
+	diagonal := int(math.Hypot(float64(width), float64(height))) + 1
+	rows := make([]float64, diagonal*2)
+	for step := -30; step <= 30; step++ {
+		angle := float64(step) * 0.5
+		sin, cos := math.Sincos(angle * math.Pi / 180)
+		for i := range rows {
+			rows[i] = 0
+		}
+		for _, p := range dark {
+			// Posisi baris setelah gambar diputar angle derajat berlawanan arah jarum jam
+			row := int(p.y*cos-p.x*sin) + diagonal
+			if row >= 0 && row < len(rows) {
+				rows[row]++
+			}
+		}
+		score := 0.0
+		for i := 1; i < len(rows); i++ {
+			delta := rows[i] - rows[i-1]
+			score += delta * delta
+		}
+		if score > bestScore {
+			bestAngle, bestScore = angle, score
+		}
+	}
+	if math.Abs(bestAngle) < 0.5 {
+		return 0
+	}
+	return bestAngle
+}
+
+// downscale mengecilkan gambar menjadi grayscale selebar analysisWidth dan mengembalikan skala
+// untuk mengubah koordinat kembali ke gambar asli.
+func downscale(img image.Image) (*image.NRGBA, float64) {
+	width := img.Bounds().Dx()
+	if width <= analysisWidth {
+		return imaging.Grayscale(img), 1
+	}
+	small := imaging.Resize(img, analysisWidth, 0, imaging.Box)
+	return imaging.Grayscale(small), float64(width) / float64(analysisWidth)
+}
+
+// otsuThreshold menghitung ambang Otsu dari kanal merah gambar grayscale
+func otsuThreshold(gray *image.NRGBA) int {
+	var histogram [256]float64
+	for i := 0; i < len(gray.Pix); i += 4 {
+		histogram[gray.Pix[i]]++
+	}
+	total := float64(len(gray.Pix) / 4)
+	sum := 0.0
+	for value, n := range histogram {
+		sum += float64(value) * n
+	}
+
+	sumBackground, weightBackground, bestVariance, threshold := 0.0, 0.0, 0.0, 128
+	for value, n := range histogram {
+		weightBackground += n
+		if weightBackground == 0 {
+			continue
+		}
+		weightForeground := total - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+		sumBackground += float64(value) * n
+		meanBackground := sumBackground / weightBackground
+		meanForeground := (sum - sumBackground) / weightForeground
+		variance := weightBackground * weightForeground * (meanBackground - meanForeground) * (meanBackground - meanForeground)
+		if variance > bestVariance {
+			bestVariance, threshold = variance, value
+		}
+	}
+	return threshold
+}
+
+func distance(a, b point) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
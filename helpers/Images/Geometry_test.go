@@ -0,0 +1,113 @@
+package images
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// receiptPhoto menggambar kertas putih dengan baris teks hitam di atas meja gelap. corners adalah
+// sudut kertas berurutan kiri atas, kanan atas, kanan bawah, kiri bawah.
+func receiptPhoto(width int, height int, corners [4]point) *image.NRGBA {
+	img := imaging.New(width, height, color.NRGBA{R: 40, G: 40, B: 40, A: 255})
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if insideQuad(point{float64(x), float64(y)}, corners) {
+				img.SetNRGBA(x, y, color.NRGBA{R: 250, G: 250, B: 250, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func insideQuad(p point, corners [4]point) bool {
+	for i := range corners {
+		a, b := corners[i], corners[(i+1)%4]
+		if (b.x-a.x)*(p.y-a.y)-(b.y-a.y)*(p.x-a.x) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// textLines menggambar baris teks hitam horizontal pada kertas putih
+func textLines(width int, height int) *image.NRGBA {
+	img := imaging.New(width, height, color.White)
+	for y := 40; y+6 < height-40; y += 24 {
+		for x := 40; x < width-40; x++ {
+			if (x/12)%5 == 4 {
+				continue
+			}
+			for dy := 0; dy < 6; dy++ {
+				img.SetNRGBA(x, y+dy, color.NRGBA{A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestFindPaper(t *testing.T) {
+	tests := []struct {
+		name      string
+		corners   [4]point
+		found     bool
+		croppable bool
+		skewed    bool
+	}{
+		{name: "straight paper", corners: [4]point{{150, 100}, {450, 100}, {450, 700}, {150, 700}}, found: true, croppable: true},
+		{name: "photographed at an angle", corners: [4]point{{120, 100}, {480, 160}, {450, 720}, {140, 680}}, found: true, croppable: true, skewed: true},
+		{name: "paper fills the photo", corners: [4]point{{0, 0}, {600, 0}, {600, 800}, {0, 800}}, found: true},
+		{name: "paper too small", corners: [4]point{{250, 300}, {350, 300}, {350, 400}, {250, 400}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			img := receiptPhoto(600, 800, test.corners)
+			paper, found := findPaper(img)
+			if found != test.found {
+				t.Fatalf("findPaper() found = %v, want %v", found, test.found)
+			}
+			if !found {
+				return
+			}
+			if croppable := paper.croppable(img.Bounds()); croppable != test.croppable {
+				t.Errorf("croppable() = %v, want %v (bounds %v)", croppable, test.croppable, paper.bounds)
+			}
+			if skewed := paper.skewed(); skewed != test.skewed {
+				t.Errorf("skewed() = %v, want %v (corners %v)", skewed, test.skewed, paper.corners)
+			}
+			for i, corner := range paper.corners {
+				if distance(corner, test.corners[i]) > 12 {
+					t.Errorf("corner %d = %v, want near %v", i, corner, test.corners[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectSkew(t *testing.T) {
+	tests := []struct {
+		name  string
+		angle float64
+	}{
+		{name: "straight text", angle: 0},
+		{name: "rotated counter-clockwise", angle: 5},
+		{name: "rotated clockwise", angle: -3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			img := image.Image(textLines(500, 700))
+			if test.angle != 0 {
+				img = imaging.Rotate(img, test.angle, color.White)
+			}
+			// sudut koreksi memutar balik kemiringan gambar
+			if angle := detectSkew(img); math.Abs(angle+test.angle) > 0.5 {
+				t.Errorf("detectSkew() = %v, want %v", angle, -test.angle)
+			}
+		})
+	}
+}
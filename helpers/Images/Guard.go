@@ -0,0 +1,388 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Error yang dikembalikan UploadGuard; service memetakan masing-masing ke error code sendiri
+var (
+	ErrFileTooLarge       = errors.New("file too large")
+	ErrDimensionsTooLarge = errors.New("image dimensions too large")
+	ErrTooManyPixels      = errors.New("image pixel count too large")
+	ErrMediaTypeMismatch  = errors.New("media type mismatch")
+	ErrPolyglotFile       = errors.New("file contains embedded data of another format")
+)
+
+// extensionTypes memetakan ekstensi file ke MIME type. Ekstensi yang tidak ada di sini (atau tanpa
+// ekstensi) tidak diperiksa.
+var extensionTypes = map[string]string{
+	".jpg":  MIMETypeJPEG,
+	".jpeg": MIMETypeJPEG,
+	".jfif": MIMETypeJPEG,
+	".png":  MIMETypePNG,
+	".webp": MIMETypeWebP,
+	".heic": MIMETypeHEIC,
+	".heif": MIMETypeHEIF,
+	".avif": MIMETypeAVIF,
+	".gif":  "image/gif",
+	".bmp":  "image/bmp",
+	".svg":  "image/svg+xml",
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+	".htm":  "text/html",
+	".html": "text/html",
+	".js":   "text/javascript",
+	".php":  "application/x-php",
+	".exe":  "application/vnd.microsoft.portable-executable",
+}
+
+// mimeAliases adalah Content-Type lain yang dikirim client untuk format yang sama
+var mimeAliases = map[string]string{
+	"image/jpg":           MIMETypeJPEG,
+	"image/pjpeg":         MIMETypeJPEG,
+	"image/heic-sequence": MIMETypeHEIC,
+	"image/heif-sequence": MIMETypeHEIF,
+	"image/avif-sequence": MIMETypeAVIF,
+}
+
+// trailerSignatures adalah awal format lain yang tidak boleh muncul setelah akhir gambar
+var trailerSignatures = [][]byte{
+	[]byte("%PDF-"),
+	[]byte("PK\x03\x04"),
+	[]byte("Rar!\x1a\x07"),
+	[]byte("7z\xbc\xaf\x27\x1c"),
+	[]byte("\x7fELF"),
+}
+
+// reEmbeddedMarkup mencocokkan script atau markup yang bisa dieksekusi jika file dibuka sebagai HTML/PHP
+var reEmbeddedMarkup = regexp.MustCompile(`(?i)<\?php|<script|<html|<iframe|<svg`)
+
+// pdfHeaderWindow adalah jarak dari awal file tempat PDF reader masih menerima header %PDF-
+const pdfHeaderWindow = 1024
+
+// UploadGuardInterface membatasi file yang diunggah sebelum gambar didekode penuh
+type UploadGuardInterface interface {
+	Read(fileheader *multipart.FileHeader) ([]byte, error)
+	ReadStream(reader io.Reader, size int64) ([]byte, error)
+	Inspect(data []byte, declaredType string, filename string) error
+}
+
+// UploadGuard menolak file yang melebihi MaxBytes, gambar yang sisi terpanjangnya melebihi
+// MaxDimension atau jumlah pikselnya melebihi MaxPixels (dibaca dari header saja, sebelum decode
+// penuh), file yang isinya tidak cocok dengan Content-Type atau ekstensinya, dan file polyglot yang
+// menyisipkan format lain (PDF, ZIP, script) di dalam gambar.
+type UploadGuard struct {
+	MaxBytes     int64
+	MaxDimension int
+	MaxPixels    int
+}
+
+// NewUploadGuard membaca batas upload dari UPLOAD_MAX_BYTES, UPLOAD_MAX_DIMENSION dan UPLOAD_MAX_PIXELS
+func NewUploadGuard() *UploadGuard {
+	return &UploadGuard{
+		MaxBytes:     int64(envFloat("UPLOAD_MAX_BYTES", 10*1024*1024)),
+		MaxDimension: int(envFloat("UPLOAD_MAX_DIMENSION", 12000)),
+		MaxPixels:    int(envFloat("UPLOAD_MAX_PIXELS", 64000000)),
+	}
+}
+
+// Read membaca file yang diunggah sekali ke memori dan berhenti begitu ukurannya melewati MaxBytes
+func (guard *UploadGuard) Read(fileheader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileheader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening uploaded file: %v", err)
+	}
+	defer file.Close()
+	return guard.ReadStream(file, fileheader.Size)
+}
+
+// ReadStream membaca file dari reader, misalnya file di dalam arsip ZIP, dengan batas MaxBytes yang
+// sama. size adalah ukuran yang dilaporkan pengirim dan diperiksa sebelum membaca.
+func (guard *UploadGuard) ReadStream(reader io.Reader, size int64) ([]byte, error) {
+	if guard.MaxBytes > 0 && size > guard.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, maximum is %d", ErrFileTooLarge, size, guard.MaxBytes)
+	}
+	if guard.MaxBytes > 0 {
+		reader = io.LimitReader(reader, guard.MaxBytes+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading uploaded file: %v", err)
+	}
+	if guard.MaxBytes > 0 && int64(len(data)) > guard.MaxBytes {
+		return nil, fmt.Errorf("%w: maximum is %d bytes", ErrFileTooLarge, guard.MaxBytes)
+	}
+	return data, nil
+}
+
+// Inspect memeriksa gambar tanpa mendekode pikselnya. Format yang tidak dikenali dibiarkan lolos
+// supaya ditolak Normalizer sebagai unsupported media.
+func (guard *UploadGuard) Inspect(data []byte, declaredType string, filename string) error {
+	mimeType := DetectMIMEType(data)
+	if mimeType == "" {
+		return nil
+	}
+	if err := checkDeclaredType(mimeType, declaredType, filename); err != nil {
+		return err
+	}
+	if err := checkPolyglot(data, mimeType); err != nil {
+		return err
+	}
+
+	width, height, err := headerDimensions(data, mimeType)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read image header: %v", ErrUnsupportedMedia, err)
+	}
+	if guard.MaxDimension > 0 && (width > guard.MaxDimension || height > guard.MaxDimension) {
+		return fmt.Errorf("%w: %dx%d, maximum is %d pixels per side", ErrDimensionsTooLarge, width, height, guard.MaxDimension)
+	}
+	if guard.MaxPixels > 0 && width*height > guard.MaxPixels {
+		return fmt.Errorf("%w: %d pixels, maximum is %d", ErrTooManyPixels, width*height, guard.MaxPixels)
+	}
+	return nil
+}
+
+// checkDeclaredType membandingkan format hasil deteksi dengan Content-Type dan ekstensi file.
+// Content-Type umum seperti application/octet-stream dan ekstensi yang tidak dikenal diabaikan.
+func checkDeclaredType(mimeType string, declaredType string, filename string) error {
+	if declared, _, err := mime.ParseMediaType(declaredType); err == nil && strings.HasPrefix(declared, "image/") {
+		if alias, ok := mimeAliases[declared]; ok {
+			declared = alias
+		}
+		if !sameFormat(declared, mimeType) {
+			return fmt.Errorf("%w: declared %s but content is %s", ErrMediaTypeMismatch, declared, mimeType)
+		}
+	}
+	extension := strings.ToLower(filepath.Ext(filename))
+	if extensionType, ok := extensionTypes[extension]; ok && !sameFormat(extensionType, mimeType) {
+		return fmt.Errorf("%w: extension %s but content is %s", ErrMediaTypeMismatch, extension, mimeType)
+	}
+	return nil
+}
+
+// sameFormat menganggap HEIC dan HEIF sebagai format yang sama karena keduanya dipakai bergantian
+func sameFormat(a string, b string) bool {
+	heif := func(mimeType string) bool { return mimeType == MIMETypeHEIC || mimeType == MIMETypeHEIF }
+	return a == b || (heif(a) && heif(b))
+}
+
+// checkPolyglot menolak gambar yang juga valid sebagai format lain: header PDF di awal file, markup
+// atau script di awal file, di segmen metadata atau setelah akhir gambar, dan arsip/dokumen/
+// executable setelah akhir gambar. Data terkompresi gambar tidak diperiksa karena byte acak cukup
+// sering cocok dengan pola markup. Data lain setelah akhir gambar (misalnya trailer metadata kamera)
+// tetap diterima.
+func checkPolyglot(data []byte, mimeType string) error {
+	header := data[:min(len(data), pdfHeaderWindow)]
+	if bytes.Contains(header, []byte("%PDF-")) {
+		return fmt.Errorf("%w: PDF header inside image", ErrPolyglotFile)
+	}
+	end := imageEnd(data, mimeType)
+	trailer := data[end:]
+	for _, segment := range append(append([][]byte{header}, metadataSegments(data[:end], mimeType)...), trailer) {
+		if match := reEmbeddedMarkup.Find(segment); match != nil {
+			return fmt.Errorf("%w: embedded %q", ErrPolyglotFile, match)
+		}
+	}
+	for _, signature := range trailerSignatures {
+		if bytes.Contains(trailer, signature) {
+			return fmt.Errorf("%w: %q after end of image", ErrPolyglotFile, signature)
+		}
+	}
+	return nil
+}
+
+// imageEnd mencari offset akhir gambar menurut strukturnya. Jika struktur tidak bisa dibaca,
+// seluruh file dianggap gambar.
+func imageEnd(data []byte, mimeType string) int {
+	switch mimeType {
+	case MIMETypeJPEG:
+		return jpegEnd(data)
+	case MIMETypePNG:
+		return pngEnd(data)
+	case MIMETypeWebP:
+		size := int(binary.LittleEndian.Uint32(data[4:8]))
+		return min(8+size+size%2, len(data))
+	case MIMETypeHEIC, MIMETypeHEIF, MIMETypeAVIF:
+		return isoMediaEnd(data)
+	}
+	return len(data)
+}
+
+// metadataSegments mengembalikan bagian gambar yang berisi metadata atau teks bebas: segmen APPn dan
+// COM JPEG (EXIF, XMP), chunk teks PNG, chunk EXIF/XMP WebP dan box top-level HEIF/AVIF selain mdat.
+// Data piksel terkompresi tidak termasuk.
+func metadataSegments(data []byte, mimeType string) [][]byte {
+	segments := [][]byte{}
+	switch mimeType {
+	case MIMETypeJPEG:
+		for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+			marker := data[i+1]
+			if marker == 0xFF {
+				i++
+				continue
+			}
+			if marker == 0xDA || marker == 0xD9 {
+				break
+			}
+			length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+			if length < 2 {
+				break
+			}
+			if (marker >= 0xE0 && marker <= 0xEF) || marker == 0xFE {
+				segments = append(segments, data[i+4:min(i+2+length, len(data))])
+			}
+			i += 2 + length
+		}
+	case MIMETypePNG:
+		for i := 8; i+12 <= len(data); {
+			length := int(binary.BigEndian.Uint32(data[i : i+4]))
+			next := i + 12 + length
+			if length < 0 || next > len(data) {
+				break
+			}
+			switch string(data[i+4 : i+8]) {
+			case "tEXt", "iTXt":
+				segments = append(segments, data[i+8:i+8+length])
+			}
+			i = next
+		}
+	case MIMETypeWebP:
+		for i := 12; i+8 <= len(data); {
+			length := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+			next := i + 8 + length + length%2
+			if length < 0 || i+8+length > len(data) {
+				break
+			}
+			switch string(data[i : i+4]) {
+			case "XMP ", "EXIF":
+				segments = append(segments, data[i+8:i+8+length])
+			}
+			i = next
+		}
+	case MIMETypeHEIC, MIMETypeHEIF, MIMETypeAVIF:
+		for i := 0; i+8 <= len(data); {
+			size := int(binary.BigEndian.Uint32(data[i : i+4]))
+			if size == 1 && i+16 <= len(data) {
+				size = int(binary.BigEndian.Uint64(data[i+8 : i+16]))
+			}
+			if size < 8 || i+size > len(data) {
+				break
+			}
+			if string(data[i+4:i+8]) != "mdat" {
+				segments = append(segments, data[i:i+size])
+			}
+			i += size
+		}
+	}
+	return segments
+}
+
+// jpegEnd melompati segmen JPEG (termasuk thumbnail EXIF di dalam APP1) sampai marker EOI
+func jpegEnd(data []byte) int {
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return len(data)
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			i++
+			continue
+		case marker == 0xD9:
+			return i + 2
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			i += 2
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 {
+			return len(data)
+		}
+		i += 2 + length
+		if marker == 0xDA {
+			// Data entropy-coded berakhir di marker pertama yang bukan byte stuffing atau RST
+			for i+1 < len(data) && !(data[i] == 0xFF && data[i+1] != 0x00 && (data[i+1] < 0xD0 || data[i+1] > 0xD7)) {
+				i++
+			}
+		}
+	}
+	return len(data)
+}
+
+// pngEnd menelusuri chunk PNG sampai chunk IEND
+func pngEnd(data []byte) int {
+	i := 8
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		next := i + 12 + length
+		if length < 0 || next > len(data) {
+			return len(data)
+		}
+		if string(data[i+4:i+8]) == "IEND" {
+			return next
+		}
+		i = next
+	}
+	return len(data)
+}
+
+// isoMediaEnd menjumlahkan ukuran box top-level HEIF/AVIF
+func isoMediaEnd(data []byte) int {
+	i := 0
+	for i+8 <= len(data) {
+		size := int(binary.BigEndian.Uint32(data[i : i+4]))
+		switch {
+		case size == 0:
+			return len(data)
+		case size == 1 && i+16 <= len(data):
+			size = int(binary.BigEndian.Uint64(data[i+8 : i+16]))
+		}
+		if size < 8 || i+size > len(data) {
+			return len(data)
+		}
+		i += size
+	}
+	return i
+}
+
+// headerDimensions membaca lebar dan tinggi gambar dari header saja. HEIF/AVIF dibaca dari box
+// "ispe"; untuk gambar grid yang terdiri dari beberapa tile dipakai ukuran terbesar.
+func headerDimensions(data []byte, mimeType string) (int, int, error) {
+	switch mimeType {
+	case MIMETypeHEIC, MIMETypeHEIF, MIMETypeAVIF:
+		width, height := 0, 0
+		for offset := 0; ; {
+			index := bytes.Index(data[offset:], []byte("ispe"))
+			if index < 0 {
+				break
+			}
+			start := offset + index + 4
+			if start+12 > len(data) {
+				break
+			}
+			width = max(width, int(binary.BigEndian.Uint32(data[start+4:start+8])))
+			height = max(height, int(binary.BigEndian.Uint32(data[start+8:start+12])))
+			offset = start
+		}
+		if width == 0 || height == 0 {
+			return 0, 0, errors.New("missing ispe box")
+		}
+		return width, height, nil
+	}
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return imageConfig.Width, imageConfig.Height, nil
+}
@@ -0,0 +1,104 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+func encodeJPEG(t *testing.T, width int, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, imaging.New(width, height, color.White), imaging.JPEG); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withJPEGSegment menyisipkan segmen JPEG (misalnya COM 0xFE) tepat setelah marker SOI
+func withJPEGSegment(data []byte, marker byte, payload string) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+// heicWithSize membuat file HEIC minimal dengan box ispe berukuran width x height
+func heicWithSize(width uint32, height uint32) []byte {
+	data := append([]byte{}, ftyp("heic", "mif1", "heic")[:24]...)
+	meta := []byte("\x00\x00\x00\x1cmeta\x00\x00\x00\x14ispe\x00\x00\x00\x00")
+	meta = binary.BigEndian.AppendUint32(meta, width)
+	meta = binary.BigEndian.AppendUint32(meta, height)
+	return append(data, meta...)
+}
+
+func TestInspect(t *testing.T) {
+	png := encodePNG(t, imaging.New(300, 100, color.White))
+	jpeg := encodeJPEG(t, 300, 100)
+	tests := []struct {
+		name         string
+		data         []byte
+		declaredType string
+		filename     string
+		maxDimension int
+		maxPixels    int
+		err          error
+	}{
+		{name: "png", data: png, declaredType: "image/png", filename: "struk.png"},
+		{name: "jpeg with alias and generic type", data: jpeg, declaredType: "image/jpg", filename: "struk.JPG"},
+		{name: "octet-stream", data: jpeg, declaredType: "application/octet-stream", filename: "struk"},
+		{name: "content type mismatch", data: png, declaredType: "image/jpeg", filename: "struk.png", err: ErrMediaTypeMismatch},
+		{name: "extension mismatch", data: png, declaredType: "image/png", filename: "struk.pdf", err: ErrMediaTypeMismatch},
+		{name: "heic declared as heif", data: heicWithSize(4032, 3024), declaredType: "image/heif", filename: "IMG_0001.HEIC"},
+		{name: "too wide", data: png, maxDimension: 200, err: ErrDimensionsTooLarge},
+		{name: "too many pixels", data: png, maxPixels: 20000, err: ErrTooManyPixels},
+		{name: "heic too large", data: heicWithSize(20000, 100), maxDimension: 12000, err: ErrDimensionsTooLarge},
+		{name: "pdf header", data: append(append([]byte{}, jpeg[:20]...), append([]byte("%PDF-1.7"), jpeg[20:]...)...), err: ErrPolyglotFile},
+		{name: "zip after the image", data: append(append([]byte{}, png...), "PK\x03\x04payload"...), err: ErrPolyglotFile},
+		{name: "script after the image", data: append(append([]byte{}, jpeg...), "<script>alert(1)</script>"...), err: ErrPolyglotFile},
+		{name: "php in a comment segment", data: withJPEGSegment(jpeg, 0xFE, "<?php system($_GET['c']); ?>"), err: ErrPolyglotFile},
+		{name: "camera trailer", data: append(append([]byte{}, jpeg...), "\x00\x01camera metadata"...)},
+		{name: "unknown format", data: []byte("GIF89a<script>")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			guard := &UploadGuard{MaxDimension: test.maxDimension, MaxPixels: test.maxPixels}
+			err := guard.Inspect(test.data, test.declaredType, test.filename)
+			if !errors.Is(err, test.err) {
+				t.Errorf("Inspect() error = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestReadStream(t *testing.T) {
+	guard := &UploadGuard{MaxBytes: 10}
+	tests := []struct {
+		name string
+		data string
+		size int64
+		err  error
+	}{
+		{name: "within the limit", data: "0123456789", size: 10},
+		{name: "declared size too large", data: "0", size: 11, err: ErrFileTooLarge},
+		{name: "more data than declared", data: "0123456789a", size: 5, err: ErrFileTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := guard.ReadStream(strings.NewReader(test.data), test.size)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ReadStream() error = %v, want %v", err, test.err)
+			}
+			if test.err == nil && string(data) != test.data {
+				t.Errorf("ReadStream() = %q, want %q", data, test.data)
+			}
+		})
+	}
+}
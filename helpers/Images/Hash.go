@@ -0,0 +1,43 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"strconv"
+
+	"github.com/disintegration/imaging"
+)
+
+// PerceptualHash menghitung difference hash (dHash) 64-bit: gambar diperkecil menjadi 9x8 grayscale
+// lalu setiap bit menandai apakah piksel lebih terang dari tetangga kanannya. Foto yang sama setelah
+// di-resize, dikompres ulang atau sedikit berbeda pencahayaannya menghasilkan hash yang berdekatan.
+func PerceptualHash(imageData []byte) (string, error) {
+	img, err := imaging.Decode(bytes.NewReader(imageData), imaging.AutoOrientation(true))
+	if err != nil {
+		return "", fmt.Errorf("error decoding image for hashing: %w", err)
+	}
+	small := imaging.Grayscale(imaging.Resize(img, 9, 8, imaging.Box))
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.Pix[small.PixOffset(x, y)] > small.Pix[small.PixOffset(x+1, y)] {
+				hash |= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%016x", hash), nil
+}
+
+// HashDistance menghitung jumlah bit berbeda (Hamming distance) antara dua hasil PerceptualHash.
+// Hasilnya -1 jika salah satu hash tidak valid.
+func HashDistance(a string, b string) int {
+	first, errFirst := strconv.ParseUint(a, 16, 64)
+	second, errSecond := strconv.ParseUint(b, 16, 64)
+	if a == "" || b == "" || errFirst != nil || errSecond != nil {
+		return -1
+	}
+	return bits.OnesCount64(first ^ second)
+}
@@ -0,0 +1,29 @@
+package images
+
+import "testing"
+
+func TestHashDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "same hash", a: "f0e1d2c3b4a59687", b: "f0e1d2c3b4a59687", want: 0},
+		{name: "one bit", a: "0000000000000000", b: "0000000000000001", want: 1},
+		{name: "every bit", a: "0000000000000000", b: "ffffffffffffffff", want: 64},
+		{name: "case insensitive", a: "ABCDEF0123456789", b: "abcdef0123456789", want: 0},
+		{name: "short hash", a: "f", b: "0", want: 4},
+		{name: "empty", a: "", b: "0000000000000000", want: -1},
+		{name: "not hex", a: "zz", b: "0000000000000000", want: -1},
+		{name: "too long", a: "10000000000000000", b: "0000000000000000", want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := HashDistance(test.a, test.b); got != test.want {
+				t.Errorf("HashDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"strconv"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/disintegration/imaging"
+)
+
+// Nama langkah preprocessing yang tercatat di Processed.Steps
+const (
+	StepAutoOrient  = "auto_orient"
+	StepPerspective = "perspective"
+	StepAutoCrop    = "auto_crop"
+	StepDeskew      = "deskew"
+	StepContrast    = "contrast"
+	StepGrayscale   = "grayscale"
+	StepResize      = "resize"
+)
+
+// minEncodeWidth adalah lebar terkecil saat gambar diperkecil agar muat di MaxBytes
+const minEncodeWidth = 400
+
+// Processed adalah hasil preprocessing: gambar yang dikirim ke model dan disimpan ke bucket
+type Processed struct {
+	Data     []byte
+	MIMEType string
+	Steps    []string
+}
+
+// PreprocessorInterface menyiapkan foto struk sebelum diekstrak dan disimpan
+type PreprocessorInterface interface {
+	Process(imageData []byte, mimeType string) (Processed, error)
+}
+
+// Preprocessor adalah image pipeline bersama yang dijalankan sebelum penyimpanan dan ekstraksi.
+// Foto struk dari ponsel diperbaiki (orientasi EXIF, perspektif dan kemiringan, kontras, grayscale
+// opsional dan crop ke area kertas; setiap langkah bisa dimatikan sendiri), lalu di-resize ke
+// MaxWidth dan di-encode ke OutputFormat dengan ukuran paling besar MaxBytes.
+type Preprocessor struct {
+	AutoOrient   bool
+	Perspective  bool
+	AutoCrop     bool
+	Deskew       bool
+	Contrast     bool
+	Grayscale    bool
+	MaxWidth     int
+	MaxBytes     int
+	OutputFormat imaging.Format
+	JPEGQuality  int
+}
+
+// NewPreprocessor membaca konfigurasi preprocessing dari environment variable PREPROCESS_* dan
+// konfigurasi resize/encode dari IMAGE_*
+func NewPreprocessor() *Preprocessor {
+	outputFormat := imaging.JPEG
+	if os.Getenv("IMAGE_OUTPUT_FORMAT") == "png" {
+		outputFormat = imaging.PNG
+	}
+	return &Preprocessor{
+		AutoOrient:   envBool("PREPROCESS_AUTO_ORIENT", true),
+		Perspective:  envBool("PREPROCESS_PERSPECTIVE", true),
+		AutoCrop:     envBool("PREPROCESS_AUTO_CROP", true),
+		Deskew:       envBool("PREPROCESS_DESKEW", true),
+		Contrast:     envBool("PREPROCESS_CONTRAST", true),
+		Grayscale:    envBool("PREPROCESS_GRAYSCALE", false),
+		MaxWidth:     int(envFloat("IMAGE_MAX_WIDTH", 1600)),
+		MaxBytes:     int(envFloat("IMAGE_MAX_BYTES", 1*1024*1024)),
+		OutputFormat: outputFormat,
+		JPEGQuality:  min(max(int(envFloat("IMAGE_JPEG_QUALITY", 75)), 1), 100),
+	}
+}
+
+func (preprocessor *Preprocessor) Process(imageData []byte, mimeType string) (Processed, error) {
+	img, err := imaging.Decode(bytes.NewReader(imageData), imaging.AutoOrientation(preprocessor.AutoOrient))
+	if err != nil {
+		return Processed{}, fmt.Errorf("error decoding image for preprocessing: %w", err)
+	}
+
+	steps := []string{}
+	if preprocessor.AutoOrient {
+		steps = append(steps, StepAutoOrient)
+	}
+
+	paper, found := findPaper(img)
+	if preprocessor.Perspective && found && paper.skewed() {
+		img = warpPerspective(img, paper.corners)
+		steps = append(steps, StepPerspective)
+	} else if preprocessor.AutoCrop && found && paper.croppable(img.Bounds()) {
+		img = imaging.Crop(img, paper.bounds)
+		steps = append(steps, StepAutoCrop)
+	}
+
+	if preprocessor.Deskew {
+		if angle := detectSkew(img); angle != 0 {
+			img = imaging.Rotate(img, angle, color.White)
+			steps = append(steps, StepDeskew)
+		}
+	}
+	if preprocessor.Contrast {
+		if normalized, ok := normalizeContrast(img); ok {
+			img = normalized
+			steps = append(steps, StepContrast)
+		}
+	}
+	if preprocessor.Grayscale {
+		img = imaging.Grayscale(img)
+		steps = append(steps, StepGrayscale)
+	}
+
+	if preprocessor.MaxWidth > 0 && img.Bounds().Dx() > preprocessor.MaxWidth {
+		img = imaging.Resize(img, preprocessor.MaxWidth, 0, imaging.Lanczos)
+		steps = append(steps, StepResize)
+	}
+
+	encoded, err := preprocessor.encode(img)
+	if err != nil {
+		return Processed{}, err
+	}
+	config.GeneralLogger.Printf("[Preprocess Info] Applied %v, size %d -> %d bytes\n", steps, len(imageData), len(encoded))
+	return Processed{
+		Data:     encoded,
+		MIMEType: preprocessor.mimeType(),
+		Steps:    steps,
+	}, nil
+}
+
+// encode menyimpan gambar dalam OutputFormat. Jika hasilnya melebihi MaxBytes, kualitas JPEG
+// diturunkan sampai 50 lalu gambar diperkecil bertahap sampai muat atau selebar minEncodeWidth.
+func (preprocessor *Preprocessor) encode(img image.Image) ([]byte, error) {
+	quality := preprocessor.JPEGQuality
+	for {
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, img, preprocessor.OutputFormat, imaging.JPEGQuality(quality)); err != nil {
+			return nil, fmt.Errorf("error encoding preprocessed image: %w", err)
+		}
+		if preprocessor.MaxBytes <= 0 || buf.Len() <= preprocessor.MaxBytes {
+			return buf.Bytes(), nil
+		}
+
+		switch {
+		case preprocessor.OutputFormat == imaging.JPEG && quality > 50:
+			quality = max(quality-10, 50)
+		case img.Bounds().Dx() > minEncodeWidth:
+			img = imaging.Resize(img, max(img.Bounds().Dx()*8/10, minEncodeWidth), 0, imaging.Lanczos)
+		default:
+			config.GeneralLogger.Printf("[Preprocess Info] Image still %d bytes at minimum size, storing as is\n", buf.Len())
+			return buf.Bytes(), nil
+		}
+	}
+}
+
+func (preprocessor *Preprocessor) mimeType() string {
+	if preprocessor.OutputFormat == imaging.PNG {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// normalizeContrast meregangkan luminance antara persentil 1% dan 99% ke rentang penuh 0-255
+func normalizeContrast(img image.Image) (*image.NRGBA, bool) {
+	var histogram [256]int
+	gray := imaging.Grayscale(img)
+	for i := 0; i < len(gray.Pix); i += 4 {
+		histogram[gray.Pix[i]]++
+	}
+
+	total := len(gray.Pix) / 4
+	low, high := percentile(histogram, total, 0.01), percentile(histogram, total, 0.99)
+	if high-low < 10 || (low == 0 && high == 255) {
+		return nil, false
+	}
+
+	var lut [256]uint8
+	for value := range lut {
+		stretched := (value - low) * 255 / (high - low)
+		lut[value] = uint8(min(max(stretched, 0), 255))
+	}
+	return imaging.AdjustFunc(img, func(c color.NRGBA) color.NRGBA {
+		return color.NRGBA{R: lut[c.R], G: lut[c.G], B: lut[c.B], A: c.A}
+	}), true
+}
+
+func percentile(histogram [256]int, total int, fraction float64) int {
+	target, count := int(float64(total)*fraction), 0
+	for value, n := range histogram {
+		count += n
+		if count > target {
+			return value
+		}
+	}
+	return 255
+}
+
+func envBool(name string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(name))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
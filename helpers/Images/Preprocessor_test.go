@@ -0,0 +1,146 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, imaging.PNG); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// lowContrast adalah foto kusam dengan luminance di antara 100 dan 160
+func lowContrast(width int, height int) *image.NRGBA {
+	img := imaging.New(width, height, color.NRGBA{R: 160, G: 160, B: 160, A: 255})
+	for y := 0; y < height; y += 4 {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 100, G: 100, B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+func TestPreprocess(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+
+	straight := receiptPhoto(600, 800, [4]point{{150, 100}, {450, 100}, {450, 700}, {150, 700}})
+	angled := receiptPhoto(600, 800, [4]point{{120, 100}, {480, 160}, {450, 720}, {140, 680}})
+	tests := []struct {
+		name         string
+		preprocessor Preprocessor
+		img          image.Image
+		steps        []string
+		width        int
+		height       int
+	}{
+		{name: "crop to the paper", preprocessor: Preprocessor{AutoCrop: true}, img: straight, steps: []string{StepAutoCrop}, width: 312, height: 612},
+		{name: "perspective before crop", preprocessor: Preprocessor{Perspective: true, AutoCrop: true}, img: angled, steps: []string{StepPerspective}},
+		{name: "contrast", preprocessor: Preprocessor{Contrast: true}, img: lowContrast(200, 200), steps: []string{StepContrast}, width: 200, height: 200},
+		{name: "grayscale", preprocessor: Preprocessor{Grayscale: true}, img: imaging.New(50, 50, color.NRGBA{R: 200, G: 30, B: 30, A: 255}), steps: []string{StepGrayscale}, width: 50, height: 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.preprocessor.JPEGQuality = 90
+			processed, err := test.preprocessor.Process(encodePNG(t, test.img), "image/png")
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !slices.Equal(processed.Steps, test.steps) {
+				t.Errorf("steps = %v, want %v", processed.Steps, test.steps)
+			}
+			if processed.MIMEType != "image/jpeg" {
+				t.Errorf("MIME type = %q, want image/jpeg", processed.MIMEType)
+			}
+			img, err := imaging.Decode(bytes.NewReader(processed.Data))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if test.width > 0 && (abs(img.Bounds().Dx()-test.width) > 4 || abs(img.Bounds().Dy()-test.height) > 4) {
+				t.Errorf("size = %v, want about %dx%d", img.Bounds().Size(), test.width, test.height)
+			}
+			if test.preprocessor.Grayscale {
+				if r, g, b, _ := img.At(25, 25).RGBA(); r != g || g != b {
+					t.Errorf("pixel = %d,%d,%d, want gray", r, g, b)
+				}
+			}
+		})
+	}
+}
+
+// TestPreprocessEncode memastikan hasil pipeline di-resize ke MaxWidth dan di-encode ke OutputFormat
+// dengan ukuran paling besar MaxBytes, juga saat semua langkah perbaikan dimatikan
+func TestPreprocessEncode(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+
+	noise := imaging.New(1200, 1200, color.White)
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(i * 7919 % 251)
+	}
+	tests := []struct {
+		name         string
+		preprocessor Preprocessor
+		img          image.Image
+		steps        []string
+		mimeType     string
+		width        int
+		maxBytes     int
+	}{
+		{name: "encode only", preprocessor: Preprocessor{JPEGQuality: 75}, img: lowContrast(200, 100), steps: []string{}, mimeType: "image/jpeg", width: 200},
+		{name: "resize to max width", preprocessor: Preprocessor{MaxWidth: 300, JPEGQuality: 75}, img: lowContrast(600, 800), steps: []string{StepResize}, mimeType: "image/jpeg", width: 300},
+		{name: "png output", preprocessor: Preprocessor{OutputFormat: imaging.PNG}, img: lowContrast(200, 100), steps: []string{}, mimeType: "image/png", width: 200},
+		{name: "shrink to max bytes", preprocessor: Preprocessor{MaxBytes: 200 * 1024, JPEGQuality: 90}, img: noise, steps: []string{}, mimeType: "image/jpeg", maxBytes: 200 * 1024},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processed, err := test.preprocessor.Process(encodePNG(t, test.img), "image/png")
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !slices.Equal(processed.Steps, test.steps) {
+				t.Errorf("steps = %v, want %v", processed.Steps, test.steps)
+			}
+			if processed.MIMEType != test.mimeType {
+				t.Errorf("MIME type = %q, want %q", processed.MIMEType, test.mimeType)
+			}
+			img, err := imaging.Decode(bytes.NewReader(processed.Data))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if test.width > 0 && img.Bounds().Dx() != test.width {
+				t.Errorf("width = %d, want %d", img.Bounds().Dx(), test.width)
+			}
+			if test.maxBytes > 0 && len(processed.Data) > test.maxBytes && img.Bounds().Dx() > minEncodeWidth {
+				t.Errorf("size = %d bytes at width %d, want at most %d bytes", len(processed.Data), img.Bounds().Dx(), test.maxBytes)
+			}
+			if test.maxBytes > 0 && img.Bounds().Dx() == test.img.Bounds().Dx() {
+				t.Errorf("width = %d, want the image scaled down", img.Bounds().Dx())
+			}
+		})
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
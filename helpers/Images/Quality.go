@@ -0,0 +1,138 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/disintegration/imaging"
+)
+
+// qualityWidth adalah lebar gambar yang dipakai untuk mengukur ketajaman, sehingga skor
+// Laplacian variance bisa dibandingkan antar resolusi kamera.
+const qualityWidth = 1000
+
+// QualityCheckerInterface mengukur kualitas foto struk sebelum ekstraksi
+type QualityCheckerInterface interface {
+	Check(imageData []byte) (models.ImageQuality, error)
+}
+
+// QualityChecker menolak foto yang buram, terlalu terang, terlalu gelap atau resolusinya terlalu
+// kecil. Rasio exposure adalah bagian piksel yang luminance-nya hampir putih (>= 250) atau hampir
+// hitam (<= 10).
+type QualityChecker struct {
+	Enabled         bool
+	MinSharpness    float64
+	MaxOverexposed  float64
+	MaxUnderexposed float64
+	MinWidth        int
+	MinHeight       int
+}
+
+// NewQualityChecker membaca ambang kualitas dari environment variable QUALITY_*
+func NewQualityChecker() *QualityChecker {
+	return &QualityChecker{
+		Enabled:         envBool("QUALITY_CHECK_ENABLED", true),
+		MinSharpness:    envFloat("QUALITY_MIN_SHARPNESS", 60),
+		MaxOverexposed:  envFloat("QUALITY_MAX_OVEREXPOSED", 0.6),
+		MaxUnderexposed: envFloat("QUALITY_MAX_UNDEREXPOSED", 0.6),
+		MinWidth:        int(envFloat("QUALITY_MIN_WIDTH", 400)),
+		MinHeight:       int(envFloat("QUALITY_MIN_HEIGHT", 400)),
+	}
+}
+
+// Check menghitung skor kualitas. Issues berisi kode error untuk setiap ambang yang dilanggar,
+// berurutan dari yang paling menentukan (resolusi, exposure, blur); foto yang terlalu gelap atau
+// terang juga kehilangan tepi tajam sehingga blur dilaporkan terakhir.
+func (qualityChecker *QualityChecker) Check(imageData []byte) (models.ImageQuality, error) {
+	if !qualityChecker.Enabled {
+		return models.ImageQuality{Passed: true}, nil
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(imageData), imaging.AutoOrientation(true))
+	if err != nil {
+		return models.ImageQuality{}, fmt.Errorf("error decoding image for quality check: %w", err)
+	}
+
+	quality := models.ImageQuality{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+	gray := imaging.Grayscale(img)
+	if quality.Width > qualityWidth {
+		gray = imaging.Resize(gray, qualityWidth, 0, imaging.Box)
+	}
+
+	quality.Sharpness = round(laplacianVariance(gray))
+	var histogram [256]int
+	for i := 0; i < len(gray.Pix); i += 4 {
+		histogram[gray.Pix[i]]++
+	}
+	total, sum, bright, dark := len(gray.Pix)/4, 0, 0, 0
+	for value, n := range histogram {
+		sum += value * n
+		if value >= 250 {
+			bright += n
+		}
+		if value <= 10 {
+			dark += n
+		}
+	}
+	quality.Brightness = round(float64(sum) / float64(total))
+	quality.OverexposedRatio = round(float64(bright) / float64(total))
+	quality.UnderexposedRatio = round(float64(dark) / float64(total))
+
+	if quality.Width < qualityChecker.MinWidth || quality.Height < qualityChecker.MinHeight {
+		quality.Issues = append(quality.Issues, helpers.ErrCodeLowResolution)
+	}
+	if quality.OverexposedRatio > qualityChecker.MaxOverexposed {
+		quality.Issues = append(quality.Issues, helpers.ErrCodeOverexposed)
+	}
+	if quality.UnderexposedRatio > qualityChecker.MaxUnderexposed {
+		quality.Issues = append(quality.Issues, helpers.ErrCodeUnderexposed)
+	}
+	if quality.Sharpness < qualityChecker.MinSharpness {
+		quality.Issues = append(quality.Issues, helpers.ErrCodeImageBlurry)
+	}
+	quality.Passed = len(quality.Issues) == 0
+	return quality, nil
+}
+
+// laplacianVariance menghitung varians respons kernel Laplacian 4-tetangga. Foto buram memiliki
+// sedikit tepi tajam sehingga variansnya rendah.
+func laplacianVariance(gray *image.NRGBA) float64 {
+	width, height := gray.Bounds().Dx(), gray.Bounds().Dy()
+	if width < 3 || height < 3 {
+		return 0
+	}
+	luminance := func(x, y int) float64 { return float64(gray.Pix[gray.PixOffset(x, y)]) }
+
+	var sum, sumSquares float64
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			response := luminance(x-1, y) + luminance(x+1, y) + luminance(x, y-1) + luminance(x, y+1) - 4*luminance(x, y)
+			sum += response
+			sumSquares += response * response
+		}
+	}
+	count := float64((width - 2) * (height - 2))
+	mean := sum / count
+	return sumSquares/count - mean*mean
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func envFloat(name string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
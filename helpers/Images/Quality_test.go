@@ -0,0 +1,62 @@
+package images
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/disintegration/imaging"
+)
+
+// printedPage adalah textLines dengan warna kertas dan tinta yang diberikan
+func printedPage(width int, height int, paper uint8, ink uint8) *image.NRGBA {
+	return imaging.AdjustFunc(textLines(width, height), func(c color.NRGBA) color.NRGBA {
+		value := paper
+		if c.R < 128 {
+			value = ink
+		}
+		return color.NRGBA{R: value, G: value, B: value, A: 255}
+	})
+}
+
+func TestQualityCheck(t *testing.T) {
+	for _, name := range []string{"QUALITY_CHECK_ENABLED", "QUALITY_MIN_SHARPNESS", "QUALITY_MAX_OVEREXPOSED", "QUALITY_MAX_UNDEREXPOSED", "QUALITY_MIN_WIDTH", "QUALITY_MIN_HEIGHT"} {
+		t.Setenv(name, "")
+	}
+	sharp := printedPage(600, 800, 225, 20)
+	tests := []struct {
+		name    string
+		enabled string
+		img     image.Image
+		issues  []string
+	}{
+		{name: "sharp photo", img: sharp},
+		{name: "blurry photo", img: imaging.Blur(sharp, 8), issues: []string{helpers.ErrCodeImageBlurry}},
+		{name: "too dark", img: printedPage(600, 800, 8, 0), issues: []string{helpers.ErrCodeUnderexposed, helpers.ErrCodeImageBlurry}},
+		{name: "too bright", img: printedPage(600, 800, 255, 252), issues: []string{helpers.ErrCodeOverexposed, helpers.ErrCodeImageBlurry}},
+		{name: "low resolution", img: printedPage(300, 800, 225, 20), issues: []string{helpers.ErrCodeLowResolution}},
+		{name: "disabled", enabled: "false", img: imaging.Blur(sharp, 8)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("QUALITY_CHECK_ENABLED", test.enabled)
+			quality, err := NewQualityChecker().Check(encodePNG(t, test.img))
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !slices.Equal(quality.Issues, test.issues) {
+				t.Errorf("issues = %v, want %v (quality %+v)", quality.Issues, test.issues, quality)
+			}
+			if quality.Passed != (len(test.issues) == 0) {
+				t.Errorf("passed = %v with issues %v", quality.Passed, quality.Issues)
+			}
+		})
+	}
+
+	if _, err := NewQualityChecker().Check([]byte("not an image")); err == nil {
+		t.Errorf("Check() error = nil for data that is not an image")
+	}
+}
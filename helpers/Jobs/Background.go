@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// Loop background (upload ulang spool, purge cache, retry webhook) berjalan dengan context yang sama
+// dan dihentikan bersama-sama oleh Shutdown saat aplikasi berhenti
+var (
+	backgroundContext, stopBackground = context.WithCancel(context.Background())
+	backgroundLoops                   sync.WaitGroup
+)
+
+// Go menjalankan loop background. Loop harus berhenti setelah ctx dibatalkan.
+func Go(loop func(ctx context.Context)) {
+	backgroundLoops.Add(1)
+	go func() {
+		defer backgroundLoops.Done()
+		loop(backgroundContext)
+	}()
+}
+
+// Shutdown membatalkan context semua loop background lalu menunggu putaran yang sedang berjalan selesai
+func Shutdown() {
+	stopBackground()
+	backgroundLoops.Wait()
+}
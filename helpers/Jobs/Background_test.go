@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestShutdown memastikan Shutdown membatalkan context loop background dan menunggu loop selesai
+func TestShutdown(t *testing.T) {
+	var stopped atomic.Int32
+	for i := 0; i < 3; i++ {
+		Go(func(ctx context.Context) {
+			ticker := time.NewTicker(time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					// putaran terakhir tetap selesai sebelum Shutdown kembali
+					time.Sleep(10 * time.Millisecond)
+					stopped.Add(1)
+					return
+				case <-ticker.C:
+				}
+			}
+		})
+	}
+
+	Shutdown()
+	if got := stopped.Load(); got != 3 {
+		t.Errorf("stopped loops = %d, want 3", got)
+	}
+}
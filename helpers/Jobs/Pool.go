@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"os"
+	"strconv"
+	"sync"
+)
+
+// PoolInterface menjalankan job di background dengan jumlah worker dan panjang antrean terbatas
+type PoolInterface interface {
+	Start(handler func(jobID string))
+	TryEnqueue(jobID string) bool
+	Enqueue(jobID string)
+}
+
+// Pool adalah worker pool in-process. Job yang menunggu disimpan di channel berkapasitas QueueSize
+// dan diproses oleh Workers goroutine.
+type Pool struct {
+	Workers   int
+	QueueSize int
+	queue     chan string
+	once      sync.Once
+}
+
+// NewPool membaca jumlah worker dari JOB_WORKERS (default 4) dan panjang antrean dari
+// JOB_QUEUE_SIZE (default 100)
+func NewPool() *Pool {
+	workers, err := strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	if err != nil || workers <= 0 {
+		workers = 4
+	}
+	queueSize, err := strconv.Atoi(os.Getenv("JOB_QUEUE_SIZE"))
+	if err != nil || queueSize <= 0 {
+		queueSize = 100
+	}
+	return &Pool{
+		Workers:   workers,
+		QueueSize: queueSize,
+		queue:     make(chan string, queueSize),
+	}
+}
+
+// Start menjalankan worker; pemanggilan berikutnya diabaikan
+func (pool *Pool) Start(handler func(jobID string)) {
+	pool.once.Do(func() {
+		for i := 0; i < pool.Workers; i++ {
+			go func() {
+				for jobID := range pool.queue {
+					handler(jobID)
+				}
+			}()
+		}
+	})
+}
+
+// TryEnqueue memasukkan job ke antrean tanpa menunggu; false jika antrean penuh
+func (pool *Pool) TryEnqueue(jobID string) bool {
+	select {
+	case pool.queue <- jobID:
+		return true
+	default:
+		return false
+	}
+}
+
+// Enqueue memasukkan job ke antrean dan menunggu jika antrean penuh
+func (pool *Pool) Enqueue(jobID string) {
+	pool.queue <- jobID
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	t.Setenv("JOB_WORKERS", "1")
+	t.Setenv("JOB_QUEUE_SIZE", "2")
+	pool := NewPool()
+
+	// sebelum worker berjalan antrean hanya menampung JOB_QUEUE_SIZE job
+	for _, jobID := range []string{"job-1", "job-2"} {
+		if !pool.TryEnqueue(jobID) {
+			t.Fatalf("TryEnqueue(%s) = false, want true", jobID)
+		}
+	}
+	if pool.TryEnqueue("job-3") {
+		t.Fatalf("TryEnqueue(job-3) = true on a full queue")
+	}
+
+	handled := make(chan string)
+	pool.Start(func(jobID string) {
+		handled <- jobID
+	})
+	pool.Start(func(jobID string) {
+		t.Errorf("second handler ran job %s", jobID)
+	})
+	pool.Enqueue("job-3")
+
+	jobIDs := []string{}
+	for len(jobIDs) < 3 {
+		select {
+		case jobID := <-handled:
+			jobIDs = append(jobIDs, jobID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("handled %v, want 3 jobs", jobIDs)
+		}
+	}
+	if !slices.Equal(jobIDs, []string{"job-1", "job-2", "job-3"}) {
+		t.Errorf("handled %v, want jobs in queue order", jobIDs)
+	}
+}
@@ -0,0 +1,53 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExtractionTimeout membaca batas waktu ekstraksi dari EXTRACTION_TIMEOUT (default 2m)
+func ExtractionTimeout() time.Duration {
+	return durationEnv("EXTRACTION_TIMEOUT", 2*time.Minute)
+}
+
+// RequestDeadline adalah batas waktu ekstraksi satu request, dihitung sejak request diterima
+func RequestDeadline(app *fiber.Ctx) time.Time {
+	return app.Context().Time().Add(ExtractionTimeout())
+}
+
+// RequestContext mengembalikan context panggilan model untuk satu request: turunan UserContext
+// request dengan deadline RequestDeadline yang juga dibatalkan jika client memutus koneksi (lihat
+// watchDisconnect). cancel wajib dipanggil setelah ekstraksi selesai. Mode stream membatalkan ctx
+// sendiri saat event gagal ditulis ke client.
+func RequestContext(app *fiber.Ctx) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithDeadline(app.UserContext(), RequestDeadline(app))
+	stop := watchDisconnect(app.Context().Conn(), cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// WithDefaultDeadline memberi deadline EXTRACTION_TIMEOUT pada ctx yang belum memilikinya, misalnya
+// ctx job async yang tidak terikat request
+func WithDefaultDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, ExtractionTimeout())
+}
+
+// ContextError mengubah err menjadi ApiError 504 extraction_timeout jika ctx berhenti karena
+// deadline terlewati. Error lain dikembalikan apa adanya.
+func ContextError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return helpers.NewApiError(fiber.StatusGatewayTimeout, helpers.ErrCodeExtractionTimeout,
+			fmt.Sprintf("extraction did not finish within %v", ExtractionTimeout()), nil)
+	}
+	return err
+}
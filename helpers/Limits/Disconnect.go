@@ -0,0 +1,48 @@
+package limits
+
+import (
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// disconnectPollInterval adalah jeda pemeriksaan koneksi client selama request biasa berjalan
+const disconnectPollInterval = 500 * time.Millisecond
+
+// watchDisconnect memanggil disconnected jika client menutup koneksi sebelum stop dipanggil.
+// fasthttp tidak membaca socket selama handler berjalan, sehingga koneksi diperiksa dengan peek
+// tanpa mengambil data: EOF atau reset berarti client terputus, sedangkan data yang masuk (misalnya
+// request pipelined) dibiarkan untuk dibaca fasthttp. Koneksi tanpa file descriptor (TLS, koneksi
+// palsu di test) tidak dipantau.
+func watchDisconnect(conn net.Conn, disconnected func()) (stop func()) {
+	syscallConn, ok := conn.(syscall.Conn)
+	if !ok {
+		return func() {}
+	}
+	rawConn, err := syscallConn.SyscallConn()
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(disconnectPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if peerClosed(rawConn) {
+				disconnected()
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
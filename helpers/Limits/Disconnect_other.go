@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package limits
+
+import "syscall"
+
+// peerClosed tidak didukung di platform ini, sehingga hanya deadline yang membatalkan request
+func peerClosed(rawConn syscall.RawConn) bool {
+	return false
+}
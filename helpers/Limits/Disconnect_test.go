@@ -0,0 +1,85 @@
+package limits
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// connPair membuat koneksi TCP loopback dan mengembalikan sisi server dan sisi client
+func connPair(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	server, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return server, client
+}
+
+func TestWatchDisconnect(t *testing.T) {
+	server, client := connPair(t)
+	disconnected := make(chan struct{})
+	stop := watchDisconnect(server, func() { close(disconnected) })
+	defer stop()
+
+	client.Close()
+	select {
+	case <-disconnected:
+	case <-time.After(5 * disconnectPollInterval):
+		t.Fatal("closing the client connection was not detected")
+	}
+}
+
+func TestWatchDisconnectPipelinedData(t *testing.T) {
+	server, client := connPair(t)
+	disconnected := make(chan struct{}, 1)
+	stop := watchDisconnect(server, func() { disconnected <- struct{}{} })
+
+	// Data yang dikirim client selama request berjalan bukan tanda terputus dan tidak boleh diambil
+	request := "GET / HTTP/1.1\r\n\r\n"
+	if _, err := client.Write([]byte(request)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	time.Sleep(3 * disconnectPollInterval)
+	stop()
+	select {
+	case <-disconnected:
+		t.Fatal("pending client data was reported as a disconnect")
+	default:
+	}
+
+	buf := make([]byte, len(request))
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(server, buf); err != nil || string(buf) != request {
+		t.Errorf("read after watch = %q, %v, want %q", buf, err, request)
+	}
+}
+
+func TestWatchDisconnectStop(t *testing.T) {
+	server, client := connPair(t)
+	disconnected := make(chan struct{}, 1)
+	stop := watchDisconnect(server, func() { disconnected <- struct{}{} })
+	stop()
+	stop()
+
+	client.Close()
+	select {
+	case <-disconnected:
+		t.Fatal("disconnect reported after stop")
+	case <-time.After(3 * disconnectPollInterval):
+	}
+}
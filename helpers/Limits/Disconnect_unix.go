@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package limits
+
+import "syscall"
+
+// peerClosed mengintip satu byte dari socket tanpa menunggu dan tanpa mengambilnya dari buffer
+func peerClosed(rawConn syscall.RawConn) bool {
+	closed := false
+	err := rawConn.Control(func(fd uintptr) {
+		var buf [1]byte
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		switch err {
+		case nil:
+			closed = n == 0
+		case syscall.EAGAIN, syscall.EINTR:
+		default:
+			closed = true
+		}
+	})
+	return err != nil || closed
+}
@@ -0,0 +1,298 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// waitBuckets adalah batas atas bucket histogram waktu tunggu (milidetik)
+var waitBuckets = []int64{10, 100, 1000, 5000, 30000}
+
+type backgroundKey struct{}
+
+// Background menandai ctx milik job async atau batch. Pemanggil background menunggu slot tanpa
+// batas antrean dan timeout karena tidak ada client yang bisa diberi 429/503.
+func Background(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundKey{}, true)
+}
+
+func isBackground(ctx context.Context) bool {
+	background, _ := ctx.Value(backgroundKey{}).(bool)
+	return background
+}
+
+type extractionSlotKey struct{}
+
+// extractionSlot adalah slot limiter yang dipakai bersama semua panggilan model satu ekstraksi
+type extractionSlot struct {
+	mutex   sync.Mutex
+	release func()
+}
+
+// WithExtractionSlot menandai ctx sebagai satu ekstraksi: panggilan model pertama (misalnya
+// klasifikasi) mengambil slot limiter dan panggilan berikutnya memakai slot yang sama, sehingga
+// ekstraksi yang sudah berjalan tidak mengantre lagi di tengah jalan. Hasil dari cache dan parser
+// berbasis aturan tidak mengambil slot. release wajib dipanggil setelah ekstraksi selesai.
+func WithExtractionSlot(ctx context.Context) (context.Context, func()) {
+	slot := &extractionSlot{}
+	return context.WithValue(ctx, extractionSlotKey{}, slot), slot.releaseSlot
+}
+
+func (slot *extractionSlot) acquire(ctx context.Context, limiter *Limiter) (func(), error) {
+	slot.mutex.Lock()
+	defer slot.mutex.Unlock()
+	if slot.release == nil {
+		release, err := limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		slot.release = release
+	}
+	return func() {}, nil
+}
+
+func (slot *extractionSlot) releaseSlot() {
+	slot.mutex.Lock()
+	defer slot.mutex.Unlock()
+	if slot.release != nil {
+		slot.release()
+		slot.release = nil
+	}
+}
+
+// LimiterInterface membatasi jumlah panggilan model yang berjalan bersamaan di seluruh aplikasi.
+// Acquire mengembalikan fungsi release yang wajib dipanggil setelah panggilan model selesai.
+type LimiterInterface interface {
+	Acquire(ctx context.Context) (func(), error)
+	Metrics() models.ExtractionMetrics
+}
+
+// Limiter adalah semaphore global dengan antrean tunggu FIFO terbatas. Request yang datang saat semua
+// slot terpakai atau masih ada yang menunggu masuk ke belakang antrean dan menunggu paling lama
+// QueueTimeout; jika sudah ada QueueSize request yang menunggu, request langsung ditolak dengan 429.
+// Kedua penolakan membawa Retry-After. Slot yang dilepas langsung diberikan ke waiter terdepan.
+type Limiter struct {
+	MaxInFlight  int
+	QueueSize    int
+	QueueTimeout time.Duration
+	RetryAfter   time.Duration
+
+	mutex            sync.Mutex
+	inFlight         int
+	waiters          []*waiter
+	queued           int
+	queuedBackground int
+	acquired         int64
+	rejected         int64
+	timedOut         int64
+	canceled         int64
+	waitTotal        time.Duration
+	waitMax          time.Duration
+	waitBuckets      []int64
+}
+
+// NewLimiter membaca jumlah panggilan model bersamaan dari EXTRACTION_MAX_IN_FLIGHT (default 8),
+// panjang antrean dari EXTRACTION_QUEUE_SIZE (default 32), lama tunggu maksimum dari
+// EXTRACTION_QUEUE_TIMEOUT (default 30s) dan nilai Retry-After dari EXTRACTION_RETRY_AFTER
+// (default 10s).
+func NewLimiter() *Limiter {
+	maxInFlight, err := strconv.Atoi(os.Getenv("EXTRACTION_MAX_IN_FLIGHT"))
+	if err != nil || maxInFlight <= 0 {
+		maxInFlight = 8
+	}
+	queueSize, err := strconv.Atoi(os.Getenv("EXTRACTION_QUEUE_SIZE"))
+	if err != nil || queueSize < 0 {
+		queueSize = 32
+	}
+	return &Limiter{
+		MaxInFlight:  maxInFlight,
+		QueueSize:    queueSize,
+		QueueTimeout: durationEnv("EXTRACTION_QUEUE_TIMEOUT", 30*time.Second),
+		RetryAfter:   durationEnv("EXTRACTION_RETRY_AFTER", 10*time.Second),
+		waitBuckets:  make([]int64, len(waitBuckets)+1),
+	}
+}
+
+// waiter adalah satu pemanggil Acquire di antrean. ready menerima slot yang diberikan releaseSlot.
+type waiter struct {
+	ready      chan struct{}
+	background bool
+}
+
+// Acquire menunggu slot kosong. Error berupa *helpers.ApiError dengan kode extraction_queue_full
+// (429) atau extraction_queue_timeout (503), atau error ctx jika pemanggil berhenti menunggu.
+// Di dalam WithExtractionSlot, slot yang sudah didapat ekstraksi yang sama dipakai ulang.
+func (limiter *Limiter) Acquire(ctx context.Context) (func(), error) {
+	if slot, ok := ctx.Value(extractionSlotKey{}).(*extractionSlot); ok {
+		return slot.acquire(ctx, limiter)
+	}
+	return limiter.acquire(ctx)
+}
+
+func (limiter *Limiter) acquire(ctx context.Context) (func(), error) {
+	background := isBackground(ctx)
+	limiter.mutex.Lock()
+	// Slot kosong hanya diambil langsung jika tidak ada yang menunggu, sehingga pendatang baru
+	// tidak mendahului antrean
+	if limiter.inFlight < limiter.MaxInFlight && len(limiter.waiters) == 0 {
+		limiter.inFlight++
+		limiter.mutex.Unlock()
+		limiter.recordWait(0)
+		return limiter.releaseFunc(), nil
+	}
+	if !background && limiter.queued >= limiter.QueueSize {
+		limiter.rejected++
+		limiter.mutex.Unlock()
+		return nil, helpers.NewApiError(fiber.StatusTooManyRequests, helpers.ErrCodeExtractionQueueFull,
+			fmt.Sprintf("too many extractions in progress, %d request(s) already waiting, please retry later", limiter.QueueSize), nil).WithRetryAfter(limiter.RetryAfter)
+	}
+	current := &waiter{ready: make(chan struct{}, 1), background: background}
+	limiter.waiters = append(limiter.waiters, current)
+	limiter.addQueued(background, 1)
+	limiter.mutex.Unlock()
+
+	started := time.Now()
+	var timeout <-chan time.Time
+	if !background {
+		timer := time.NewTimer(limiter.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	timedOut := false
+	select {
+	case <-current.ready:
+		limiter.recordWait(time.Since(started))
+		return limiter.releaseFunc(), nil
+	case <-timeout:
+		timedOut = true
+		err = helpers.NewApiError(fiber.StatusServiceUnavailable, helpers.ErrCodeExtractionQueueTimeout,
+			fmt.Sprintf("no extraction slot became available within %v, please retry later", limiter.QueueTimeout), nil).WithRetryAfter(limiter.RetryAfter)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	limiter.mutex.Lock()
+	if timedOut {
+		limiter.timedOut++
+	} else {
+		limiter.canceled++
+	}
+	if !limiter.removeWaiter(current) {
+		// Slot sudah diberikan bersamaan dengan timeout atau pembatalan; teruskan ke waiter berikutnya
+		limiter.mutex.Unlock()
+		<-current.ready
+		limiter.releaseSlot()
+		return nil, err
+	}
+	limiter.mutex.Unlock()
+	return nil, err
+}
+
+// Metrics mengembalikan jumlah slot terpakai, kedalaman antrean, jumlah penolakan dan waktu tunggu
+func (limiter *Limiter) Metrics() models.ExtractionMetrics {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	metrics := models.ExtractionMetrics{
+		MaxInFlight:      limiter.MaxInFlight,
+		InFlight:         limiter.inFlight,
+		QueueSize:        limiter.QueueSize,
+		Queued:           limiter.queued,
+		QueuedBackground: limiter.queuedBackground,
+		QueueTimeoutMs:   limiter.QueueTimeout.Milliseconds(),
+		Acquired:         limiter.acquired,
+		Rejected:         limiter.rejected,
+		TimedOut:         limiter.timedOut,
+		Canceled:         limiter.canceled,
+		Wait: models.WaitMetrics{
+			Count:   limiter.acquired,
+			TotalMs: limiter.waitTotal.Milliseconds(),
+			MaxMs:   limiter.waitMax.Milliseconds(),
+			Buckets: map[string]int64{},
+		},
+	}
+	if limiter.acquired > 0 {
+		metrics.Wait.AvgMs = limiter.waitTotal.Milliseconds() / limiter.acquired
+	}
+	for i, bound := range waitBuckets {
+		metrics.Wait.Buckets[strconv.FormatInt(bound, 10)] = limiter.waitBuckets[i]
+	}
+	metrics.Wait.Buckets["+Inf"] = limiter.waitBuckets[len(waitBuckets)]
+	return metrics
+}
+
+// releaseFunc mengembalikan slot; pemanggilan berikutnya diabaikan
+func (limiter *Limiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(limiter.releaseSlot)
+	}
+}
+
+// releaseSlot memberikan slot ke waiter terdepan, atau mengosongkannya jika tidak ada yang menunggu
+func (limiter *Limiter) releaseSlot() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	if len(limiter.waiters) == 0 {
+		limiter.inFlight--
+		return
+	}
+	next := limiter.waiters[0]
+	limiter.waiters = limiter.waiters[1:]
+	limiter.addQueued(next.background, -1)
+	next.ready <- struct{}{}
+}
+
+// removeWaiter mengeluarkan waiter dari antrean; false berarti waiter sudah diberi slot
+func (limiter *Limiter) removeWaiter(target *waiter) bool {
+	for i, current := range limiter.waiters {
+		if current == target {
+			limiter.waiters = append(limiter.waiters[:i], limiter.waiters[i+1:]...)
+			limiter.addQueued(target.background, -1)
+			return true
+		}
+	}
+	return false
+}
+
+func (limiter *Limiter) addQueued(background bool, delta int) {
+	if background {
+		limiter.queuedBackground += delta
+	} else {
+		limiter.queued += delta
+	}
+}
+
+func (limiter *Limiter) recordWait(wait time.Duration) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.acquired++
+	limiter.waitTotal += wait
+	if wait > limiter.waitMax {
+		limiter.waitMax = wait
+	}
+	bucket := len(waitBuckets)
+	for i, bound := range waitBuckets {
+		if wait.Milliseconds() <= bound {
+			bucket = i
+			break
+		}
+	}
+	limiter.waitBuckets[bucket]++
+}
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
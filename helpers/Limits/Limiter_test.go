@@ -0,0 +1,202 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+func newTestLimiter(maxInFlight int, queueSize int, queueTimeout time.Duration) *Limiter {
+	return &Limiter{
+		MaxInFlight:  maxInFlight,
+		QueueSize:    queueSize,
+		QueueTimeout: queueTimeout,
+		RetryAfter:   5 * time.Second,
+		waitBuckets:  make([]int64, len(waitBuckets)+1),
+	}
+}
+
+// waitQueued menunggu sampai jumlah request (bukan background) di antrean mencapai queued
+func waitQueued(t *testing.T, limiter *Limiter, queued int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for limiter.Metrics().Queued < queued {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue did not reach %d waiting request(s)", queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiterAcquire(t *testing.T) {
+	tests := []struct {
+		name         string
+		queueSize    int
+		queueTimeout time.Duration
+		waiting      int
+		status       int
+		code         string
+	}{
+		{name: "queue full", queueSize: 1, queueTimeout: time.Minute, waiting: 1, status: fiber.StatusTooManyRequests, code: helpers.ErrCodeExtractionQueueFull},
+		{name: "no queue", queueSize: 0, queueTimeout: time.Minute, status: fiber.StatusTooManyRequests, code: helpers.ErrCodeExtractionQueueFull},
+		{name: "queue timeout", queueSize: 1, queueTimeout: 20 * time.Millisecond, status: fiber.StatusServiceUnavailable, code: helpers.ErrCodeExtractionQueueTimeout},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limiter := newTestLimiter(1, test.queueSize, test.queueTimeout)
+			release, err := limiter.Acquire(context.Background())
+			if err != nil {
+				t.Fatalf("first Acquire() error = %v", err)
+			}
+			defer release()
+
+			// Request lain yang sudah menunggu dihentikan lewat ctx setelah test selesai
+			waitCtx, stopWaiting := context.WithCancel(context.Background())
+			defer stopWaiting()
+			for i := 0; i < test.waiting; i++ {
+				go limiter.Acquire(waitCtx)
+			}
+			waitQueued(t, limiter, test.waiting)
+
+			_, err = limiter.Acquire(context.Background())
+			var apiError *helpers.ApiError
+			if !errors.As(err, &apiError) {
+				t.Fatalf("Acquire() error = %v, want *helpers.ApiError", err)
+			}
+			if apiError.Status != test.status || apiError.Code != test.code {
+				t.Errorf("Acquire() error = %d %s, want %d %s", apiError.Status, apiError.Code, test.status, test.code)
+			}
+			if apiError.RetryAfter != limiter.RetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", apiError.RetryAfter, limiter.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestLimiterAcquireBackground(t *testing.T) {
+	limiter := newTestLimiter(1, 0, 10*time.Millisecond)
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+
+	// Pemanggil background tidak ditolak walaupun antrean penuh dan menunggu lebih lama dari QueueTimeout
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := limiter.Acquire(Background(context.Background()))
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	time.Sleep(5 * limiter.QueueTimeout)
+	select {
+	case err := <-acquired:
+		t.Fatalf("background Acquire() returned before a slot was free: %v", err)
+	default:
+	}
+	if queued := limiter.Metrics().QueuedBackground; queued != 1 {
+		t.Errorf("QueuedBackground = %d, want 1", queued)
+	}
+
+	release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("background Acquire() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("background Acquire() did not get the released slot")
+	}
+}
+
+func TestLimiterAcquireCanceled(t *testing.T) {
+	limiter := newTestLimiter(1, 1, time.Minute)
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+	if canceled := limiter.Metrics().Canceled; canceled != 1 {
+		t.Errorf("Canceled = %d, want 1", canceled)
+	}
+}
+
+// TestLimiterAcquireFIFO memastikan slot yang dilepas diberikan ke waiter terdepan dan pendatang
+// baru tidak mendahului antrean walaupun datang tepat saat slot dilepas
+func TestLimiterAcquireFIFO(t *testing.T) {
+	limiter := newTestLimiter(1, 3, time.Minute)
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+
+	order := make(chan int, 3)
+	for i := 1; i <= 2; i++ {
+		go func() {
+			release, err := limiter.Acquire(context.Background())
+			if err != nil {
+				t.Errorf("waiter %d Acquire() error = %v", i, err)
+				return
+			}
+			order <- i
+			time.Sleep(5 * time.Millisecond)
+			release()
+		}()
+		waitQueued(t, limiter, i)
+	}
+
+	release()
+	release, err = limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("newcomer Acquire() error = %v", err)
+	}
+	order <- 3
+	release()
+
+	for want := 1; want <= 3; want++ {
+		if got := <-order; got != want {
+			t.Fatalf("slot %d went to caller %d, want %d", want, got, want)
+		}
+	}
+	if metrics := limiter.Metrics(); metrics.InFlight != 0 || metrics.Queued != 0 {
+		t.Errorf("metrics after release = %d in flight, %d queued, want 0 and 0", metrics.InFlight, metrics.Queued)
+	}
+}
+
+func TestWithExtractionSlot(t *testing.T) {
+	limiter := newTestLimiter(1, 0, 10*time.Millisecond)
+	ctx, releaseExtraction := WithExtractionSlot(context.Background())
+
+	// Semua panggilan model satu ekstraksi memakai satu slot, termasuk saat limiter sudah penuh
+	for i := 0; i < 3; i++ {
+		release, err := limiter.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("call %d Acquire() error = %v", i+1, err)
+		}
+		release()
+		if inFlight := limiter.Metrics().InFlight; inFlight != 1 {
+			t.Fatalf("InFlight after call %d = %d, want 1 until the extraction ends", i+1, inFlight)
+		}
+	}
+	if _, err := limiter.Acquire(context.Background()); err == nil {
+		t.Error("Acquire() of another extraction succeeded while the slot is held")
+	}
+
+	releaseExtraction()
+	releaseExtraction()
+	if inFlight := limiter.Metrics().InFlight; inFlight != 0 {
+		t.Errorf("InFlight after the extraction = %d, want 0", inFlight)
+	}
+}
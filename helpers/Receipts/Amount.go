@@ -0,0 +1,67 @@
+package receipts
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseAmount membaca nominal dengan format Indonesia ("25.000", "25.000,00", "Rp25.000") maupun
+// format internasional ("25,000.00").
+func ParseAmount(s string) (float64, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	negative := strings.HasPrefix(s, "-") || strings.HasSuffix(s, "-") || strings.HasPrefix(s, "(")
+	s = strings.Trim(s, "()- ")
+	s = strings.TrimPrefix(s, "RP.")
+	s = strings.TrimPrefix(s, "RP")
+	s = strings.Trim(s, " -")
+	if s == "" {
+		return 0, false
+	}
+
+	lastDot := strings.LastIndex(s, ".")
+	lastComma := strings.LastIndex(s, ",")
+	decimalSep := -1
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		decimalSep = max(lastDot, lastComma)
+	case lastDot >= 0 || lastComma >= 0:
+		// Satu jenis pemisah: tiga digit di belakangnya berarti pemisah ribuan
+		sep := max(lastDot, lastComma)
+		if len(s)-sep-1 != 3 {
+			decimalSep = sep
+		}
+	}
+
+	intPart, fracPart := s, ""
+	if decimalSep >= 0 {
+		intPart, fracPart = s[:decimalSep], s[decimalSep+1:]
+	}
+	intPart = strings.NewReplacer(".", "", ",", "").Replace(intPart)
+	if strings.ContainsAny(fracPart, ".,") {
+		return 0, false
+	}
+
+	number := intPart
+	if fracPart != "" {
+		number += "." + fracPart
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		value = -value
+	}
+	return value, true
+}
+
+// AmountOrZero sama seperti ParseAmount tetapi mengembalikan 0 untuk nilai kosong atau tidak valid.
+func AmountOrZero(s string) float64 {
+	value, _ := ParseAmount(s)
+	return value
+}
+
+// FormatAmount mengembalikan nominal dalam format desimal tanpa pemisah ribuan ("220000.00").
+func FormatAmount(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
@@ -0,0 +1,35 @@
+package receipts
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+		ok    bool
+	}{
+		{input: "25.000", want: 25000, ok: true},
+		{input: "25.000,50", want: 25000.5, ok: true},
+		{input: "Rp25.000", want: 25000, ok: true},
+		{input: "Rp. 1.250.000", want: 1250000, ok: true},
+		{input: "25,000.00", want: 25000, ok: true},
+		{input: "61050.00", want: 61050, ok: true},
+		{input: "12,5", want: 12.5, ok: true},
+		{input: "-5.000", want: -5000, ok: true},
+		{input: "5.000-", want: -5000, ok: true},
+		{input: "(5.000)", want: -5000, ok: true},
+		{input: "", ok: false},
+		{input: "Rp", ok: false},
+		{input: "abc", ok: false},
+		{input: "1,2.5", want: 12.5, ok: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, ok := ParseAmount(test.input)
+			if ok != test.ok || got != test.want {
+				t.Errorf("ParseAmount(%q) = %v, %v, want %v, %v", test.input, got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
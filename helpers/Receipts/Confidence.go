@@ -0,0 +1,106 @@
+package receipts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// NormalizeConfidence merapikan confidence dan bounding box dari model: key field dirapikan, nilai
+// yang bukan angka dibuang, confidence baris item yang kosong diisi dengan confidence field item
+// terendah, dan bounding box yang tidak valid dibuang.
+func NormalizeConfidence(receipt models.SplitbillResponse) models.SplitbillResponse {
+	if len(receipt.FieldConfidence) > 0 {
+		fields := make(map[string]models.Score, len(receipt.FieldConfidence))
+		for path, score := range receipt.FieldConfidence {
+			if score > 0 {
+				fields[fieldPath(path)] = score
+			}
+		}
+		receipt.FieldConfidence = fields
+		if len(fields) == 0 {
+			receipt.FieldConfidence = nil
+		}
+	}
+
+	if len(receipt.FieldBoundingBoxes) > 0 {
+		boxes := make(map[string]*models.BoundingBox, len(receipt.FieldBoundingBoxes))
+		for path, box := range receipt.FieldBoundingBoxes {
+			if box.Valid() {
+				boxes[fieldPath(path)] = box
+			}
+		}
+		receipt.FieldBoundingBoxes = boxes
+		if len(boxes) == 0 {
+			receipt.FieldBoundingBoxes = nil
+		}
+	}
+
+	for i, item := range receipt.Items {
+		if item.Confidence != nil && item.Confidence.Line == 0 {
+			confidence := *item.Confidence
+			confidence.Line = lowestScore(confidence.Name, confidence.Price, confidence.Quantity, confidence.Total)
+			receipt.Items[i].Confidence = &confidence
+		}
+		if !item.BoundingBox.Valid() {
+			receipt.Items[i].BoundingBox = nil
+		}
+	}
+	return receipt
+}
+
+// LowConfidenceFields mengembalikan path field yang confidence-nya di bawah threshold, diurutkan
+// dengan field item lebih dulu. Field tanpa confidence tidak dianggap rendah.
+func LowConfidenceFields(receipt models.SplitbillResponse, threshold float64) []string {
+	fields := []string{}
+	for i, item := range receipt.Items {
+		if item.Confidence == nil {
+			continue
+		}
+		itemFields := []struct {
+			name  string
+			score models.Score
+		}{
+			{"name", item.Confidence.Name},
+			{"price", item.Confidence.Price},
+			{"quantity", item.Confidence.Quantity},
+			{"total", item.Confidence.Total},
+		}
+		lowField := false
+		for _, field := range itemFields {
+			if field.score > 0 && float64(field.score) < threshold {
+				fields = append(fields, fmt.Sprintf("items[%d].%s", i, field.name))
+				lowField = true
+			}
+		}
+		if !lowField && item.Confidence.Line > 0 && float64(item.Confidence.Line) < threshold {
+			fields = append(fields, fmt.Sprintf("items[%d]", i))
+		}
+	}
+
+	receiptFields := []string{}
+	for path, score := range receipt.FieldConfidence {
+		if score > 0 && float64(score) < threshold {
+			receiptFields = append(receiptFields, path)
+		}
+	}
+	sort.Strings(receiptFields)
+	return append(fields, receiptFields...)
+}
+
+// fieldPath merapikan path field dari model, misalnya "Totals.Total " menjadi "totals.total"
+func fieldPath(path string) string {
+	return strings.ToLower(strings.Join(strings.Fields(path), ""))
+}
+
+func lowestScore(scores ...models.Score) models.Score {
+	lowest := models.Score(0)
+	for _, score := range scores {
+		if score > 0 && (lowest == 0 || score < lowest) {
+			lowest = score
+		}
+	}
+	return lowest
+}
@@ -0,0 +1,68 @@
+package receipts
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// modelConfidence adalah contoh keluaran model dengan confidence dalam persen, string, angka tunggal
+// untuk baris item, serta bounding box dalam skala 0-1000
+const modelConfidence = `{
+	"items": [
+		{"name": "Nasi Goreng", "confidence": {"name": 0.97, "price": "55%", "quantity": 0.95, "total": 90}, "bounding_box": [410, 80, 440, 920]},
+		{"name": "Es Teh", "confidence": 0.5, "bounding_box": [500, 900, 520, 100]},
+		{"name": "Kerupuk", "confidence": {"name": "n/a"}}
+	],
+	"field_confidence": {"Totals.Total ": 0.4, "store_information.store_name": 0.99, "transaction_info.date": "?"},
+	"field_bounding_boxes": {"totals.total": [900, 600, 930, 950], "totals.tax.amount": [1, 2]}
+}`
+
+func TestNormalizeConfidence(t *testing.T) {
+	var receipt models.SplitbillResponse
+	if err := json.Unmarshal([]byte(modelConfidence), &receipt); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	receipt = NormalizeConfidence(receipt)
+
+	first := receipt.Items[0]
+	if first.Confidence.Price != 0.55 || first.Confidence.Total != 0.9 {
+		t.Errorf("item 0 confidence = %+v, want price 0.55 and total 0.9", first.Confidence)
+	}
+	// confidence baris diisi dengan field item terendah
+	if first.Confidence.Line != 0.55 {
+		t.Errorf("item 0 line confidence = %v, want 0.55", first.Confidence.Line)
+	}
+	if want := (models.BoundingBox{YMin: 0.41, XMin: 0.08, YMax: 0.44, XMax: 0.92}); first.BoundingBox == nil || *first.BoundingBox != want {
+		t.Errorf("item 0 bounding box = %+v, want %+v", first.BoundingBox, want)
+	}
+	if receipt.Items[1].Confidence.Line != 0.5 || receipt.Items[1].BoundingBox != nil {
+		t.Errorf("item 1 = %+v / %+v, want line confidence 0.5 without the inverted box", receipt.Items[1].Confidence, receipt.Items[1].BoundingBox)
+	}
+	if receipt.Items[2].Confidence.Line != 0 {
+		t.Errorf("item 2 line confidence = %v, want 0", receipt.Items[2].Confidence.Line)
+	}
+
+	wantFields := map[string]models.Score{"totals.total": 0.4, "store_information.store_name": 0.99}
+	if len(receipt.FieldConfidence) != len(wantFields) {
+		t.Errorf("field confidence = %v, want %v", receipt.FieldConfidence, wantFields)
+	}
+	for path, score := range wantFields {
+		if receipt.FieldConfidence[path] != score {
+			t.Errorf("field confidence[%s] = %v, want %v", path, receipt.FieldConfidence[path], score)
+		}
+	}
+	if len(receipt.FieldBoundingBoxes) != 1 || receipt.FieldBoundingBoxes["totals.total"] == nil {
+		t.Errorf("field bounding boxes = %v, want only totals.total", receipt.FieldBoundingBoxes)
+	}
+
+	fields := LowConfidenceFields(receipt, 0.6)
+	if want := []string{"items[0].price", "items[1]", "totals.total"}; !slices.Equal(fields, want) {
+		t.Errorf("LowConfidenceFields() = %v, want %v", fields, want)
+	}
+	if fields := LowConfidenceFields(receipt, 0.3); len(fields) != 0 {
+		t.Errorf("LowConfidenceFields(0.3) = %v, want none", fields)
+	}
+}
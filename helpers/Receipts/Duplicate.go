@@ -0,0 +1,134 @@
+package receipts
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// Alasan kecocokan yang dicatat di peringatan duplikat
+const (
+	DuplicateReasonImage         = "similar_image"
+	DuplicateReasonTransactionID = "same_transaction_id"
+	DuplicateReasonStore         = "same_store"
+	DuplicateReasonDateTime      = "same_date_time"
+	DuplicateReasonTotal         = "same_total"
+)
+
+// duplicateImageDistance adalah Hamming distance maksimum dua perceptual hash yang dianggap foto
+// dari struk yang sama
+const duplicateImageDistance = 10
+
+var reNonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// DuplicateMatch menilai apakah candidate kemungkinan besar struk yang sama dengan existing.
+// imageDistance adalah jarak perceptual hash kedua foto (-1 jika tidak tersedia). Struk dianggap
+// duplikat jika nomor transaksinya sama dan toko atau totalnya cocok, jika fotonya mirip dan
+// totalnya cocok, atau jika toko, tanggal, jam dan total semuanya cocok.
+func DuplicateMatch(candidate models.SplitbillResponse, existing models.SplitbillResponse, imageDistance int) ([]string, bool) {
+	reasons := []string{}
+	similarImage := imageDistance >= 0 && imageDistance <= duplicateImageDistance
+	sameTransactionID := sameKey(candidate.TransactionInfo.TransactionID, existing.TransactionInfo.TransactionID)
+	sameStore := sameKey(candidate.StoreInformation.StoreName, existing.StoreInformation.StoreName)
+	sameDateTime := sameKey(normalizeDate(candidate.TransactionInfo.Date), normalizeDate(existing.TransactionInfo.Date)) &&
+		sameKey(padTime(candidate.TransactionInfo.Time), padTime(existing.TransactionInfo.Time))
+	total, hasTotal := ParseAmount(candidate.Totals.Total)
+	existingTotal, hasExistingTotal := ParseAmount(existing.Totals.Total)
+	sameTotal := hasTotal && hasExistingTotal && total != 0 && near(total, existingTotal)
+
+	for _, signal := range []struct {
+		matched bool
+		reason  string
+	}{
+		{similarImage, DuplicateReasonImage},
+		{sameTransactionID, DuplicateReasonTransactionID},
+		{sameStore, DuplicateReasonStore},
+		{sameDateTime, DuplicateReasonDateTime},
+		{sameTotal, DuplicateReasonTotal},
+	} {
+		if signal.matched {
+			reasons = append(reasons, signal.reason)
+		}
+	}
+
+	duplicate := (sameTransactionID && (sameStore || sameTotal)) ||
+		(similarImage && sameTotal) ||
+		(sameStore && sameDateTime && sameTotal)
+	return reasons, duplicate
+}
+
+// DuplicateKeys mengembalikan key indeks duplikat receipt: nomor transaksi dan rentang total. Setiap
+// aturan DuplicateMatch mensyaratkan nomor transaksi atau total yang sama, sehingga dua receipt yang
+// mungkin duplikat selalu berbagi minimal satu key dan receipt tanpa keduanya tidak perlu diindeks.
+func DuplicateKeys(receipt models.SplitbillResponse) []string {
+	keys := []string{}
+	if key := transactionKey(receipt); key != "" {
+		keys = append(keys, key)
+	}
+	if total, ok := ParseAmount(receipt.Totals.Total); ok && total != 0 {
+		keys = append(keys, totalKey(total, totalBucket(math.Abs(total))))
+	}
+	return keys
+}
+
+// DuplicateCandidateKeys mengembalikan key indeks yang harus dicari untuk menemukan receipt yang
+// mungkin duplikat dari receipt ini: nomor transaksinya dan setiap rentang total yang bersinggungan
+// dengan toleransi total DuplicateMatch.
+func DuplicateCandidateKeys(receipt models.SplitbillResponse) []string {
+	keys := []string{}
+	if key := transactionKey(receipt); key != "" {
+		keys = append(keys, key)
+	}
+	total, ok := ParseAmount(receipt.Totals.Total)
+	if !ok || total == 0 {
+		return keys
+	}
+	// Toleransi near dihitung dari total terbesar, jadi batasnya sedikit diperlebar
+	amount := math.Abs(total)
+	tolerance := math.Max(1, amount*0.0011)
+	for start := totalBucket(math.Max(0, amount-tolerance)); start <= amount+tolerance; start += bucketWidth(start) {
+		keys = append(keys, totalKey(total, start))
+	}
+	return keys
+}
+
+func transactionKey(receipt models.SplitbillResponse) string {
+	key := reNonAlphanumeric.ReplaceAllString(strings.ToUpper(receipt.TransactionInfo.TransactionID), "")
+	if key == "" {
+		return ""
+	}
+	return "transaction:" + key
+}
+
+// totalKey membentuk key rentang total; total negatif (refund) diindeks terpisah
+func totalKey(total float64, start float64) string {
+	if total < 0 {
+		return "total:-" + strconv.FormatFloat(start, 'f', -1, 64)
+	}
+	return "total:" + strconv.FormatFloat(start, 'f', -1, 64)
+}
+
+// totalBucket mengembalikan awal rentang yang memuat amount (tidak negatif). Lebar rentang 1% dari
+// orde besarnya (paling kecil 1), sehingga rentang di setiap orde besar saling bersambung.
+func totalBucket(amount float64) float64 {
+	width := bucketWidth(amount)
+	return math.Floor(amount/width) * width
+}
+
+func bucketWidth(amount float64) float64 {
+	if amount < 1000 {
+		return 1
+	}
+	return math.Pow(10, math.Floor(math.Log10(amount))-2)
+}
+
+// sameKey membandingkan dua nilai tanpa memperhatikan huruf besar, spasi dan tanda baca.
+// Nilai kosong tidak pernah dianggap sama.
+func sameKey(a string, b string) bool {
+	a = reNonAlphanumeric.ReplaceAllString(strings.ToUpper(a), "")
+	b = reNonAlphanumeric.ReplaceAllString(strings.ToUpper(b), "")
+	return a != "" && a == b
+}
@@ -0,0 +1,129 @@
+package receipts
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func duplicateReceipt(store string, date string, clock string, transactionID string, total string) models.SplitbillResponse {
+	var receipt models.SplitbillResponse
+	receipt.StoreInformation.StoreName = store
+	receipt.TransactionInfo.Date = date
+	receipt.TransactionInfo.Time = clock
+	receipt.TransactionInfo.TransactionID = transactionID
+	receipt.Totals.Total = total
+	return receipt
+}
+
+func TestDuplicateMatch(t *testing.T) {
+	existing := duplicateReceipt("Toko Maju Jaya", "02/08/2025", "19:30", "TRX-0001", "61050.00")
+	tests := []struct {
+		name          string
+		candidate     models.SplitbillResponse
+		imageDistance int
+		reasons       []string
+		duplicate     bool
+	}{
+		{
+			name:          "same receipt",
+			candidate:     duplicateReceipt("TOKO MAJU JAYA", "2/8/25", "19:30", "trx 0001", "61050"),
+			imageDistance: 0,
+			reasons:       []string{DuplicateReasonImage, DuplicateReasonTransactionID, DuplicateReasonStore, DuplicateReasonDateTime, DuplicateReasonTotal},
+			duplicate:     true,
+		},
+		{
+			name:          "transaction id and store",
+			candidate:     duplicateReceipt("Toko Maju Jaya", "", "", "TRX-0001", "99000"),
+			imageDistance: -1,
+			reasons:       []string{DuplicateReasonTransactionID, DuplicateReasonStore},
+			duplicate:     true,
+		},
+		{
+			name:          "transaction id only",
+			candidate:     duplicateReceipt("Toko Lain", "", "", "TRX-0001", "99000"),
+			imageDistance: -1,
+			reasons:       []string{DuplicateReasonTransactionID},
+		},
+		{
+			name:          "similar image and total within rounding",
+			candidate:     duplicateReceipt("", "", "", "", "61100"),
+			imageDistance: 10,
+			reasons:       []string{DuplicateReasonImage, DuplicateReasonTotal},
+			duplicate:     true,
+		},
+		{
+			name:          "image too different",
+			candidate:     duplicateReceipt("", "", "", "", "61050"),
+			imageDistance: 11,
+			reasons:       []string{DuplicateReasonTotal},
+		},
+		{
+			name:          "store date time and total",
+			candidate:     duplicateReceipt("Toko Maju Jaya", "02-08-2025", "19:30", "", "61050"),
+			imageDistance: -1,
+			reasons:       []string{DuplicateReasonStore, DuplicateReasonDateTime, DuplicateReasonTotal},
+			duplicate:     true,
+		},
+		{
+			name:          "same store and total on another day",
+			candidate:     duplicateReceipt("Toko Maju Jaya", "03/08/2025", "19:30", "", "61050"),
+			imageDistance: -1,
+			reasons:       []string{DuplicateReasonStore, DuplicateReasonTotal},
+		},
+		{
+			name:          "zero total never matches",
+			candidate:     duplicateReceipt("", "", "", "", "0"),
+			imageDistance: 0,
+			reasons:       []string{DuplicateReasonImage},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reasons, duplicate := DuplicateMatch(test.candidate, existing, test.imageDistance)
+			if duplicate != test.duplicate {
+				t.Errorf("duplicate = %v, want %v", duplicate, test.duplicate)
+			}
+			if !slices.Equal(reasons, test.reasons) {
+				t.Errorf("reasons = %v, want %v", reasons, test.reasons)
+			}
+		})
+	}
+}
+
+// TestDuplicateCandidateKeys memastikan setiap pasangan total yang dianggap sama oleh DuplicateMatch
+// berbagi minimal satu key indeks, termasuk di batas rentang dan pergantian orde besar
+func TestDuplicateCandidateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    float64
+		existing float64
+	}{
+		{name: "same total", total: 61050, existing: 61050},
+		{name: "one rupiah below small total", total: 500, existing: 499},
+		{name: "across bucket boundary", total: 61099, existing: 61101},
+		{name: "across order of magnitude", total: 99990, existing: 100050},
+		{name: "upper tolerance", total: 1000000, existing: 1001000},
+		{name: "refund", total: -25000, existing: -25020},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidate := duplicateReceipt("", "", "", "", strconv.FormatFloat(test.total, 'f', 2, 64))
+			existing := duplicateReceipt("", "", "", "", strconv.FormatFloat(test.existing, 'f', 2, 64))
+			if _, duplicate := DuplicateMatch(candidate, existing, 0); !duplicate {
+				t.Fatalf("DuplicateMatch() of %v and %v is false", test.total, test.existing)
+			}
+			candidateKeys := DuplicateCandidateKeys(candidate)
+			for _, key := range DuplicateKeys(existing) {
+				if slices.Contains(candidateKeys, key) {
+					return
+				}
+			}
+			t.Errorf("candidate keys %v do not include any of %v", candidateKeys, DuplicateKeys(existing))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package receipts
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+var batchCSVHeader = []string{
+	"file_index", "filename", "status", "error_code", "error", "receipt_id", "receipt_type",
+	"store_name", "date", "time", "transaction_id", "item_index", "item_name", "quantity", "price",
+	"item_total", "subtotal", "tax", "discount", "total",
+}
+
+// WriteBatchCSV menulis hasil batch sebagai CSV dengan satu baris per item. Kolom struk diulang di
+// setiap baris item; struk tanpa item dan file yang gagal tetap mendapat satu baris. Foto yang
+// berisi beberapa struk menghasilkan baris untuk setiap struknya.
+func WriteBatchCSV(w io.Writer, batch models.Batch) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(batchCSVHeader); err != nil {
+		return err
+	}
+	for _, file := range batch.Files {
+		fileColumns := []string{strconv.Itoa(file.Index), csvText(file.Filename), file.Status}
+		if file.Error != nil {
+			fileColumns = append(fileColumns, file.Error.Code, csvText(file.Error.Message))
+		} else {
+			fileColumns = append(fileColumns, "", "")
+		}
+		if file.Result == nil {
+			if err := writer.Write(append(fileColumns, make([]string, len(batchCSVHeader)-len(fileColumns))...)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		found := file.Result.Receipts
+		if len(found) == 0 {
+			found = []models.SplitbillResponse{*file.Result}
+		}
+		for _, receipt := range found {
+			receiptColumns := []string{
+				receipt.ReceiptID, receipt.ReceiptType, csvText(receipt.StoreInformation.StoreName),
+				receipt.TransactionInfo.Date, receipt.TransactionInfo.Time, csvText(receipt.TransactionInfo.TransactionID),
+			}
+			totalColumns := []string{receipt.Totals.Subtotal, receipt.Totals.Tax.Amount, receipt.Totals.Discount, receipt.Totals.Total}
+			items := receipt.Items
+			if len(items) == 0 {
+				items = []models.Item{{}}
+			}
+			for i, item := range items {
+				itemIndex := strconv.Itoa(i + 1)
+				if len(receipt.Items) == 0 {
+					itemIndex = ""
+				}
+				row := append(append([]string{}, fileColumns...), receiptColumns...)
+				row = append(row, itemIndex, csvText(item.Name), item.Quantity, item.Price, item.Total)
+				if err := writer.Write(append(row, totalColumns...)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText mencegah teks dari struk dibaca sebagai formula saat CSV dibuka di spreadsheet
+func csvText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
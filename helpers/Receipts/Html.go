@@ -0,0 +1,76 @@
+package receipts
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var reHTMLTag = regexp.MustCompile(`(?i)<\s*(html|body|table|div|p|br|td|span)\b`)
+
+// blockElements menandai elemen HTML yang diakhiri baris baru saat diubah menjadi teks
+var blockElements = map[string]bool{
+	"br": true, "p": true, "div": true, "tr": true, "li": true, "table": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "article": true, "header": true, "footer": true, "hr": true,
+}
+
+// LooksLikeHTML menebak apakah teks yang dikirim sebenarnya HTML (misalnya badan email e-receipt).
+func LooksLikeHTML(text string) bool {
+	return reHTMLTag.MatchString(text)
+}
+
+// HTMLToText mengubah HTML e-receipt menjadi teks baris-per-baris. Setiap baris tabel menjadi
+// satu baris teks dengan sel dipisahkan spasi, sehingga formatnya mirip struk biasa.
+func HTMLToText(source string) (string, error) {
+	document, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			switch node.Data {
+			case "script", "style", "head", "title", "noscript":
+				return
+			}
+		}
+		if node.Type == html.TextNode {
+			// Spasi di tepi teks dipertahankan agar "Total <b>35.000</b>" tidak menjadi "Total35.000"
+			text := strings.Join(strings.Fields(node.Data), " ")
+			if text != "" && strings.TrimLeft(node.Data, " \t\r\n") != node.Data {
+				text = " " + text
+			}
+			if text != "" && strings.TrimRight(node.Data, " \t\r\n") != node.Data {
+				text += " "
+			}
+			builder.WriteString(text)
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if node.Type == html.ElementNode {
+			switch {
+			case blockElements[node.Data]:
+				builder.WriteString("\n")
+			case node.Data == "td" || node.Data == "th":
+				builder.WriteString("  ")
+			default:
+				builder.WriteString(" ")
+			}
+		}
+	}
+	walk(document)
+
+	lines := []string{}
+	for _, line := range strings.Split(builder.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,41 @@
+package receipts
+
+import "testing"
+
+func TestLooksLikeHTML(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "<html><body>Total 25.000</body></html>", want: true},
+		{text: "<TABLE><tr><td>Teh</td></tr></TABLE>", want: true},
+		{text: "Teh Manis 2 x 5.000 <promo>", want: false},
+		{text: "TOTAL 25.000\nTUNAI 30.000", want: false},
+	}
+
+	for _, test := range tests {
+		if got := LooksLikeHTML(test.text); got != test.want {
+			t.Errorf("LooksLikeHTML(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	source := `<html><head><title>Struk</title><style>td { color: red }</style></head><body>
+<h1>Toko Maju</h1>
+<table>
+  <tr><td>Teh   Manis</td><td>2</td><td>10.000</td></tr>
+  <tr><td>Nasi Goreng</td><td>1</td><td>25.000</td></tr>
+</table>
+<p>Total <b>35.000</b></p><script>alert("x")</script>
+</body></html>`
+	want := "Toko Maju\nTeh Manis 2 10.000\nNasi Goreng 1 25.000\nTotal 35.000"
+
+	got, err := HTMLToText(source)
+	if err != nil {
+		t.Fatalf("HTMLToText() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("HTMLToText() = %q, want %q", got, want)
+	}
+}
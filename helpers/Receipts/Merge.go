@@ -0,0 +1,122 @@
+package receipts
+
+import (
+	"strings"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// MergeSections menggabungkan hasil ekstraksi beberapa foto berurutan dari satu struk panjang.
+// Item yang muncul di bagian yang tumpang tindih (akhir foto sebelumnya sama dengan awal foto
+// berikutnya) hanya dihitung sekali. Totals diambil dari bagian terakhir yang memiliki total,
+// informasi toko, jenis struk dan extensions dari bagian pertama yang memilikinya. Confidence dan
+// bounding box field ikut diambil dari bagian asal nilainya; bounding box diberi nomor bagian.
+func MergeSections(sections []models.SplitbillResponse) models.SplitbillResponse {
+	merged := models.SplitbillResponse{Items: []models.Item{}}
+	storeSection := -1
+
+	for i, section := range sections {
+		sectionNumber := 0
+		if len(sections) > 1 {
+			sectionNumber = i + 1
+		}
+		overlap := overlapLength(merged.Items, section.Items)
+		for _, item := range section.Items[overlap:] {
+			if item.BoundingBox != nil {
+				box := *item.BoundingBox
+				box.Section = sectionNumber
+				item.BoundingBox = &box
+			}
+			merged.Items = append(merged.Items, item)
+		}
+
+		if merged.StoreInformation.StoreName == "" && section.StoreInformation.StoreName != "" {
+			merged.StoreInformation = section.StoreInformation
+			storeSection = i
+		}
+		if (merged.ReceiptType == "" || merged.ReceiptType == models.ReceiptTypeOther) && section.ReceiptType != "" {
+			merged.ReceiptType = section.ReceiptType
+		}
+		if merged.Extensions == nil && section.Extensions != nil {
+			merged.Extensions = section.Extensions
+		}
+		if merged.TransactionInfo.Date == "" {
+			merged.TransactionInfo.Date = section.TransactionInfo.Date
+		}
+		if merged.TransactionInfo.Time == "" {
+			merged.TransactionInfo.Time = section.TransactionInfo.Time
+		}
+		if merged.TransactionInfo.TransactionID == "" {
+			merged.TransactionInfo.TransactionID = section.TransactionInfo.TransactionID
+		}
+	}
+
+	totalsSection := -1
+	for i := len(sections) - 1; i >= 0; i-- {
+		if AmountOrZero(sections[i].Totals.Total) != 0 {
+			merged.Totals = sections[i].Totals
+			totalsSection = i
+			break
+		}
+	}
+
+	for i, section := range sections {
+		for path, score := range section.FieldConfidence {
+			if _, exists := merged.FieldConfidence[path]; !exists && fieldSection(path, i, storeSection, totalsSection) {
+				if merged.FieldConfidence == nil {
+					merged.FieldConfidence = map[string]models.Score{}
+				}
+				merged.FieldConfidence[path] = score
+			}
+		}
+		for path, box := range section.FieldBoundingBoxes {
+			if _, exists := merged.FieldBoundingBoxes[path]; !exists && box != nil && fieldSection(path, i, storeSection, totalsSection) {
+				if merged.FieldBoundingBoxes == nil {
+					merged.FieldBoundingBoxes = map[string]*models.BoundingBox{}
+				}
+				sectionBox := *box
+				if len(sections) > 1 {
+					sectionBox.Section = i + 1
+				}
+				merged.FieldBoundingBoxes[path] = &sectionBox
+			}
+		}
+	}
+	return merged
+}
+
+// fieldSection menentukan apakah confidence field path dari bagian ke-section dipakai. Informasi
+// toko dan totals mengikuti bagian asal nilainya, field lain diambil dari bagian pertama yang
+// memilikinya.
+func fieldSection(path string, section int, storeSection int, totalsSection int) bool {
+	switch {
+	case strings.HasPrefix(path, "store_information."):
+		return section == storeSection
+	case strings.HasPrefix(path, "totals."):
+		return section == totalsSection
+	default:
+		return true
+	}
+}
+
+// overlapLength mencari jumlah item terpanjang di akhir previous yang sama persis dengan awal next
+func overlapLength(previous, next []models.Item) int {
+	for n := min(len(previous), len(next)); n > 0; n-- {
+		matched := true
+		for i := 0; i < n; i++ {
+			if itemKey(previous[len(previous)-n+i]) != itemKey(next[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return n
+		}
+	}
+	return 0
+}
+
+func itemKey(item models.Item) string {
+	name := strings.ToUpper(strings.Join(strings.Fields(item.Name), " "))
+	return name + "|" + FormatAmount(AmountOrZero(item.Total))
+}
@@ -0,0 +1,132 @@
+package receipts
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func mergeSection(store string, total string, items ...string) models.SplitbillResponse {
+	section := models.SplitbillResponse{Items: []models.Item{}}
+	section.StoreInformation.StoreName = store
+	section.Totals.Total = total
+	for i := 0; i+1 < len(items); i += 2 {
+		section.Items = append(section.Items, models.Item{Name: items[i], Quantity: "1", Price: items[i+1], Total: items[i+1]})
+	}
+	return section
+}
+
+func itemNames(items []models.Item) []string {
+	names := []string{}
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestMergeSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []models.SplitbillResponse
+		items    []string
+		store    string
+		total    string
+	}{
+		{
+			name: "single section",
+			sections: []models.SplitbillResponse{
+				mergeSection("Toko Maju", "30000", "Teh", "5000", "Nasi", "25000"),
+			},
+			items: []string{"Teh", "Nasi"},
+			store: "Toko Maju",
+			total: "30000",
+		},
+		{
+			name: "overlapping items counted once",
+			sections: []models.SplitbillResponse{
+				mergeSection("Toko Maju", "", "Teh", "5000", "Nasi", "25000", "Ayam", "20000"),
+				mergeSection("", "65000", "nasi ", "25000.00", "AYAM", "20000", "Kopi", "15000"),
+			},
+			items: []string{"Teh", "Nasi", "Ayam", "Kopi"},
+			store: "Toko Maju",
+			total: "65000",
+		},
+		{
+			name: "same name with another price is not an overlap",
+			sections: []models.SplitbillResponse{
+				mergeSection("Toko Maju", "", "Teh", "5000"),
+				mergeSection("", "11000", "Teh", "6000"),
+			},
+			items: []string{"Teh", "Teh"},
+			store: "Toko Maju",
+			total: "11000",
+		},
+		{
+			name: "repeated item after the overlap is kept",
+			sections: []models.SplitbillResponse{
+				mergeSection("", "", "Teh", "5000", "Nasi", "25000"),
+				mergeSection("Toko Maju", "", "Nasi", "25000", "Teh", "5000"),
+				mergeSection("", "", "Kopi", "15000"),
+			},
+			items: []string{"Teh", "Nasi", "Teh", "Kopi"},
+			store: "Toko Maju",
+		},
+		{
+			name: "totals from the last section with a total",
+			sections: []models.SplitbillResponse{
+				mergeSection("Toko Maju", "10000", "Teh", "5000"),
+				mergeSection("", "35000", "Nasi", "25000"),
+				mergeSection("", "0", "Kopi", "5000"),
+			},
+			items: []string{"Teh", "Nasi", "Kopi"},
+			store: "Toko Maju",
+			total: "35000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := MergeSections(test.sections)
+			if names := itemNames(merged.Items); !slices.Equal(names, test.items) {
+				t.Errorf("items = %v, want %v", names, test.items)
+			}
+			if merged.StoreInformation.StoreName != test.store {
+				t.Errorf("store = %q, want %q", merged.StoreInformation.StoreName, test.store)
+			}
+			if merged.Totals.Total != test.total {
+				t.Errorf("total = %q, want %q", merged.Totals.Total, test.total)
+			}
+		})
+	}
+}
+
+func TestMergeSectionsProvenance(t *testing.T) {
+	first := mergeSection("Toko Maju", "", "Teh", "5000")
+	first.Items[0].BoundingBox = &models.BoundingBox{YMin: 0.9, YMax: 0.95}
+	first.FieldConfidence = map[string]models.Score{"store_information.store_name": 0.9, "totals.total": 0.2}
+	second := mergeSection("", "30000", "Teh", "5000", "Nasi", "25000")
+	second.Items[1].BoundingBox = &models.BoundingBox{YMin: 0.1, YMax: 0.15}
+	second.FieldConfidence = map[string]models.Score{"store_information.store_name": 0.1, "totals.total": 0.8}
+	second.FieldBoundingBoxes = map[string]*models.BoundingBox{"totals.total": {YMin: 0.8, YMax: 0.85}}
+
+	merged := MergeSections([]models.SplitbillResponse{first, second})
+	if box := merged.Items[0].BoundingBox; box == nil || box.Section != 1 {
+		t.Errorf("first item bounding box = %+v, want section 1", box)
+	}
+	if box := merged.Items[1].BoundingBox; box == nil || box.Section != 2 {
+		t.Errorf("second item bounding box = %+v, want section 2", box)
+	}
+	if got := merged.FieldConfidence["store_information.store_name"]; got != 0.9 {
+		t.Errorf("store name confidence = %v, want 0.9 from the section with the store name", got)
+	}
+	if got := merged.FieldConfidence["totals.total"]; got != 0.8 {
+		t.Errorf("total confidence = %v, want 0.8 from the section with the total", got)
+	}
+	if box := merged.FieldBoundingBoxes["totals.total"]; box == nil || box.Section != 2 {
+		t.Errorf("total bounding box = %+v, want section 2", box)
+	}
+	if first.Items[0].BoundingBox.Section != 0 {
+		t.Error("MergeSections changed the bounding box of its input")
+	}
+}
@@ -0,0 +1,43 @@
+package receipts
+
+import "github.com/arifin2018/splitbill-arifin.git/models"
+
+// ReviewReasons mengembalikan alasan receipt harus direview manusia sebelum masuk tahap split:
+// validasi aritmetika gagal atau ada field dengan confidence rendah.
+func ReviewReasons(receipt models.SplitbillResponse) []string {
+	reasons := []string{}
+	if receipt.Validation != nil && !receipt.Validation.Valid {
+		reasons = append(reasons, models.ReviewReasonValidationFailed)
+	}
+	if len(receipt.LowConfidenceFields) > 0 {
+		reasons = append(reasons, models.ReviewReasonLowConfidence)
+	}
+	return reasons
+}
+
+// ReviewCleared bernilai true jika receipt boleh dikunci: tidak perlu direview atau sudah approved
+func ReviewCleared(review *models.ReceiptReview) bool {
+	return review == nil || review.Status == models.ReviewStatusNotRequired || review.Status == models.ReviewStatusApproved
+}
+
+// ApplyCorrection mengganti isi struk dengan koreksi reviewer lalu memvalidasi ulang. Metadata
+// receipt (ID, sumber, status penyimpanan, review) tidak berubah.
+func ApplyCorrection(receipt models.SplitbillResponse, correction models.SplitbillResponse) models.SplitbillResponse {
+	receipt.Items = correction.Items
+	if receipt.Items == nil {
+		receipt.Items = []models.Item{}
+	}
+	receipt.StoreInformation = correction.StoreInformation
+	receipt.Totals = correction.Totals
+	receipt.TransactionInfo = correction.TransactionInfo
+	if correction.ReceiptType != "" {
+		receipt.ReceiptType = correction.ReceiptType
+	}
+	receipt.Extensions = KeepTypeExtension(receipt.ReceiptType, correction.Extensions)
+	// Nilai yang sudah diperiksa reviewer tidak lagi dianggap meragukan
+	receipt.LowConfidenceFields = nil
+
+	validation := Validate(receipt)
+	receipt.Validation = &validation
+	return receipt
+}
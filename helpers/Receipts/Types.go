@@ -0,0 +1,248 @@
+package receipts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// typeKeywords berisi kata kunci yang menandai setiap jenis struk. Jenis dengan kecocokan
+// terbanyak dipilih; urutan menentukan pemenang jika jumlahnya sama.
+var typeKeywords = []struct {
+	receiptType string
+	pattern     *regexp.Regexp
+}{
+	{models.ReceiptTypeFuel, regexp.MustCompile(`\b(SPBU|PERTAMINA|PERTALITE|PERTAMAX|DEXLITE|SOLAR|BBM|POMPA|PUMP|LITER|LTR|V-POWER|REVVO)\b`)},
+	{models.ReceiptTypeParking, regexp.MustCompile(`\b(PARKIR|PARKING|MASUK|KELUAR|DURASI|ENTRY|EXIT|NOPOL|PLAT)\b`)},
+	{models.ReceiptTypeHotel, regexp.MustCompile(`\b(HOTEL|FOLIO|KAMAR|ROOM|CHECK[\s\-]?IN|CHECK[\s\-]?OUT|ARRIVAL|DEPARTURE|NIGHTS?|MALAM|RESORT)\b`)},
+	{models.ReceiptTypeRestaurant, regexp.MustCompile(`\b(MEJA|TABLE|PAX|WAITER|PELAYAN|DINE\s*IN|TAKE\s*AWAY|RESTO|RESTORAN|RESTAURANT|RUMAH\s+MAKAN|CAFE|KAFE|PB1|SERVICE)\b`)},
+	{models.ReceiptTypeSupermarket, regexp.MustCompile(`\b(INDOMARET|ALFAMART|ALFAMIDI|SUPERMARKET|MINIMARKET|HYPERMART|HYPERMARKET|TRANSMART|SUPERINDO|LOTTE\s*MART|GIANT|MEMBER|KG|PCS|HEMAT)\b`)},
+}
+
+var (
+	reTableNumber     = regexp.MustCompile(`\b(?:NO\.?\s*MEJA|MEJA|TABLE|TBL)\s*[:#.]?\s*([A-Z]?\d{1,3}[A-Z]?)\b`)
+	rePax             = regexp.MustCompile(`\b(?:PAX|TAMU|GUESTS?|COVERS?)\s*[:#.]?\s*(\d{1,3})\b`)
+	rePaxCount        = regexp.MustCompile(`\b(\d{1,3})\s*(?:PAX|ORANG)\b`)
+	reWaiter          = regexp.MustCompile(`\b(?:WAITER|PELAYAN|SERVER)\s*[:.]?\s*([A-Z][A-Z .]{1,30})$`)
+	reMemberID        = regexp.MustCompile(`\b(?:NO\.?\s*MEMBER|MEMBER\s*ID|KARTU\s+MEMBER|MEMBER)\s*[:#.]?\s*(\d{6,})`)
+	reItemUnit        = regexp.MustCompile(`^(.+?)\s+(\d+(?:[.,]\d+)?)\s*(KG|GR|G|LTR|L|ML|PCS|PC|PAK|PACK|BOX|BTL|DUS|SCH|BKS)\s*[X@]`)
+	reFuelType        = regexp.MustCompile(`\b(PERTALITE|PERTAMAX\s+TURBO|PERTAMAX\s+GREEN|PERTAMAX|PERTAMINA\s+DEX|DEXLITE|BIO\s*SOLAR|SOLAR|PREMIUM|SHELL\s+SUPER|SHELL\s+V-POWER|V-POWER|REVVO\s*\d+|BP\s*\d{2})\b`)
+	reLiters          = regexp.MustCompile(`\b(?:VOLUME|VOL|JUMLAH\s+LITER|LITER|LITRE)\s*(?:\(L\))?\s*[:.]?\s*(\d+(?:[.,]\d+)?)`)
+	reLitersCount     = regexp.MustCompile(`\b(\d+(?:[.,]\d+)?)\s*(?:L|LTR|LITER)\b`)
+	rePricePerLiter   = regexp.MustCompile(`\b(?:HARGA\s*/\s*(?:LITER|LTR|L)|HARGA\s+PER\s+LITER|PRICE\s*/\s*L)\s*[:.]?\s*(?:RP\.?\s*)?(\d[\d.,]*)`)
+	rePumpNumber      = regexp.MustCompile(`\b(?:NO\.?\s*POMPA|PULAU\s*/\s*POMPA|POMPA|PUMP)\s*[:#.]?\s*(\d{1,2})\b`)
+	reVehiclePlate    = regexp.MustCompile(`\b(?:NO\.?\s*(?:POL|PLAT|KENDARAAN)|NOPOL|PLAT|PLATE)\s*[:.]?\s*([A-Z]{1,2}\s*\d{1,4}\s*[A-Z]{0,3})\b`)
+	reEntryTime       = regexp.MustCompile(`\b(?:JAM\s+MASUK|WAKTU\s+MASUK|TGL\s+MASUK|MASUK|ENTRY|TIME\s+IN)\b\s*[:.]?\s*(?:[\d/\-.]+\s+)?(\d{1,2}:\d{2})`)
+	reExitTime        = regexp.MustCompile(`\b(?:JAM\s+KELUAR|WAKTU\s+KELUAR|TGL\s+KELUAR|KELUAR|EXIT|TIME\s+OUT)\b\s*[:.]?\s*(?:[\d/\-.]+\s+)?(\d{1,2}:\d{2})`)
+	reDuration        = regexp.MustCompile(`\b(?:LAMA\s+PARKIR|DURASI|DURATION|LAMA)\s*[:.]?\s*(\d.*)$`)
+	reVehicleType     = regexp.MustCompile(`\b(MOBIL|MOTOR|TRUK|BUS|CAR|MOTORCYCLE)\b`)
+	reGuestName       = regexp.MustCompile(`\b(?:GUEST\s+NAME|NAMA\s+TAMU|GUEST)\s*[:.]\s*([A-Z][A-Z .,']{1,40})$`)
+	reRoomNumber      = regexp.MustCompile(`\b(?:ROOM\s+NO|NO\.?\s*KAMAR|ROOM|KAMAR)\s*[:#.]?\s*(\d{2,5}[A-Z]?)\b`)
+	reCheckIn         = regexp.MustCompile(`\b(?:CHECK[\s\-]?IN|ARRIVAL|KEDATANGAN|C/I)\b\s*[:.]?\s*(\d{1,2}[/\-.]\d{1,2}[/\-.]\d{2,4})`)
+	reCheckOut        = regexp.MustCompile(`\b(?:CHECK[\s\-]?OUT|DEPARTURE|KEBERANGKATAN|C/O)\b\s*[:.]?\s*(\d{1,2}[/\-.]\d{1,2}[/\-.]\d{2,4})`)
+	reRoomNights      = regexp.MustCompile(`\b(?:ROOM\s+NIGHTS?|NIGHTS?|MALAM)\s*[:.]?\s*(\d{1,3})\b`)
+	reRoomNightsCount = regexp.MustCompile(`\b(\d{1,3})\s*(?:NIGHTS?|MALAM)\b`)
+)
+
+// typeDetailPatterns adalah baris keterangan khusus jenis struk (meja, pompa, jam masuk, kamar)
+// yang bukan baris item.
+var typeDetailPatterns = []*regexp.Regexp{
+	reTableNumber, rePax, rePaxCount, reWaiter, reMemberID, rePricePerLiter, rePumpNumber,
+	reVehiclePlate, reEntryTime, reExitTime, reDuration, reGuestName, reRoomNumber,
+	reCheckIn, reCheckOut,
+}
+
+// DetectReceiptType menebak jenis struk dari teksnya berdasarkan kata kunci. Jika tidak ada kata
+// kunci yang cocok, hasilnya "other".
+func DetectReceiptType(text string) string {
+	upper := strings.ToUpper(text)
+	best, bestScore := models.ReceiptTypeOther, 0
+	for _, keyword := range typeKeywords {
+		if score := len(keyword.pattern.FindAllString(upper, -1)); score > bestScore {
+			best, bestScore = keyword.receiptType, score
+		}
+	}
+	return best
+}
+
+// IsTypeDetailLine menandai baris keterangan khusus jenis struk agar tidak terbaca sebagai item
+func IsTypeDetailLine(upper string) bool {
+	for _, pattern := range typeDetailPatterns {
+		if pattern.MatchString(upper) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractTypeDetails mengisi blok extensions untuk jenis struk yang diberikan dari teks struk.
+// Hasilnya nil untuk jenis "other" atau jika tidak ada field yang ditemukan.
+func ExtractTypeDetails(receiptType string, text string) *models.ReceiptExtensions {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		if line = strings.ToUpper(strings.Join(strings.Fields(line), " ")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	extensions := &models.ReceiptExtensions{}
+	switch receiptType {
+	case models.ReceiptTypeRestaurant:
+		restaurant := models.RestaurantExtension{
+			TableNumber: firstMatch(lines, reTableNumber),
+			Pax:         firstMatch(lines, rePax, rePaxCount),
+			Waiter:      firstMatch(lines, reWaiter),
+		}
+		if restaurant != (models.RestaurantExtension{}) {
+			extensions.Restaurant = &restaurant
+		}
+	case models.ReceiptTypeSupermarket:
+		supermarket := models.SupermarketExtension{MemberID: firstMatch(lines, reMemberID), ItemUnits: []models.ItemUnit{}}
+		for _, line := range lines {
+			if m := reItemUnit.FindStringSubmatch(line); m != nil {
+				supermarket.ItemUnits = append(supermarket.ItemUnits, models.ItemUnit{
+					Name:     strings.TrimSpace(m[1]),
+					Quantity: strings.ReplaceAll(m[2], ",", "."),
+					Unit:     strings.ToLower(m[3]),
+				})
+			}
+		}
+		if supermarket.MemberID != "" || len(supermarket.ItemUnits) > 0 {
+			extensions.Supermarket = &supermarket
+		}
+	case models.ReceiptTypeFuel:
+		fuel := models.FuelExtension{
+			FuelType:     firstMatch(lines, reFuelType),
+			Liters:       formatDecimal(firstMatch(lines, reLiters, reLitersCount)),
+			PumpNumber:   firstMatch(lines, rePumpNumber),
+			VehiclePlate: firstMatch(lines, reVehiclePlate),
+		}
+		if price, ok := ParseAmount(firstMatch(lines, rePricePerLiter)); ok {
+			fuel.PricePerLiter = FormatAmount(price)
+		}
+		if fuel != (models.FuelExtension{}) {
+			extensions.Fuel = &fuel
+		}
+	case models.ReceiptTypeParking:
+		parking := models.ParkingExtension{
+			EntryTime:    padTime(firstMatch(lines, reEntryTime)),
+			ExitTime:     padTime(firstMatch(lines, reExitTime)),
+			Duration:     strings.ToLower(firstMatch(lines, reDuration)),
+			VehiclePlate: firstMatch(lines, reVehiclePlate),
+			VehicleType:  firstMatch(lines, reVehicleType),
+		}
+		if parking != (models.ParkingExtension{}) {
+			extensions.Parking = &parking
+		}
+	case models.ReceiptTypeHotel:
+		hotel := models.HotelExtension{
+			GuestName:  firstMatch(lines, reGuestName),
+			RoomNumber: firstMatch(lines, reRoomNumber),
+			CheckIn:    normalizeDate(firstMatch(lines, reCheckIn)),
+			CheckOut:   normalizeDate(firstMatch(lines, reCheckOut)),
+			RoomNights: firstMatch(lines, reRoomNights, reRoomNightsCount),
+		}
+		if hotel.RoomNights == "" {
+			checkIn, errIn := time.Parse("02/01/2006", hotel.CheckIn)
+			checkOut, errOut := time.Parse("02/01/2006", hotel.CheckOut)
+			if errIn == nil && errOut == nil && checkOut.After(checkIn) {
+				hotel.RoomNights = strconv.Itoa(int(checkOut.Sub(checkIn).Hours() / 24))
+			}
+		}
+		if hotel != (models.HotelExtension{}) {
+			extensions.Hotel = &hotel
+		}
+	default:
+		return nil
+	}
+	return KeepTypeExtension(receiptType, extensions)
+}
+
+// KeepTypeExtension hanya menyisakan blok extensions yang sesuai dengan jenis struk. Model AI
+// kadang mengisi semua blok dengan string kosong, sehingga blok kosong juga dibuang.
+func KeepTypeExtension(receiptType string, extensions *models.ReceiptExtensions) *models.ReceiptExtensions {
+	if extensions == nil {
+		return nil
+	}
+	kept := &models.ReceiptExtensions{}
+	switch receiptType {
+	case models.ReceiptTypeRestaurant:
+		if extensions.Restaurant != nil && *extensions.Restaurant != (models.RestaurantExtension{}) {
+			kept.Restaurant = extensions.Restaurant
+		}
+	case models.ReceiptTypeSupermarket:
+		if extensions.Supermarket != nil && (extensions.Supermarket.MemberID != "" || len(extensions.Supermarket.ItemUnits) > 0) {
+			kept.Supermarket = extensions.Supermarket
+		}
+	case models.ReceiptTypeFuel:
+		if extensions.Fuel != nil && *extensions.Fuel != (models.FuelExtension{}) {
+			kept.Fuel = extensions.Fuel
+		}
+	case models.ReceiptTypeParking:
+		if extensions.Parking != nil && *extensions.Parking != (models.ParkingExtension{}) {
+			kept.Parking = extensions.Parking
+		}
+	case models.ReceiptTypeHotel:
+		if extensions.Hotel != nil && *extensions.Hotel != (models.HotelExtension{}) {
+			kept.Hotel = extensions.Hotel
+		}
+	}
+	if *kept == (models.ReceiptExtensions{}) {
+		return nil
+	}
+	return kept
+}
+
+// firstMatch mengembalikan grup pertama dari baris pertama yang cocok. Pola dicoba berurutan,
+// pola berikutnya hanya dipakai jika pola sebelumnya tidak cocok di baris mana pun.
+func firstMatch(lines []string, patterns ...*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		for _, line := range lines {
+			if m := pattern.FindStringSubmatch(line); m != nil {
+				return strings.TrimSpace(m[1])
+			}
+		}
+	}
+	return ""
+}
+
+// formatDecimal membaca angka dengan koma atau titik sebagai pemisah desimal ("20,500" liter)
+func formatDecimal(value string) string {
+	if value == "" {
+		return ""
+	}
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err != nil {
+		return ""
+	}
+	return FormatAmount(number)
+}
+
+func padTime(value string) string {
+	if len(value) == 4 {
+		return "0" + value
+	}
+	return value
+}
+
+// normalizeDate mengubah tanggal D/M/YY atau D-M-YYYY menjadi DD/MM/YYYY
+func normalizeDate(value string) string {
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == '-' || r == '.' })
+	if len(parts) != 3 {
+		return value
+	}
+	day, errDay := strconv.Atoi(parts[0])
+	month, errMonth := strconv.Atoi(parts[1])
+	year, errYear := strconv.Atoi(parts[2])
+	if errDay != nil || errMonth != nil || errYear != nil {
+		return value
+	}
+	if year < 100 {
+		year += 2000
+	}
+	return fmt.Sprintf("%02d/%02d/%04d", day, month, year)
+}
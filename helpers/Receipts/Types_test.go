@@ -0,0 +1,115 @@
+package receipts
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func TestDetectReceiptType(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "restaurant", text: "RM SEDERHANA\nMeja: 12\nPax 4\nNasi Rendang 35.000\nService 5%", want: models.ReceiptTypeRestaurant},
+		{name: "supermarket", text: "INDOMARET\nMember 1234567890\nGula 1 KG x 15.000\nHEMAT", want: models.ReceiptTypeSupermarket},
+		{name: "fuel", text: "SPBU 34.123.45\nPERTALITE\nVolume 20,5 L\nPompa 3", want: models.ReceiptTypeFuel},
+		{name: "parking", text: "PARKIR MALL\nMasuk 10:05\nKeluar 12:30\nMOBIL", want: models.ReceiptTypeParking},
+		{name: "hotel", text: "GRAND HOTEL\nRoom 1203\nCheck-in 10/08/25\nCheck-out 12/08/25", want: models.ReceiptTypeHotel},
+		{name: "no keywords", text: "TOKO SERBA ADA\nBarang 10.000", want: models.ReceiptTypeOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DetectReceiptType(test.text); got != test.want {
+				t.Errorf("DetectReceiptType() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExtractTypeDetails(t *testing.T) {
+	tests := []struct {
+		name        string
+		receiptType string
+		text        string
+		want        *models.ReceiptExtensions
+	}{
+		{
+			name:        "restaurant",
+			receiptType: models.ReceiptTypeRestaurant,
+			text:        "Meja: 12\n4 orang\nWaiter: Budi",
+			want:        &models.ReceiptExtensions{Restaurant: &models.RestaurantExtension{TableNumber: "12", Pax: "4", Waiter: "BUDI"}},
+		},
+		{
+			name:        "supermarket",
+			receiptType: models.ReceiptTypeSupermarket,
+			text:        "Member: 1234567890\nGula Pasir 1 KG x 15.000\nMinyak 2 LTR @ 18.000",
+			want: &models.ReceiptExtensions{Supermarket: &models.SupermarketExtension{
+				MemberID: "1234567890",
+				ItemUnits: []models.ItemUnit{
+					{Name: "GULA PASIR", Quantity: "1", Unit: "kg"},
+					{Name: "MINYAK", Quantity: "2", Unit: "ltr"},
+				},
+			}},
+		},
+		{
+			name:        "fuel",
+			receiptType: models.ReceiptTypeFuel,
+			text:        "Pertalite\nVolume (L): 20,5\nHarga/Liter: Rp 10.000\nPompa 3\nNo Pol: B 1234 XYZ",
+			want:        &models.ReceiptExtensions{Fuel: &models.FuelExtension{FuelType: "PERTALITE", Liters: "20.50", PricePerLiter: "10000.00", PumpNumber: "3", VehiclePlate: "B 1234 XYZ"}},
+		},
+		{
+			name:        "parking",
+			receiptType: models.ReceiptTypeParking,
+			text:        "Masuk 12/08/25 9:05\nKeluar 12/08/25 11:30\nLama Parkir: 2 Jam 25 Menit\nMobil",
+			want:        &models.ReceiptExtensions{Parking: &models.ParkingExtension{EntryTime: "09:05", ExitTime: "11:30", Duration: "2 jam 25 menit", VehicleType: "MOBIL"}},
+		},
+		{
+			// jumlah malam dihitung dari tanggal check-in dan check-out
+			name:        "hotel",
+			receiptType: models.ReceiptTypeHotel,
+			text:        "Guest Name: Siti Aminah\nRoom No 1203\nArrival 10/8/25\nDeparture 12-08-2025",
+			want:        &models.ReceiptExtensions{Hotel: &models.HotelExtension{GuestName: "SITI AMINAH", RoomNumber: "1203", CheckIn: "10/08/2025", CheckOut: "12/08/2025", RoomNights: "2"}},
+		},
+		{name: "nothing found", receiptType: models.ReceiptTypeRestaurant, text: "Nasi Goreng 25.000"},
+		{name: "other", receiptType: models.ReceiptTypeOther, text: "Meja: 12"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ExtractTypeDetails(test.receiptType, test.text); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ExtractTypeDetails() = %s, want %s", describeExtensions(got), describeExtensions(test.want))
+			}
+		})
+	}
+}
+
+func TestKeepTypeExtension(t *testing.T) {
+	fuel := &models.FuelExtension{FuelType: "PERTAMAX"}
+	extensions := &models.ReceiptExtensions{
+		Restaurant: &models.RestaurantExtension{},
+		Fuel:       fuel,
+	}
+
+	if got := KeepTypeExtension(models.ReceiptTypeFuel, extensions); got == nil || got.Fuel != fuel || got.Restaurant != nil {
+		t.Errorf("KeepTypeExtension(fuel) = %s, want only the fuel block", describeExtensions(got))
+	}
+	// blok kosong dari model dibuang
+	if got := KeepTypeExtension(models.ReceiptTypeRestaurant, extensions); got != nil {
+		t.Errorf("KeepTypeExtension(restaurant) = %s, want nil", describeExtensions(got))
+	}
+	if got := KeepTypeExtension(models.ReceiptTypeOther, extensions); got != nil {
+		t.Errorf("KeepTypeExtension(other) = %s, want nil", describeExtensions(got))
+	}
+}
+
+func describeExtensions(extensions *models.ReceiptExtensions) string {
+	if extensions == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("{restaurant:%+v supermarket:%+v fuel:%+v parking:%+v hotel:%+v}", extensions.Restaurant, extensions.Supermarket, extensions.Fuel, extensions.Parking, extensions.Hotel)
+}
@@ -0,0 +1,81 @@
+package receipts
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// Validate memeriksa aritmetika struk: kuantitas x harga per item, jumlah item terhadap subtotal,
+// subtotal - diskon + pajak terhadap total, serta pembayaran - total terhadap kembalian.
+func Validate(receipt models.SplitbillResponse) models.ReceiptValidation {
+	issues := []string{}
+
+	if len(receipt.Items) == 0 {
+		issues = append(issues, "no items found")
+	}
+
+	itemsSum := 0.0
+	for _, item := range receipt.Items {
+		itemTotal := AmountOrZero(item.Total)
+		itemsSum += itemTotal
+
+		quantity := AmountOrZero(item.Quantity)
+		price := AmountOrZero(item.Price)
+		if quantity != 0 && price != 0 && !near(quantity*price, itemTotal) {
+			issues = append(issues, fmt.Sprintf("item %q: quantity x price (%.2f) does not match total (%.2f)", item.Name, quantity*price, itemTotal))
+		}
+	}
+
+	totals := receipt.Totals
+	subtotal, hasSubtotal := ParseAmount(totals.Subtotal)
+	total, hasTotal := ParseAmount(totals.Total)
+	discount := math.Abs(AmountOrZero(totals.Discount))
+	taxes, hasTaxes := ParseAmount(totals.Tax.TotalTax)
+	if !hasTaxes {
+		taxes = AmountOrZero(totals.Tax.Amount) + AmountOrZero(totals.Tax.ServiceCharge)
+	}
+
+	if !hasTotal {
+		issues = append(issues, "total not found")
+	}
+	if !hasSubtotal {
+		subtotal = itemsSum
+	} else if len(receipt.Items) > 0 && !near(itemsSum, subtotal) && !near(itemsSum-discount, subtotal) {
+		issues = append(issues, fmt.Sprintf("items sum (%.2f) does not match subtotal (%.2f)", itemsSum, subtotal))
+	}
+	// Diskon bisa sudah termasuk dalam subtotal atau belum, jadi kedua kemungkinan diterima
+	if hasTotal && !near(subtotal-discount+taxes, total) && !near(subtotal+taxes, total) {
+		issues = append(issues, fmt.Sprintf("subtotal - discount + tax (%.2f) does not match total (%.2f)", subtotal-discount+taxes, total))
+	}
+
+	payment := AmountOrZero(totals.Payment)
+	change, hasChange := ParseAmount(totals.Change)
+	if hasTotal && payment > 0 && hasChange && !near(payment-total, change) {
+		issues = append(issues, fmt.Sprintf("payment - total (%.2f) does not match change (%.2f)", payment-total, change))
+	}
+
+	if date := receipt.TransactionInfo.Date; date != "" {
+		if _, err := time.Parse("02/01/2006", date); err != nil {
+			issues = append(issues, fmt.Sprintf("date %q is not in DD/MM/YYYY format", date))
+		}
+	}
+	if clock := receipt.TransactionInfo.Time; clock != "" {
+		if _, err := time.Parse("15:04", clock); err != nil {
+			issues = append(issues, fmt.Sprintf("time %q is not in HH:MM format", clock))
+		}
+	}
+
+	return models.ReceiptValidation{
+		Valid:  len(issues) == 0,
+		Issues: issues,
+	}
+}
+
+// near membandingkan dua nominal dengan toleransi pembulatan (minimal 1 rupiah atau 0,1%).
+func near(a, b float64) bool {
+	tolerance := math.Max(1, math.Max(math.Abs(a), math.Abs(b))*0.001)
+	return math.Abs(a-b) <= tolerance
+}
@@ -0,0 +1,99 @@
+package receipts
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func validationReceipt() models.SplitbillResponse {
+	var receipt models.SplitbillResponse
+	receipt.Items = []models.Item{
+		{Name: "Teh", Quantity: "2", Price: "5000", Total: "10000"},
+		{Name: "Nasi", Quantity: "1", Price: "25000", Total: "25000"},
+	}
+	receipt.Totals.Subtotal = "35000"
+	receipt.Totals.Tax.TotalTax = "3500"
+	receipt.Totals.Total = "38500"
+	receipt.Totals.Payment = "50000"
+	receipt.Totals.Change = "11500"
+	receipt.TransactionInfo.Date = "12/08/2025"
+	receipt.TransactionInfo.Time = "19:45"
+	return receipt
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(receipt *models.SplitbillResponse)
+		issues []string
+	}{
+		{
+			name:   "valid receipt",
+			change: func(receipt *models.SplitbillResponse) {},
+			issues: []string{},
+		},
+		{
+			name: "discount included in the subtotal",
+			change: func(receipt *models.SplitbillResponse) {
+				receipt.Totals.Discount = "5000"
+				receipt.Totals.Subtotal = "30000"
+				receipt.Totals.Total = "33500"
+				receipt.Totals.Change = "16500"
+			},
+			issues: []string{},
+		},
+		{
+			name:   "rounding within tolerance",
+			change: func(receipt *models.SplitbillResponse) { receipt.Totals.Total = "38510" },
+			issues: []string{},
+		},
+		{
+			name:   "item arithmetic",
+			change: func(receipt *models.SplitbillResponse) { receipt.Items[0].Total = "12000" },
+			issues: []string{
+				`item "Teh": quantity x price (10000.00) does not match total (12000.00)`,
+				"items sum (37000.00) does not match subtotal (35000.00)",
+			},
+		},
+		{
+			name: "total",
+			change: func(receipt *models.SplitbillResponse) {
+				receipt.Totals.Total = "40000"
+				receipt.Totals.Change = "10000"
+			},
+			issues: []string{"subtotal - discount + tax (38500.00) does not match total (40000.00)"},
+		},
+		{
+			name: "missing items and total",
+			change: func(receipt *models.SplitbillResponse) {
+				receipt.Items = nil
+				receipt.Totals = models.Totals{}
+			},
+			issues: []string{"no items found", "total not found"},
+		},
+		{
+			name: "date and time format",
+			change: func(receipt *models.SplitbillResponse) {
+				receipt.TransactionInfo.Date = "2025-08-12"
+				receipt.TransactionInfo.Time = "7.45 PM"
+			},
+			issues: []string{`date "2025-08-12" is not in DD/MM/YYYY format`, `time "7.45 PM" is not in HH:MM format`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			receipt := validationReceipt()
+			test.change(&receipt)
+			validation := Validate(receipt)
+			if !slices.Equal(validation.Issues, test.issues) {
+				t.Errorf("issues = %q, want %q", validation.Issues, test.issues)
+			}
+			if validation.Valid != (len(test.issues) == 0) {
+				t.Errorf("valid = %v with issues %q", validation.Valid, validation.Issues)
+			}
+		})
+	}
+}
@@ -10,6 +10,10 @@ func ResultSuccessJsonApi(c *fiber.Ctx, data any) error {
 	return c.Status(fiber.StatusAccepted).JSON(data)
 }
 
+func ResultSuccessFindJsonApi(c *fiber.Ctx, data any) error {
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
 func ResultSuccessCreateJsonApi(c *fiber.Ctx, data any) error {
 	return c.Status(fiber.StatusCreated).JSON(data)
 }
@@ -0,0 +1,134 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace adalah blok alamat carrier-grade NAT (RFC 6598) yang tidak bisa dijangkau
+// dari internet publik
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// SenderInterface mengirim satu webhook yang sudah di-encode
+type SenderInterface interface {
+	Send(ctx context.Context, targetURL string, secret string, deliveryID string, event string, body []byte) (int, error)
+}
+
+// Sender mengirim webhook lewat HTTP POST dengan batas waktu Timeout per percobaan
+type Sender struct {
+	Client *http.Client
+}
+
+// NewSender membaca batas waktu pengiriman dari WEBHOOK_TIMEOUT (default 10s). Alamat tujuan
+// diperiksa lagi saat koneksi dibuka, sehingga host yang setelah registrasi di-resolve ke alamat
+// internal (DNS rebinding) atau redirect ke alamat internal tetap ditolak. Proxy dari environment
+// tidak dipakai karena pemeriksaan itu hanya melihat alamat yang benar-benar di-dial.
+func NewSender() *Sender {
+	timeout, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Sender{Client: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// Send mengirim body dengan header signature dan timestamp. Status selain 2xx dianggap gagal.
+func (sender *Sender) Send(ctx context.Context, targetURL string, secret string, deliveryID string, event string, body []byte) (int, error) {
+	timestamp := time.Now().Unix()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "splitbill-webhooks/1.0")
+	request.Header.Set(HeaderTimestamp, fmt.Sprint(timestamp))
+	request.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+	request.Header.Set(HeaderDelivery, deliveryID)
+	request.Header.Set(HeaderEvent, event)
+
+	response, err := sender.Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, errors.New(fmt.Sprintf("unexpected status %d", response.StatusCode))
+	}
+	return response.StatusCode, nil
+}
+
+// ValidateURL memastikan callback URL adalah URL http/https absolut yang host-nya tidak mengarah
+// ke alamat loopback, link-local, privat atau alamat internal lain, kecuali WEBHOOK_ALLOW_PRIVATE
+// diaktifkan.
+func ValidateURL(callbackURL string) error {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New(fmt.Sprintf("invalid callback URL %q, an absolute http or https URL is required", callbackURL))
+	}
+	if allowPrivate() {
+		return nil
+	}
+	host := parsed.Hostname()
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return errors.New(fmt.Sprintf("invalid callback URL %q, host could not be resolved: %v", callbackURL, err.Error()))
+		}
+		ips = ips[:0]
+		for _, address := range addresses {
+			ips = append(ips, address.IP)
+		}
+	}
+	for _, ip := range ips {
+		if isInternalIP(ip) {
+			return errors.New(fmt.Sprintf("invalid callback URL %q, host %s resolves to internal address %s", callbackURL, host, ip))
+		}
+	}
+	return nil
+}
+
+// dialControl menolak koneksi ke alamat internal tepat sebelum socket tersambung
+func dialControl(network string, address string, conn syscall.RawConn) error {
+	if allowPrivate() {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+		return errors.New(fmt.Sprintf("webhook target %s is an internal address", host))
+	}
+	return nil
+}
+
+// isInternalIP bernilai true untuk alamat yang tidak boleh menjadi tujuan webhook: loopback,
+// link-local (termasuk metadata cloud 169.254.169.254), privat, carrier-grade NAT, multicast dan
+// alamat kosong
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsPrivate() || ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+func allowPrivate() bool {
+	allow, err := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE"))
+	return err == nil && allow
+}
@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Header yang dikirim bersama setiap webhook
+const (
+	HeaderSignature = "X-Splitbill-Signature"
+	HeaderTimestamp = "X-Splitbill-Timestamp"
+	HeaderDelivery  = "X-Splitbill-Delivery"
+	HeaderEvent     = "X-Splitbill-Event"
+	// HeaderAPIKey mengidentifikasi klien yang mendaftarkan callback URL
+	HeaderAPIKey = "X-API-Key"
+)
+
+// Sign menghitung HMAC-SHA256 dari "<timestamp>.<body>" dengan secret. Penerima menghitung ulang
+// nilai yang sama dan menolak timestamp yang terlalu lama untuk mencegah replay.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify membandingkan signature dengan waktu konstan
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret membuat signing secret acak untuk registrasi webhook
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// KeyID menghitung SHA-256 API key sehingga API key aslinya tidak pernah disimpan
+func KeyID(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
+
+// AllowedKey memeriksa API key terhadap daftar WEBHOOK_API_KEYS (dipisah koma). Jika daftar kosong
+// semua API key diterima, sehingga X-API-Key hanya menjadi identitas dan siapa pun yang mengetahui
+// sebuah API key bisa mengelola webhook-nya.
+func AllowedKey(apiKey string) bool {
+	allowed := os.Getenv("WEBHOOK_API_KEYS")
+	if strings.TrimSpace(allowed) == "" {
+		return true
+	}
+	for _, key := range strings.Split(allowed, ",") {
+		if key = strings.TrimSpace(key); key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			return true
+		}
+	}
+	return false
+}
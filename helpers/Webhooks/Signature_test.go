@@ -0,0 +1,68 @@
+package webhooks
+
+import "testing"
+
+const (
+	testSecret    = "whsec_test"
+	testTimestamp = int64(1700000000)
+	testBody      = `{"event":"extraction.completed"}`
+	// testSignature adalah HMAC-SHA256 "1700000000.<testBody>" dengan testSecret, dihitung dengan openssl
+	testSignature = "sha256=69a0d25c791feaf368d10a1b9a20b58810bb583c9f9c047d13ae691db4003b10"
+)
+
+func TestSign(t *testing.T) {
+	if got := Sign(testSecret, testTimestamp, []byte(testBody)); got != testSignature {
+		t.Errorf("Sign() = %q, want %q", got, testSignature)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: testSecret, timestamp: testTimestamp, body: testBody, signature: testSignature, want: true},
+		{name: "wrong secret", secret: "whsec_other", timestamp: testTimestamp, body: testBody, signature: testSignature},
+		{name: "other timestamp", secret: testSecret, timestamp: testTimestamp + 1, body: testBody, signature: testSignature},
+		{name: "tampered body", secret: testSecret, timestamp: testTimestamp, body: `{"event":"extraction.failed"}`, signature: testSignature},
+		{name: "without prefix", secret: testSecret, timestamp: testTimestamp, body: testBody, signature: testSignature[len("sha256="):]},
+		{name: "uppercase hex", secret: testSecret, timestamp: testTimestamp, body: testBody, signature: "sha256=69A0D25C791FEAF368D10A1B9A20B58810BB583C9F9C047D13AE691DB4003B10"},
+		{name: "empty", secret: testSecret, timestamp: testTimestamp, body: testBody, signature: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Verify(test.secret, test.timestamp, []byte(test.body), test.signature); got != test.want {
+				t.Errorf("Verify() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAllowedKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed string
+		apiKey  string
+		want    bool
+	}{
+		{name: "no list accepts any key", allowed: "", apiKey: "anything", want: true},
+		{name: "listed key", allowed: "key-a, key-b", apiKey: "key-b", want: true},
+		{name: "unlisted key", allowed: "key-a,key-b", apiKey: "key-c", want: false},
+		{name: "prefix of a listed key", allowed: "key-a", apiKey: "key", want: false},
+		{name: "empty entries ignored", allowed: "key-a,,", apiKey: "", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("WEBHOOK_API_KEYS", test.allowed)
+			if got := AllowedKey(test.apiKey); got != test.want {
+				t.Errorf("AllowedKey(%q) = %v, want %v", test.apiKey, got, test.want)
+			}
+		})
+	}
+}
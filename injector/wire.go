@@ -5,21 +5,94 @@ package injector
 
 import (
 	"github.com/arifin2018/splitbill-arifin.git/controllers"
+	metricscontrollers "github.com/arifin2018/splitbill-arifin.git/controllers/MetricsControllers"
+	reviewcontrollers "github.com/arifin2018/splitbill-arifin.git/controllers/ReviewControllers"
 	splitbillcontollers "github.com/arifin2018/splitbill-arifin.git/controllers/SplitbillContollers"
+	webhookcontrollers "github.com/arifin2018/splitbill-arifin.git/controllers/WebhookControllers"
+	caches "github.com/arifin2018/splitbill-arifin.git/helpers/Caches"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	images "github.com/arifin2018/splitbill-arifin.git/helpers/Images"
+	jobs "github.com/arifin2018/splitbill-arifin.git/helpers/Jobs"
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	webhooks "github.com/arifin2018/splitbill-arifin.git/helpers/Webhooks"
+	files "github.com/arifin2018/splitbill-arifin.git/helpers/files"
+	jobrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/JobRepositories"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	webhookrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/WebhookRepositories"
+	metricsservices "github.com/arifin2018/splitbill-arifin.git/services/MetricsServices"
+	reviewservices "github.com/arifin2018/splitbill-arifin.git/services/ReviewServices"
 	splitbillservices "github.com/arifin2018/splitbill-arifin.git/services/SplitbillServices"
+	webhookservices "github.com/arifin2018/splitbill-arifin.git/services/WebhookServices"
 	"github.com/google/wire"
 )
 
+var receiptRepository = wire.NewSet(
+	documents.NewDocumentStore,
+	receiptrepositories.NewReceiptRepositoryImpl,
+	wire.Bind(new(receiptrepositories.ReceiptRepository), new(*receiptrepositories.ReceiptRepositoryImpl)),
+	jobrepositories.NewJobRepositoryImpl,
+	wire.Bind(new(jobrepositories.JobRepository), new(*jobrepositories.JobRepositoryImpl)),
+	webhookrepositories.NewWebhookRepositoryImpl,
+	wire.Bind(new(webhookrepositories.WebhookRepository), new(*webhookrepositories.WebhookRepositoryImpl)),
+)
+
 var splitbilController = wire.NewSet(
+	limits.NewLimiter,
+	wire.Bind(new(limits.LimiterInterface), new(*limits.Limiter)),
+	extractors.NewGeminiKeyPool,
+	extractors.NewExtractor,
+	extractors.NewClassifier,
+	images.NewUploadGuard,
+	wire.Bind(new(images.UploadGuardInterface), new(*images.UploadGuard)),
+	images.NewNormalizer,
+	wire.Bind(new(images.NormalizerInterface), new(*images.Normalizer)),
+	images.NewQualityChecker,
+	wire.Bind(new(images.QualityCheckerInterface), new(*images.QualityChecker)),
+	images.NewPreprocessor,
+	wire.Bind(new(images.PreprocessorInterface), new(*images.Preprocessor)),
+	files.NewSpool,
+	wire.Bind(new(files.SpoolInterface), new(*files.Spool)),
+	caches.NewExtractionCache,
+	jobs.NewPool,
+	wire.Bind(new(jobs.PoolInterface), new(*jobs.Pool)),
 	splitbillservices.NewSplitbillServiceImpl,
 	wire.Bind(new(splitbillservices.SplibillService), new(*splitbillservices.SplibillServiceImpl)),
 	splitbillcontollers.NewSplitbilController,
 	wire.Bind(new(splitbillcontollers.SplitbilController), new(*splitbillcontollers.SplitbillControllerImpl)),
 )
 
+var webhookController = wire.NewSet(
+	webhooks.NewSender,
+	wire.Bind(new(webhooks.SenderInterface), new(*webhooks.Sender)),
+	webhookservices.NewWebhookServiceImpl,
+	wire.Bind(new(webhookservices.WebhookService), new(*webhookservices.WebhookServiceImpl)),
+	wire.Bind(new(webhookservices.WebhookNotifier), new(*webhookservices.WebhookServiceImpl)),
+	webhookcontrollers.NewWebhookController,
+	wire.Bind(new(webhookcontrollers.WebhookController), new(*webhookcontrollers.WebhookControllerImpl)),
+)
+
+var reviewController = wire.NewSet(
+	reviewservices.NewReviewServiceImpl,
+	wire.Bind(new(reviewservices.ReviewService), new(*reviewservices.ReviewServiceImpl)),
+	reviewcontrollers.NewReviewController,
+	wire.Bind(new(reviewcontrollers.ReviewController), new(*reviewcontrollers.ReviewControllerImpl)),
+)
+
+var metricsController = wire.NewSet(
+	metricsservices.NewMetricsServiceImpl,
+	wire.Bind(new(metricsservices.MetricsService), new(*metricsservices.MetricsServiceImpl)),
+	metricscontrollers.NewMetricsController,
+	wire.Bind(new(metricscontrollers.MetricsController), new(*metricscontrollers.MetricsControllerImpl)),
+)
+
 var setAllControllers = wire.NewSet(
 	// ProvideDB,
+	receiptRepository,
 	splitbilController,
+	reviewController,
+	webhookController,
+	metricsController,
 	wire.Struct(new(controllers.AllControllers), "*"),
 )
 
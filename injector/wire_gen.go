@@ -8,27 +8,81 @@ package injector
 
 import (
 	"github.com/arifin2018/splitbill-arifin.git/controllers"
+	"github.com/arifin2018/splitbill-arifin.git/controllers/MetricsControllers"
+	"github.com/arifin2018/splitbill-arifin.git/controllers/ReviewControllers"
 	"github.com/arifin2018/splitbill-arifin.git/controllers/SplitbillContollers"
+	"github.com/arifin2018/splitbill-arifin.git/controllers/WebhookControllers"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Caches"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Images"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Jobs"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/Webhooks"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/files"
+	"github.com/arifin2018/splitbill-arifin.git/repositories/JobRepositories"
+	"github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	"github.com/arifin2018/splitbill-arifin.git/repositories/WebhookRepositories"
+	"github.com/arifin2018/splitbill-arifin.git/services/MetricsServices"
+	"github.com/arifin2018/splitbill-arifin.git/services/ReviewServices"
 	"github.com/arifin2018/splitbill-arifin.git/services/SplitbillServices"
+	"github.com/arifin2018/splitbill-arifin.git/services/WebhookServices"
 	"github.com/google/wire"
 )
 
 // Injectors from wire.go:
 
 func InitializeController() *controllers.AllControllers {
-	splibillServiceImpl := splitbillservices.NewSplitbillServiceImpl()
+	geminiKeyPool := extractors.NewGeminiKeyPool()
+	limiter := limits.NewLimiter()
+	extractorInterface := extractors.NewExtractor(geminiKeyPool, limiter)
+	classifierInterface := extractors.NewClassifier(geminiKeyPool, limiter)
+	uploadGuard := images.NewUploadGuard()
+	normalizer := images.NewNormalizer()
+	qualityChecker := images.NewQualityChecker()
+	preprocessor := images.NewPreprocessor()
+	spool := files.NewSpool()
+	extractionCacheInterface := caches.NewExtractionCache()
+	documentStoreInterface := documents.NewDocumentStore()
+	receiptRepositoryImpl := receiptrepositories.NewReceiptRepositoryImpl(documentStoreInterface)
+	jobRepositoryImpl := jobrepositories.NewJobRepositoryImpl(documentStoreInterface)
+	pool := jobs.NewPool()
+	webhookRepositoryImpl := webhookrepositories.NewWebhookRepositoryImpl(documentStoreInterface)
+	sender := webhooks.NewSender()
+	webhookServiceImpl := webhookservices.NewWebhookServiceImpl(webhookRepositoryImpl, sender)
+	splibillServiceImpl := splitbillservices.NewSplitbillServiceImpl(extractorInterface, classifierInterface, uploadGuard, normalizer, qualityChecker, preprocessor, receiptRepositoryImpl, spool, extractionCacheInterface, jobRepositoryImpl, pool, webhookServiceImpl)
 	splitbillControllerImpl := splitbillcontollers.NewSplitbilController(splibillServiceImpl)
+	reviewServiceImpl := reviewservices.NewReviewServiceImpl(receiptRepositoryImpl)
+	reviewControllerImpl := reviewcontrollers.NewReviewController(reviewServiceImpl)
+	webhookControllerImpl := webhookcontrollers.NewWebhookController(webhookServiceImpl)
+	metricsServiceImpl := metricsservices.NewMetricsServiceImpl(limiter, geminiKeyPool)
+	metricsControllerImpl := metricscontrollers.NewMetricsController(metricsServiceImpl)
 	allControllers := &controllers.AllControllers{
 		SplitbilController: splitbillControllerImpl,
+		ReviewController:   reviewControllerImpl,
+		WebhookController:  webhookControllerImpl,
+		MetricsController:  metricsControllerImpl,
 	}
 	return allControllers
 }
 
 // wire.go:
 
-var splitbilController = wire.NewSet(splitbillservices.NewSplitbillServiceImpl, wire.Bind(new(splitbillservices.SplibillService), new(*splitbillservices.SplibillServiceImpl)), splitbillcontollers.NewSplitbilController, wire.Bind(new(splitbillcontollers.SplitbilController), new(*splitbillcontollers.SplitbillControllerImpl)))
+var receiptRepository = wire.NewSet(documents.NewDocumentStore, receiptrepositories.NewReceiptRepositoryImpl, wire.Bind(new(receiptrepositories.ReceiptRepository), new(*receiptrepositories.ReceiptRepositoryImpl)), jobrepositories.NewJobRepositoryImpl, wire.Bind(new(jobrepositories.JobRepository), new(*jobrepositories.JobRepositoryImpl)), webhookrepositories.NewWebhookRepositoryImpl, wire.Bind(new(webhookrepositories.WebhookRepository), new(*webhookrepositories.WebhookRepositoryImpl)))
+
+var splitbilController = wire.NewSet(limits.NewLimiter, wire.Bind(new(limits.LimiterInterface), new(*limits.Limiter)), extractors.NewGeminiKeyPool, extractors.NewExtractor, extractors.NewClassifier, images.NewUploadGuard, wire.Bind(new(images.UploadGuardInterface), new(*images.UploadGuard)), images.NewNormalizer, wire.Bind(new(images.NormalizerInterface), new(*images.Normalizer)), images.NewQualityChecker, wire.Bind(new(images.QualityCheckerInterface), new(*images.QualityChecker)), images.NewPreprocessor, wire.Bind(new(images.PreprocessorInterface), new(*images.Preprocessor)), files.NewSpool, wire.Bind(new(files.SpoolInterface), new(*files.Spool)), caches.NewExtractionCache, jobs.NewPool, wire.Bind(new(jobs.PoolInterface), new(*jobs.Pool)), splitbillservices.NewSplitbillServiceImpl, wire.Bind(new(splitbillservices.SplibillService), new(*splitbillservices.SplibillServiceImpl)), splitbillcontollers.NewSplitbilController, wire.Bind(new(splitbillcontollers.SplitbilController), new(*splitbillcontollers.SplitbillControllerImpl)))
+
+var webhookController = wire.NewSet(webhooks.NewSender, wire.Bind(new(webhooks.SenderInterface), new(*webhooks.Sender)), webhookservices.NewWebhookServiceImpl, wire.Bind(new(webhookservices.WebhookService), new(*webhookservices.WebhookServiceImpl)), wire.Bind(new(webhookservices.WebhookNotifier), new(*webhookservices.WebhookServiceImpl)), webhookcontrollers.NewWebhookController, wire.Bind(new(webhookcontrollers.WebhookController), new(*webhookcontrollers.WebhookControllerImpl)))
+
+var reviewController = wire.NewSet(reviewservices.NewReviewServiceImpl, wire.Bind(new(reviewservices.ReviewService), new(*reviewservices.ReviewServiceImpl)), reviewcontrollers.NewReviewController, wire.Bind(new(reviewcontrollers.ReviewController), new(*reviewcontrollers.ReviewControllerImpl)))
+
+var metricsController = wire.NewSet(metricsservices.NewMetricsServiceImpl, wire.Bind(new(metricsservices.MetricsService), new(*metricsservices.MetricsServiceImpl)), metricscontrollers.NewMetricsController, wire.Bind(new(metricscontrollers.MetricsController), new(*metricscontrollers.MetricsControllerImpl)))
 
 var setAllControllers = wire.NewSet(
 
-	splitbilController, wire.Struct(new(controllers.AllControllers), "*"),
+	receiptRepository,
+	splitbilController,
+	reviewController,
+	webhookController,
+	metricsController, wire.Struct(new(controllers.AllControllers), "*"),
 )
@@ -7,6 +7,7 @@ import (
 
 	"github.com/arifin2018/splitbill-arifin.git/config"
 	appconfig "github.com/arifin2018/splitbill-arifin.git/config/appConfig"
+	jobs "github.com/arifin2018/splitbill-arifin.git/helpers/Jobs"
 	"github.com/arifin2018/splitbill-arifin.git/routes"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -30,7 +31,11 @@ import (
 // @host localhost:3000
 // @BasePath /
 func main() {
-	app := fiber.New()
+	appconfig.InitApplication()
+	app := fiber.New(fiber.Config{
+		// Batas seluruh request multipart; batas per file diatur UPLOAD_MAX_BYTES
+		BodyLimit: appconfig.BodyLimit(),
+	})
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -43,7 +48,6 @@ func main() {
 		_ = app.Shutdown()
 		serverShutdown <- struct{}{}
 	}()
-	appconfig.InitApplication()
 	app.Use(cors.New())
 
 	config.ConnectFirebase()
@@ -61,4 +65,6 @@ func main() {
 
 	config.GeneralLogger.Println("Running cleanup tasks...")
 	fmt.Println("Running cleanup tasks...")
+	// Hentikan loop background (spool, purge cache, retry webhook) setelah server tidak menerima request
+	jobs.Shutdown()
 }
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Status batch. Batch selesai (completed) setelah semua file-nya selesai, berhasil maupun gagal.
+const (
+	BatchStatusQueued    = "queued"
+	BatchStatusRunning   = "running"
+	BatchStatusCompleted = "completed"
+)
+
+// Batch represents a batch of receipts uploaded together, one extraction job per file. Status,
+// counters, progress and the per-file state are computed from the jobs when the batch is read.
+type Batch struct {
+	ID        string      `json:"id" example:"0d6f3b2a-5c8e-4f1a-9b7d-2e4c6a8f0b1d"`
+	Status    string      `json:"status" example:"running"`
+	Total     int         `json:"total" example:"52"`
+	Completed int         `json:"completed" example:"20"`
+	Succeeded int         `json:"succeeded" example:"18"`
+	Failed    int         `json:"failed" example:"2"`
+	Progress  int         `json:"progress" example:"41"`
+	Files     []BatchFile `json:"files"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// BatchFile represents one file of a batch. Files rejected before extraction (for example too large
+// or unsupported) have no job and are failed with Error.
+type BatchFile struct {
+	Index     int                `json:"index" example:"1"`
+	Filename  string             `json:"filename" example:"2025-08/struk-parkir.jpg"`
+	JobID     string             `json:"job_id,omitempty" example:"3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"`
+	Status    string             `json:"status" example:"succeeded"`
+	Stage     string             `json:"stage,omitempty" example:"done"`
+	Progress  int                `json:"progress" example:"100"`
+	ReceiptID string             `json:"receipt_id,omitempty" example:"6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	Result    *SplitbillResponse `json:"result,omitempty"`
+	Error     *JobError          `json:"error,omitempty"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Score adalah confidence antara 0 dan 1. Model kadang mengembalikan angka sebagai string atau
+// dalam persen, sehingga keduanya diterima dan dinormalisasi ke rentang 0-1.
+type Score float64
+
+func (score *Score) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	text = strings.TrimSuffix(strings.TrimSpace(text), "%")
+	if text == "" || text == "null" {
+		*score = 0
+		return nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		*score = 0
+		return nil
+	}
+	if value > 1 {
+		value = value / 100
+	}
+	*score = Score(min(max(value, 0), 1))
+	return nil
+}
+
+// ItemConfidence represents the confidence of an item line and each of its fields
+type ItemConfidence struct {
+	Line     Score `json:"line" example:"0.82"`
+	Name     Score `json:"name" example:"0.97"`
+	Price    Score `json:"price" example:"0.55"`
+	Quantity Score `json:"quantity" example:"0.95"`
+	Total    Score `json:"total" example:"0.9"`
+}
+
+// UnmarshalJSON juga menerima satu angka yang dianggap sebagai confidence baris item
+func (confidence *ItemConfidence) UnmarshalJSON(data []byte) error {
+	type plainConfidence ItemConfidence
+	var plain plainConfidence
+	if err := json.Unmarshal(data, &plain); err == nil {
+		*confidence = ItemConfidence(plain)
+		return nil
+	}
+	var line Score
+	if err := line.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*confidence = ItemConfidence{Line: line}
+	return nil
+}
+
+// BoundingBox represents the location of the source text in the image as fractions (0-1) of the
+// image width and height. Section is the 1-based photo number for receipts uploaded in sections.
+type BoundingBox struct {
+	XMin    float64 `json:"x_min" example:"0.08"`
+	YMin    float64 `json:"y_min" example:"0.41"`
+	XMax    float64 `json:"x_max" example:"0.92"`
+	YMax    float64 `json:"y_max" example:"0.44"`
+	Section int     `json:"section,omitempty" example:"0"`
+}
+
+// UnmarshalJSON menerima format model ([ymin, xmin, ymax, xmax] dalam skala 0-1000) maupun format
+// objek yang disimpan di receipt record. Box yang tidak valid diabaikan.
+func (box *BoundingBox) UnmarshalJSON(data []byte) error {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err == nil {
+		*box = BoundingBox{}
+		if len(values) != 4 {
+			return nil
+		}
+		coordinates := make([]float64, 4)
+		for i, value := range values {
+			coordinate, err := strconv.ParseFloat(strings.Trim(string(value), `" `), 64)
+			if err != nil {
+				return nil
+			}
+			coordinates[i] = min(max(coordinate/1000, 0), 1)
+		}
+		*box = BoundingBox{YMin: coordinates[0], XMin: coordinates[1], YMax: coordinates[2], XMax: coordinates[3]}
+		return nil
+	}
+
+	type plainBox BoundingBox
+	var plain plainBox
+	if err := json.Unmarshal(data, &plain); err != nil {
+		*box = BoundingBox{}
+		return nil
+	}
+	*box = BoundingBox(plain)
+	return nil
+}
+
+// Valid bernilai true jika box memiliki luas
+func (box *BoundingBox) Valid() bool {
+	return box != nil && box.XMax > box.XMin && box.YMax > box.YMin
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Status job ekstraksi async
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Tahap pemrosesan yang dilaporkan di Job.Stage
+const (
+	JobStageQueued        = "queued"
+	JobStagePreprocessing = "preprocessing"
+	JobStageExtracting    = "extracting"
+	JobStageSaving        = "saving"
+	JobStageDone          = "done"
+)
+
+// UploadedFile adalah file yang sudah dibaca dari request. Disimpan sebagai input job async
+// sehingga job bisa dijalankan ulang setelah restart.
+type UploadedFile struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// JobInput adalah file yang diproses oleh satu job
+type JobInput struct {
+	JobID string         `json:"job_id"`
+	Files []UploadedFile `json:"files"`
+}
+
+// Job represents an asynchronous extraction job
+type Job struct {
+	ID         string             `json:"id" example:"3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"`
+	Status     string             `json:"status" example:"running"`
+	Stage      string             `json:"stage" example:"extracting"`
+	Progress   int                `json:"progress" example:"30"`
+	Attempts   int                `json:"attempts" example:"1"`
+	Result     *SplitbillResponse `json:"result,omitempty"`
+	Error      *JobError          `json:"error,omitempty"`
+	Callback   *WebhookTarget     `json:"callback,omitempty"`
+	BatchID    string             `json:"batch_id,omitempty" example:"0d6f3b2a-5c8e-4f1a-9b7d-2e4c6a8f0b1d"`
+	CreatedAt  time.Time          `json:"created_at"`
+	StartedAt  *time.Time         `json:"started_at,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// JobError represents the error of a failed job with the HTTP status and code the synchronous
+// request would have returned
+type JobError struct {
+	Status  int    `json:"status" example:"406"`
+	Code    string `json:"code,omitempty" example:"image_blurry"`
+	Message string `json:"message" example:"photo quality is too low (image_blurry), please retake the photo"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// JobAccepted represents the response of an accepted asynchronous upload
+type JobAccepted struct {
+	JobID     string `json:"job_id" example:"3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"`
+	Status    string `json:"status" example:"queued"`
+	StatusURL string `json:"status_url" example:"/jobs/3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"`
+}
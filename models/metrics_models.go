@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Metrics represents the runtime metrics of the service
+type Metrics struct {
+	Extraction ExtractionMetrics  `json:"extraction"`
+	GeminiKeys []GeminiKeyMetrics `json:"gemini_keys"`
+}
+
+// ExtractionMetrics represents the state of the global limiter on in-flight model calls.
+// Queued counts requests waiting in the bounded queue; QueuedBackground counts async and batch
+// jobs, which wait for a slot without the queue bound and timeout.
+type ExtractionMetrics struct {
+	MaxInFlight      int         `json:"max_in_flight" example:"8"`
+	InFlight         int         `json:"in_flight" example:"8"`
+	QueueSize        int         `json:"queue_size" example:"32"`
+	Queued           int         `json:"queued" example:"5"`
+	QueuedBackground int         `json:"queued_background" example:"2"`
+	QueueTimeoutMs   int64       `json:"queue_timeout_ms" example:"30000"`
+	Acquired         int64       `json:"acquired" example:"1520"`
+	Rejected         int64       `json:"rejected" example:"12"`
+	TimedOut         int64       `json:"timed_out" example:"3"`
+	Canceled         int64       `json:"canceled" example:"1"`
+	Wait             WaitMetrics `json:"wait"`
+}
+
+// WaitMetrics represents the time spent waiting for an extraction slot. Buckets counts waits
+// per upper bound in milliseconds ("+Inf" for longer waits).
+type WaitMetrics struct {
+	Count   int64            `json:"count" example:"1520"`
+	TotalMs int64            `json:"total_ms" example:"84210"`
+	AvgMs   int64            `json:"avg_ms" example:"55"`
+	MaxMs   int64            `json:"max_ms" example:"12840"`
+	Buckets map[string]int64 `json:"buckets"`
+}
+
+// GeminiKeyMetrics represents the usage counters of one Gemini API key in the key pool. Key only
+// shows the last 4 characters of the API key. A benched key hit its quota and is skipped until
+// BenchedUntil.
+type GeminiKeyMetrics struct {
+	ID           string     `json:"id" example:"key-1"`
+	Key          string     `json:"key" example:"...x9Qa"`
+	Requests     int64      `json:"requests" example:"812"`
+	Succeeded    int64      `json:"succeeded" example:"790"`
+	Failed       int64      `json:"failed" example:"22"`
+	QuotaErrors  int64      `json:"quota_errors" example:"4"`
+	Benches      int64      `json:"benches" example:"4"`
+	Benched      bool       `json:"benched" example:"true"`
+	BenchedUntil *time.Time `json:"benched_until,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Event progress ekstraksi. Dikirim lewat stream SSE dan dipakai untuk menentukan tahap job async.
+const (
+	ProgressEventUploaded     = "uploaded"
+	ProgressEventPreprocessed = "preprocessed"
+	ProgressEventStored       = "stored"
+	ProgressEventModelStarted = "model_started"
+	ProgressEventItem         = "item"
+	ProgressEventValidated    = "validated"
+	ProgressEventDone         = "done"
+	ProgressEventError        = "error"
+)
+
+// ProgressEvent represents one progress event of an extraction. Only the fields relevant to the
+// event are filled: Item for "item", ImageURLs for "stored", Validation for "validated", Result for
+// "done" and Error for "error".
+type ProgressEvent struct {
+	Event      string             `json:"event" example:"item"`
+	Progress   int                `json:"progress,omitempty" example:"40"`
+	Index      int                `json:"index,omitempty" example:"2"`
+	Item       *Item              `json:"item,omitempty"`
+	ImageURLs  []string           `json:"image_urls,omitempty"`
+	Validation *ReceiptValidation `json:"validation,omitempty"`
+	Result     *SplitbillResponse `json:"result,omitempty"`
+	Error      *JobError          `json:"error,omitempty"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+const (
+	ReceiptSourceImage    = "image"
+	ReceiptSourceSections = "sections"
+	ReceiptSourcePDF      = "pdf"
+	ReceiptSourceText     = "text"
+	ReceiptSourceHTML     = "html"
+)
+
+// Receipt represents a stored receipt record with its source files and extraction result.
+// Receipts detected in the same photo share a SourceGroupID and are ordered by SourceIndex.
+// LockedAt is set once the bill is locked; a locked receipt can no longer be reviewed or merged.
+type Receipt struct {
+	ID            string            `json:"id" example:"6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	SourceType    string            `json:"source_type" example:"sections"`
+	SourceURLs    []string          `json:"source_urls"`
+	SourceGroupID string            `json:"source_group_id,omitempty" example:"0b9d6c1e-7f3a-4c2d-8e5b-1a2b3c4d5e6f"`
+	SourceIndex   int               `json:"source_index" example:"0"`
+	ImageHash     string            `json:"image_hash,omitempty" example:"f0e4c2d8a1b3c5e7"`
+	Data          SplitbillResponse `json:"data"`
+	LockedAt      *time.Time        `json:"locked_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Label hasil klasifikasi dokumen sebelum ekstraksi
+const (
+	DocumentClassReceipt     = "receipt"
+	DocumentClassInvoice     = "invoice"
+	DocumentClassMenu        = "menu"
+	DocumentClassNonDocument = "non_document"
+	DocumentClassUnreadable  = "unreadable"
+)
+
+// DocumentClass represents the pre-extraction classification of an uploaded image
+type DocumentClass struct {
+	Label      string  `json:"label" example:"receipt"`
+	Confidence float64 `json:"confidence" example:"0.97"`
+	Reason     string  `json:"reason,omitempty" example:"Printed store receipt with item list and total"`
+}
+
+// ImageQuality represents the quality scores of an uploaded photo measured before extraction
+type ImageQuality struct {
+	Passed            bool     `json:"passed" example:"true"`
+	Issues            []string `json:"issues,omitempty"`
+	Sharpness         float64  `json:"sharpness" example:"412.7"`
+	Brightness        float64  `json:"brightness" example:"168.2"`
+	OverexposedRatio  float64  `json:"overexposed_ratio" example:"0.04"`
+	UnderexposedRatio float64  `json:"underexposed_ratio" example:"0.01"`
+	Width             int      `json:"width" example:"1080"`
+	Height            int      `json:"height" example:"1920"`
+}
@@ -0,0 +1,68 @@
+package models
+
+// Jenis struk yang dikenali extractor
+const (
+	ReceiptTypeRestaurant  = "restaurant"
+	ReceiptTypeSupermarket = "supermarket"
+	ReceiptTypeFuel        = "fuel"
+	ReceiptTypeParking     = "parking"
+	ReceiptTypeHotel       = "hotel"
+	ReceiptTypeOther       = "other"
+)
+
+// ReceiptExtensions holds the type-specific fields of a receipt. Only the block matching
+// SplitbillResponse.ReceiptType is filled.
+type ReceiptExtensions struct {
+	Restaurant  *RestaurantExtension  `json:"restaurant,omitempty"`
+	Supermarket *SupermarketExtension `json:"supermarket,omitempty"`
+	Fuel        *FuelExtension        `json:"fuel,omitempty"`
+	Parking     *ParkingExtension     `json:"parking,omitempty"`
+	Hotel       *HotelExtension       `json:"hotel,omitempty"`
+}
+
+// RestaurantExtension represents restaurant bill details
+type RestaurantExtension struct {
+	TableNumber string `json:"table_number" example:"12"`
+	Pax         string `json:"pax" example:"4"`
+	Waiter      string `json:"waiter" example:"Budi"`
+}
+
+// SupermarketExtension represents supermarket receipt details
+type SupermarketExtension struct {
+	MemberID  string     `json:"member_id" example:"8800123456"`
+	ItemUnits []ItemUnit `json:"item_units"`
+}
+
+// ItemUnit represents the unit of measure of an item, matched to Items by name
+type ItemUnit struct {
+	Name     string `json:"name" example:"BERAS PANDAN WANGI"`
+	Quantity string `json:"quantity" example:"5"`
+	Unit     string `json:"unit" example:"kg"`
+}
+
+// FuelExtension represents fuel station receipt details
+type FuelExtension struct {
+	FuelType      string `json:"fuel_type" example:"PERTALITE"`
+	Liters        string `json:"liters" example:"20.50"`
+	PricePerLiter string `json:"price_per_liter" example:"10000.00"`
+	PumpNumber    string `json:"pump_number" example:"3"`
+	VehiclePlate  string `json:"vehicle_plate" example:"B 1234 XYZ"`
+}
+
+// ParkingExtension represents parking ticket details
+type ParkingExtension struct {
+	EntryTime    string `json:"entry_time" example:"08:15"`
+	ExitTime     string `json:"exit_time" example:"17:40"`
+	Duration     string `json:"duration" example:"9 jam 25 menit"`
+	VehiclePlate string `json:"vehicle_plate" example:"B 1234 XYZ"`
+	VehicleType  string `json:"vehicle_type" example:"MOBIL"`
+}
+
+// HotelExtension represents hotel folio details
+type HotelExtension struct {
+	GuestName  string `json:"guest_name" example:"ARIFIN"`
+	RoomNumber string `json:"room_number" example:"1207"`
+	CheckIn    string `json:"check_in" example:"10/08/2025"`
+	CheckOut   string `json:"check_out" example:"12/08/2025"`
+	RoomNights string `json:"room_nights" example:"2"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Status review receipt. Receipt yang tidak perlu direview bernilai not_required; receipt yang
+// ditandai mulai dari pending_review lalu berpindah ke approved atau rejected.
+const (
+	ReviewStatusNotRequired   = "not_required"
+	ReviewStatusPendingReview = "pending_review"
+	ReviewStatusApproved      = "approved"
+	ReviewStatusRejected      = "rejected"
+)
+
+// Alasan receipt masuk antrean review
+const (
+	ReviewReasonValidationFailed = "validation_failed"
+	ReviewReasonLowConfidence    = "low_confidence"
+)
+
+// Keputusan reviewer untuk receipt di antrean review
+const (
+	ReviewActionApprove = "approve"
+	ReviewActionCorrect = "correct"
+	ReviewActionReject  = "reject"
+)
+
+// ReceiptReview represents the review state of a receipt
+type ReceiptReview struct {
+	Status     string     `json:"status" example:"pending_review"`
+	Reasons    []string   `json:"reasons,omitempty" example:"validation_failed,low_confidence"`
+	Reviewer   string     `json:"reviewer,omitempty" example:"budi@example.com"`
+	Note       string     `json:"note,omitempty" example:"Harga item kedua dikoreksi dari foto"`
+	Corrected  bool       `json:"corrected,omitempty" example:"false"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// ReviewRequest represents a reviewer's decision. Data is required for "correct" and replaces the
+// items, store information, totals, transaction information and type-specific fields.
+type ReviewRequest struct {
+	Action   string             `json:"action" form:"action" example:"correct"`
+	Reviewer string             `json:"reviewer" form:"reviewer" example:"budi@example.com"`
+	Note     string             `json:"note" form:"note" example:"Harga item kedua dikoreksi dari foto"`
+	Data     *SplitbillResponse `json:"data,omitempty"`
+}
+
+// ReviewQueueItem represents a receipt in the review queue with its stored images next to the
+// extracted data
+type ReviewQueueItem struct {
+	ReceiptID           string            `json:"receipt_id" example:"6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	Status              string            `json:"status" example:"pending_review"`
+	Reasons             []string          `json:"reasons" example:"validation_failed,low_confidence"`
+	ImageURLs           []string          `json:"image_urls"`
+	ImageStatus         string            `json:"image_status,omitempty" example:"stored"`
+	LowConfidenceFields []string          `json:"low_confidence_fields,omitempty" example:"items[2].price"`
+	ValidationIssues    []string          `json:"validation_issues,omitempty"`
+	Data                SplitbillResponse `json:"data"`
+	CreatedAt           time.Time         `json:"created_at"`
+}
+
+// ReviewQueuePage represents one page of the review queue with the number of receipts in the queue
+type ReviewQueuePage struct {
+	Reviews []ReviewQueueItem `json:"reviews"`
+	Total   int               `json:"total" example:"120"`
+	Limit   int               `json:"limit" example:"50"`
+	Offset  int               `json:"offset" example:"0"`
+}
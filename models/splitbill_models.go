@@ -1,11 +1,50 @@
 package models
 
+// Status penyimpanan file sumber struk di bucket
+const (
+	ImageStatusStored  = "stored"
+	ImageStatusPending = "pending"
+	ImageStatusFailed  = "failed"
+)
+
 // SplitbillResponse represents the response structure for splitbill API
 type SplitbillResponse struct {
-	Items            []Item           `json:"items"`
-	StoreInformation StoreInformation `json:"store_information"`
-	Totals           Totals           `json:"totals"`
-	TransactionInfo  TransactionInfo  `json:"transaction_information"`
+	Items            []Item             `json:"items"`
+	StoreInformation StoreInformation   `json:"store_information"`
+	Totals           Totals             `json:"totals"`
+	TransactionInfo  TransactionInfo    `json:"transaction_information"`
+	ReceiptType      string             `json:"receipt_type,omitempty" example:"restaurant"`
+	Extensions       *ReceiptExtensions `json:"extensions,omitempty"`
+	ReceiptID        string             `json:"receipt_id,omitempty" example:"6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	SourceURL        string             `json:"source_url,omitempty" example:"https://firebasestorage.googleapis.com/v0/b/bucket/o/images/20250802193000_struk.jpg?alt=media"`
+	Validation       *ReceiptValidation `json:"validation,omitempty"`
+	Classification   *DocumentClass     `json:"classification,omitempty"`
+	// FieldConfidence berisi confidence model (0-1) untuk field di luar items, dengan key berupa
+	// path field seperti "totals.total" atau "store_information.store_name"
+	FieldConfidence map[string]Score `json:"field_confidence,omitempty"`
+	// FieldBoundingBoxes berisi lokasi teks sumber field di gambar jika EXTRACTION_BOUNDING_BOXES aktif
+	FieldBoundingBoxes map[string]*BoundingBox `json:"field_bounding_boxes,omitempty"`
+	// LowConfidenceFields berisi path field yang confidence-nya di bawah CONFIDENCE_LOW_THRESHOLD,
+	// misalnya "items[2].price", sebagai target review
+	LowConfidenceFields []string `json:"low_confidence_fields,omitempty" example:"items[2].price,totals.tax.amount"`
+	// Quality berisi skor kualitas foto yang diukur sebelum preprocessing dan ekstraksi
+	Quality *ImageQuality `json:"quality,omitempty"`
+	// PreprocessingSteps mencatat langkah preprocessing yang diterapkan pada gambar sebelum diekstrak
+	PreprocessingSteps []string `json:"preprocessing_steps,omitempty" example:"auto_orient,deskew,contrast"`
+	// Review berisi status review receipt; receipt harus approved (atau tidak perlu direview)
+	// sebelum bisa dikunci
+	Review *ReceiptReview `json:"review,omitempty"`
+	// DuplicateWarning terisi jika struk yang sama kemungkinan besar sudah pernah diunggah
+	DuplicateWarning *DuplicateWarning `json:"duplicate_warning,omitempty"`
+	// Cached bernilai true jika hasil ekstraksi diambil dari cache karena file yang sama pernah diunggah
+	Cached bool `json:"cached,omitempty" example:"false"`
+	// ImageStatus adalah status penyimpanan file sumber di bucket (stored, pending, failed)
+	ImageStatus string `json:"image_status,omitempty" example:"stored"`
+	// StorageError terisi jika upload ke bucket gagal tetapi request tetap dilanjutkan
+	StorageError string `json:"storage_error,omitempty"`
+	// Receipts berisi semua struk yang terdeteksi jika satu foto memuat lebih dari satu struk.
+	// Field lain pada respons ini sama dengan struk pertama.
+	Receipts []SplitbillResponse `json:"receipts,omitempty"`
 }
 
 // Item represents an individual item in the receipt
@@ -14,6 +53,10 @@ type Item struct {
 	Price    string `json:"price" example:"25000.00"`
 	Quantity string `json:"quantity" example:"2"`
 	Total    string `json:"total" example:"50000.00"`
+	// Confidence berisi confidence model untuk baris item dan setiap field-nya
+	Confidence *ItemConfidence `json:"confidence,omitempty"`
+	// BoundingBox adalah lokasi baris item di gambar jika EXTRACTION_BOUNDING_BOXES aktif
+	BoundingBox *BoundingBox `json:"bounding_box,omitempty"`
 }
 
 // StoreInformation represents store details from the receipt
@@ -55,9 +98,41 @@ type TransactionInfo struct {
 type ErrorResponse struct {
 	Data   string `json:"data"`
 	Status string `json:"status" example:"Error uploading image"`
+	Code   string `json:"code,omitempty" example:"not_a_receipt"`
 }
 
 // SuccessResponse represents a success response wrapper
 type SuccessResponse struct {
 	Data SplitbillResponse `json:"data"`
 }
+
+// Pilihan client untuk receipt yang terdeteksi sebagai duplikat
+const (
+	DuplicateActionMerge    = "merge"
+	DuplicateActionKeepBoth = "keep_both"
+)
+
+// DuplicateWarning represents a probable duplicate of an existing receipt
+type DuplicateWarning struct {
+	ReceiptID string   `json:"receipt_id" example:"6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	URL       string   `json:"url" example:"/receipts/6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	Reasons   []string `json:"reasons" example:"same_transaction_id,same_total"`
+	Actions   []string `json:"actions" example:"merge,keep_both"`
+}
+
+// DuplicateResolutionRequest represents the client's choice for a receipt flagged as a duplicate
+type DuplicateResolutionRequest struct {
+	Action string `json:"action" form:"action" example:"merge"`
+}
+
+// TextReceiptRequest represents a request to extract splitbill information from receipt text or an HTML e-receipt
+type TextReceiptRequest struct {
+	Text string `json:"text" form:"text" example:"INDOMARET\nINDOMIE GORENG 2 X 3.500 7.000\nTOTAL 7.000"`
+	HTML string `json:"html" form:"html" example:"<table><tr><td>2x Nasi Goreng</td><td>Rp50.000</td></tr></table>"`
+}
+
+// ReceiptValidation represents the arithmetic validation result of an extracted receipt
+type ReceiptValidation struct {
+	Valid  bool     `json:"valid" example:"true"`
+	Issues []string `json:"issues"`
+}
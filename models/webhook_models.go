@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Jenis event webhook yang dikirim setelah ekstraksi selesai
+const (
+	WebhookEventExtractionSucceeded = "extraction.succeeded"
+	WebhookEventExtractionFailed    = "extraction.failed"
+)
+
+// Status pengiriman webhook
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookRegistration represents the callback URL registered for an API key. Only the SHA-256 of
+// the API key is stored; Secret is returned once when the registration is created.
+type WebhookRegistration struct {
+	ID        string    `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	URL       string    `json:"url" example:"https://api.example.com/splitbill/callback"`
+	Secret    string    `json:"secret,omitempty" example:"whsec_3f2a9c..."`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookRegistrationRequest represents a request to register the callback URL of an API key.
+// A signing secret is generated when Secret is empty.
+type WebhookRegistrationRequest struct {
+	URL    string `json:"url" form:"url" example:"https://api.example.com/splitbill/callback"`
+	Secret string `json:"secret" form:"secret" example:""`
+}
+
+// WebhookTarget is where the result of one extraction is delivered. APIKeyID refers to the
+// WebhookRegistration whose secret signs the delivery; deliveries without one are signed with
+// WEBHOOK_SECRET.
+type WebhookTarget struct {
+	URL      string `json:"url" example:"https://api.example.com/splitbill/callback"`
+	APIKeyID string `json:"api_key_id,omitempty"`
+}
+
+// WebhookEvent represents the body POSTed to the callback URL
+type WebhookEvent struct {
+	ID        string             `json:"id" example:"b7e2c1d4-8a3f-4e6b-9c2d-1f0e3a5b7c9d"`
+	Type      string             `json:"type" example:"extraction.succeeded"`
+	CreatedAt time.Time          `json:"created_at"`
+	JobID     string             `json:"job_id,omitempty" example:"3c1f7a2e-9b4d-4e8a-8f2b-6d5c4b3a2f1e"`
+	ReceiptID string             `json:"receipt_id,omitempty" example:"6f1c2b8e-2a4b-4a8e-9d1f-3c5e7a9b1d2f"`
+	Receipt   *SplitbillResponse `json:"receipt,omitempty"`
+	Error     *JobError          `json:"error,omitempty"`
+}
+
+// WebhookAttempt represents one delivery attempt
+type WebhookAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty" example:"500"`
+	Error      string    `json:"error,omitempty" example:"unexpected status 500"`
+	DurationMs int64     `json:"duration_ms" example:"183"`
+}
+
+// WebhookDelivery represents a webhook delivery with its attempt log
+type WebhookDelivery struct {
+	ID            string           `json:"id" example:"b7e2c1d4-8a3f-4e6b-9c2d-1f0e3a5b7c9d"`
+	Event         string           `json:"event" example:"extraction.succeeded"`
+	Target        WebhookTarget    `json:"target"`
+	JobID         string           `json:"job_id,omitempty"`
+	ReceiptID     string           `json:"receipt_id,omitempty"`
+	Payload       WebhookEvent     `json:"payload"`
+	Status        string           `json:"status" example:"pending"`
+	Attempts      []WebhookAttempt `json:"attempts"`
+	NextAttemptAt *time.Time       `json:"next_attempt_at,omitempty"`
+	MaxAttempts   int              `json:"max_attempts" example:"6"`
+	DeliveredAt   *time.Time       `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
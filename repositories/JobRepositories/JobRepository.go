@@ -0,0 +1,38 @@
+package jobrepositories
+
+import (
+	"sync"
+
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+type JobRepository interface {
+	Save(job *models.Job) error
+	FindByID(id string) (models.Job, error)
+	FindUnfinished() ([]models.Job, error)
+	Claim(id string) (models.Job, bool, error)
+	Release(id string)
+	SaveInput(input models.JobInput) error
+	FindInput(jobID string) (models.JobInput, error)
+	DeleteInput(jobID string) error
+	SaveBatch(batch *models.Batch) error
+	FindBatch(id string) (models.Batch, error)
+}
+
+// JobRepositoryImpl menyimpan job di document store. claimed mencatat job yang sedang dijalankan
+// proses ini (lihat Claim). Klaim hanya berlaku di dalam satu proses, sehingga beberapa instance
+// aplikasi tidak boleh memakai penyimpanan job yang sama.
+type JobRepositoryImpl struct {
+	Store documents.DocumentStoreInterface
+
+	claimMutex sync.Mutex
+	claimed    map[string]bool
+}
+
+func NewJobRepositoryImpl(store documents.DocumentStoreInterface) *JobRepositoryImpl {
+	return &JobRepositoryImpl{
+		Store:   store,
+		claimed: map[string]bool{},
+	}
+}
@@ -0,0 +1,122 @@
+package jobrepositories
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/google/uuid"
+)
+
+const (
+	jobCollection      = "jobs"
+	jobInputCollection = "job_inputs"
+	batchCollection    = "batches"
+)
+
+// Save menyimpan job baru atau memperbarui job yang sudah ada. ID dibuat otomatis jika kosong.
+func (jobRepositoryImpl *JobRepositoryImpl) Save(job *models.Job) error {
+	now := time.Now()
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
+	}
+	job.UpdatedAt = now
+	return jobRepositoryImpl.Store.Save(jobCollection, job.ID, job)
+}
+
+func (jobRepositoryImpl *JobRepositoryImpl) FindByID(id string) (models.Job, error) {
+	var job models.Job
+	err := jobRepositoryImpl.Store.Find(jobCollection, id, &job)
+	return job, err
+}
+
+// FindUnfinished mengembalikan job yang masih queued atau running, diurutkan dari yang paling lama
+func (jobRepositoryImpl *JobRepositoryImpl) FindUnfinished() ([]models.Job, error) {
+	jobs := []models.Job{}
+	err := jobRepositoryImpl.Store.All(jobCollection, func(data []byte) error {
+		var job models.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		if job.Status == models.JobStatusQueued || job.Status == models.JobStatusRunning {
+			jobs = append(jobs, job)
+		}
+		return nil
+	})
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, err
+}
+
+// Claim mengubah job dari queued menjadi running dan menambah Attempts secara atomik, sehingga job
+// yang masuk lewat worker pool dan runBatch sekaligus hanya dijalankan sekali. Job berstatus running
+// hanya bisa diklaim jika tidak sedang dijalankan proses ini, yaitu job yang terhenti karena aplikasi
+// berhenti. Nilai false berarti job sudah selesai atau sedang dijalankan; job yang diklaim harus
+// dilepas dengan Release setelah selesai.
+func (jobRepositoryImpl *JobRepositoryImpl) Claim(id string) (models.Job, bool, error) {
+	jobRepositoryImpl.claimMutex.Lock()
+	defer jobRepositoryImpl.claimMutex.Unlock()
+
+	job, err := jobRepositoryImpl.FindByID(id)
+	if err != nil {
+		return models.Job{}, false, err
+	}
+	if jobRepositoryImpl.claimed[id] || (job.Status != models.JobStatusQueued && job.Status != models.JobStatusRunning) {
+		return job, false, nil
+	}
+	now := time.Now()
+	job.Status, job.Stage, job.Progress = models.JobStatusRunning, models.JobStageQueued, 0
+	job.StartedAt = &now
+	job.Attempts++
+	if err := jobRepositoryImpl.Save(&job); err != nil {
+		return models.Job{}, false, err
+	}
+	jobRepositoryImpl.claimed[id] = true
+	return job, true, nil
+}
+
+// Release melepas klaim job setelah runJob selesai
+func (jobRepositoryImpl *JobRepositoryImpl) Release(id string) {
+	jobRepositoryImpl.claimMutex.Lock()
+	defer jobRepositoryImpl.claimMutex.Unlock()
+	delete(jobRepositoryImpl.claimed, id)
+}
+
+// SaveInput menyimpan file input job terpisah dari job-nya agar GET /jobs/:id tetap ringan
+func (jobRepositoryImpl *JobRepositoryImpl) SaveInput(input models.JobInput) error {
+	return jobRepositoryImpl.Store.Save(jobInputCollection, input.JobID, input)
+}
+
+func (jobRepositoryImpl *JobRepositoryImpl) FindInput(jobID string) (models.JobInput, error) {
+	var input models.JobInput
+	err := jobRepositoryImpl.Store.Find(jobInputCollection, jobID, &input)
+	return input, err
+}
+
+func (jobRepositoryImpl *JobRepositoryImpl) DeleteInput(jobID string) error {
+	return jobRepositoryImpl.Store.Delete(jobInputCollection, jobID)
+}
+
+// SaveBatch menyimpan batch baru atau memperbarui batch yang sudah ada. ID dibuat otomatis jika kosong.
+func (jobRepositoryImpl *JobRepositoryImpl) SaveBatch(batch *models.Batch) error {
+	now := time.Now()
+	if batch.ID == "" {
+		batch.ID = uuid.NewString()
+	}
+	if batch.CreatedAt.IsZero() {
+		batch.CreatedAt = now
+	}
+	batch.UpdatedAt = now
+	return jobRepositoryImpl.Store.Save(batchCollection, batch.ID, batch)
+}
+
+func (jobRepositoryImpl *JobRepositoryImpl) FindBatch(id string) (models.Batch, error) {
+	var batch models.Batch
+	err := jobRepositoryImpl.Store.Find(batchCollection, id, &batch)
+	return batch, err
+}
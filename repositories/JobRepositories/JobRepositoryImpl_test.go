@@ -0,0 +1,105 @@
+package jobrepositories
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func TestClaim(t *testing.T) {
+	repository := NewJobRepositoryImpl(documents.NewFile(t.TempDir()))
+	job := models.Job{Status: models.JobStatusQueued}
+	if err := repository.Save(&job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	claimed, ok, err := repository.Claim(job.ID)
+	if err != nil || !ok {
+		t.Fatalf("Claim() = %v, %v, want claimed", ok, err)
+	}
+	if claimed.Status != models.JobStatusRunning || claimed.Attempts != 1 || claimed.StartedAt == nil {
+		t.Errorf("claimed job = %+v, want running on attempt 1", claimed)
+	}
+	if _, ok, _ := repository.Claim(job.ID); ok {
+		t.Errorf("Claim() of a job this process is running = true")
+	}
+
+	// job running yang dilepas (misalnya setelah restart) bisa diklaim lagi sebagai percobaan baru
+	repository.Release(job.ID)
+	claimed, ok, err = repository.Claim(job.ID)
+	if err != nil || !ok || claimed.Attempts != 2 {
+		t.Fatalf("Claim() after Release = %+v, %v, %v, want attempt 2", claimed, ok, err)
+	}
+	repository.Release(job.ID)
+
+	claimed.Status = models.JobStatusSucceeded
+	if err := repository.Save(&claimed); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, ok, _ := repository.Claim(job.ID); ok {
+		t.Errorf("Claim() of a finished job = true")
+	}
+	if _, _, err := repository.Claim("missing"); err == nil {
+		t.Errorf("Claim(missing) error = nil")
+	}
+}
+
+// TestClaimConcurrent memastikan job yang masuk lewat worker pool dan runBatch bersamaan hanya
+// diklaim sekali
+func TestClaimConcurrent(t *testing.T) {
+	repository := NewJobRepositoryImpl(documents.NewFile(t.TempDir()))
+	job := models.Job{Status: models.JobStatusQueued}
+	if err := repository.Save(&job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	var claims atomic.Int32
+	var wait sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if _, ok, err := repository.Claim(job.ID); err != nil {
+				t.Errorf("Claim() error = %v", err)
+			} else if ok {
+				claims.Add(1)
+			}
+		}()
+	}
+	wait.Wait()
+	if claims.Load() != 1 {
+		t.Errorf("claims = %d, want 1", claims.Load())
+	}
+}
+
+func TestFindUnfinished(t *testing.T) {
+	repository := NewJobRepositoryImpl(documents.NewFile(t.TempDir()))
+	start := time.Now().Add(-time.Hour)
+	statuses := []string{models.JobStatusRunning, models.JobStatusSucceeded, models.JobStatusQueued, models.JobStatusFailed}
+	ids := []string{}
+	for i, status := range statuses {
+		job := models.Job{Status: status, CreatedAt: start.Add(time.Duration(len(statuses)-i) * time.Minute)}
+		if err := repository.Save(&job); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	unfinished, err := repository.FindUnfinished()
+	if err != nil {
+		t.Fatalf("FindUnfinished() error = %v", err)
+	}
+	got := []string{}
+	for _, job := range unfinished {
+		got = append(got, job.ID)
+	}
+	// job queued lebih lama dibuat dibanding job running sehingga muncul lebih dulu
+	if want := []string{ids[2], ids[0]}; !slices.Equal(got, want) {
+		t.Errorf("FindUnfinished() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package receiptrepositories
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+const duplicateIndexCollection = "receipt_duplicate_index"
+
+// duplicateIndexBuilt menandai indeks yang sudah diisi dengan semua receipt lama
+const duplicateIndexBuilt = "built"
+
+// duplicateIndexEntry mencatat receipt yang memiliki satu key indeks duplikat (nomor transaksi
+// atau rentang total, lihat receipts.DuplicateKeys)
+type duplicateIndexEntry struct {
+	Key        string   `json:"key"`
+	ReceiptIDs []string `json:"receipt_ids"`
+}
+
+// FindDuplicateCandidates mengembalikan receipt yang terdaftar di salah satu key indeks duplikat,
+// diurutkan dari yang paling lama dibuat. Key indeks lama dari receipt yang datanya berubah tidak
+// dihapus, jadi pemanggil tetap harus membandingkan setiap kandidat.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) FindDuplicateCandidates(keys []string) ([]models.Receipt, error) {
+	receiptRepositoryImpl.indexOnce.Do(receiptRepositoryImpl.buildIndex)
+
+	receiptIDs, err := receiptRepositoryImpl.indexedReceiptIDs(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []models.Receipt{}
+	for _, receiptID := range receiptIDs {
+		receipt, err := receiptRepositoryImpl.FindByID(receiptID)
+		if errors.Is(err, documents.ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, receipt)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+	return candidates, nil
+}
+
+// indexedReceiptIDs membaca ID receipt dari setiap key di bawah read lock indeks, sehingga tidak
+// bertabrakan dengan Save atau Delete yang sedang mengubah entry yang sama
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) indexedReceiptIDs(keys []string) ([]string, error) {
+	receiptRepositoryImpl.indexMutex.RLock()
+	defer receiptRepositoryImpl.indexMutex.RUnlock()
+
+	receiptIDs := []string{}
+	for _, key := range keys {
+		entry, err := receiptRepositoryImpl.findIndex(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, receiptID := range entry.ReceiptIDs {
+			if !slices.Contains(receiptIDs, receiptID) {
+				receiptIDs = append(receiptIDs, receiptID)
+			}
+		}
+	}
+	return receiptIDs, nil
+}
+
+// buildIndex mengisi indeks dengan receipt yang disimpan sebelum indeks ada. Ini hanya berjalan
+// sekali per penyimpanan; setelahnya indeks diperbarui oleh Save dan Delete.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) buildIndex() {
+	var built duplicateIndexEntry
+	if err := receiptRepositoryImpl.Store.Find(duplicateIndexCollection, duplicateIndexBuilt, &built); err == nil {
+		return
+	}
+	all, err := receiptRepositoryImpl.FindAll()
+	if err != nil {
+		config.GeneralLogger.Printf("Failed to build receipt duplicate index: %v\n", err.Error())
+		return
+	}
+	for _, receipt := range all {
+		receiptRepositoryImpl.index(receipt.ID, receipts.DuplicateKeys(receipt.Data))
+	}
+	if err := receiptRepositoryImpl.Store.Save(duplicateIndexCollection, duplicateIndexBuilt, duplicateIndexEntry{Key: duplicateIndexBuilt}); err != nil {
+		config.GeneralLogger.Printf("Failed to save receipt duplicate index: %v\n", err.Error())
+	}
+	config.GeneralLogger.Printf("Receipt duplicate index built from %d receipt(s)\n", len(all))
+}
+
+// index menambahkan receipt ke setiap key. Kegagalan hanya dicatat karena receipt sudah tersimpan;
+// receipt yang tidak terindeks hanya tidak ikut dicek sebagai duplikat.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) index(receiptID string, keys []string) {
+	receiptRepositoryImpl.updateIndex(keys, func(entry *duplicateIndexEntry) bool {
+		if slices.Contains(entry.ReceiptIDs, receiptID) {
+			return false
+		}
+		entry.ReceiptIDs = append(entry.ReceiptIDs, receiptID)
+		return true
+	})
+}
+
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) unindex(receiptID string, keys []string) {
+	receiptRepositoryImpl.updateIndex(keys, func(entry *duplicateIndexEntry) bool {
+		index := slices.Index(entry.ReceiptIDs, receiptID)
+		if index < 0 {
+			return false
+		}
+		entry.ReceiptIDs = slices.Delete(entry.ReceiptIDs, index, index+1)
+		return true
+	})
+}
+
+// updateIndex mengubah entry setiap key dan menyimpannya jika change mengembalikan true
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) updateIndex(keys []string, change func(entry *duplicateIndexEntry) bool) {
+	receiptRepositoryImpl.indexMutex.Lock()
+	defer receiptRepositoryImpl.indexMutex.Unlock()
+	for _, key := range keys {
+		entry, err := receiptRepositoryImpl.findIndex(key)
+		if err != nil {
+			config.GeneralLogger.Printf("Failed to read receipt duplicate index: %v\n", err.Error())
+			continue
+		}
+		if !change(&entry) {
+			continue
+		}
+		if err := receiptRepositoryImpl.Store.Save(duplicateIndexCollection, indexID(key), entry); err != nil {
+			config.GeneralLogger.Printf("Failed to update receipt duplicate index: %v\n", err.Error())
+		}
+	}
+}
+
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) findIndex(key string) (duplicateIndexEntry, error) {
+	entry := duplicateIndexEntry{Key: key, ReceiptIDs: []string{}}
+	err := receiptRepositoryImpl.Store.Find(duplicateIndexCollection, indexID(key), &entry)
+	if errors.Is(err, documents.ErrDocumentNotFound) {
+		return entry, nil
+	}
+	return entry, err
+}
+
+// indexID mengubah key menjadi ID dokumen yang aman dipakai sebagai nama file
+func indexID(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:16])
+}
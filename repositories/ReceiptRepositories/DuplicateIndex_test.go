@@ -0,0 +1,87 @@
+package receiptrepositories
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+func duplicateCandidate(transactionID string, total string) models.Receipt {
+	var receipt models.Receipt
+	receipt.Data.StoreInformation.StoreName = "Toko Maju Jaya"
+	receipt.Data.TransactionInfo.TransactionID = transactionID
+	receipt.Data.Totals.Total = total
+	return receipt
+}
+
+func TestFindDuplicateCandidates(t *testing.T) {
+	repository := newTestRepository(t, t.TempDir())
+	existing := duplicateCandidate("TRX-0001", "61050")
+	if err := repository.Save(&existing); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	other := duplicateCandidate("TRX-0002", "15000")
+	if err := repository.Save(&other); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	candidates, err := repository.FindDuplicateCandidates(receipts.DuplicateCandidateKeys(duplicateCandidate("trx 0001", "61100").Data))
+	if err != nil {
+		t.Fatalf("FindDuplicateCandidates() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != existing.ID {
+		t.Fatalf("candidates = %v, want only %s", candidates, existing.ID)
+	}
+
+	if err := repository.Delete(existing.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	candidates, err = repository.FindDuplicateCandidates(receipts.DuplicateCandidateKeys(existing.Data))
+	if err != nil {
+		t.Fatalf("FindDuplicateCandidates() error = %v", err)
+	}
+	if len(candidates) != 0 {
+		t.Errorf("candidates after delete = %v, want none", candidates)
+	}
+}
+
+// TestFindDuplicateCandidatesConcurrent membaca indeks duplikat sambil receipt lain disimpan. Jalankan
+// dengan -race.
+func TestFindDuplicateCandidatesConcurrent(t *testing.T) {
+	repository := newTestRepository(t, t.TempDir())
+	keys := receipts.DuplicateCandidateKeys(duplicateCandidate("", "61050").Data)
+
+	var wait sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wait.Add(2)
+		go func() {
+			defer wait.Done()
+			for j := 0; j < 10; j++ {
+				receipt := duplicateCandidate(fmt.Sprintf("TRX-%d-%d", i, j), "61050")
+				if err := repository.Save(&receipt); err != nil {
+					t.Errorf("Save() error = %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wait.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := repository.FindDuplicateCandidates(keys); err != nil {
+					t.Errorf("FindDuplicateCandidates() error = %v", err)
+				}
+			}
+		}()
+	}
+	wait.Wait()
+
+	candidates, err := repository.FindDuplicateCandidates(keys)
+	if err != nil {
+		t.Fatalf("FindDuplicateCandidates() error = %v", err)
+	}
+	if len(candidates) != 40 {
+		t.Errorf("candidates = %d, want 40", len(candidates))
+	}
+}
@@ -0,0 +1,49 @@
+package receiptrepositories
+
+import (
+	"errors"
+	"sync"
+
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+type ReceiptRepository interface {
+	Save(receipt *models.Receipt) error
+	FindByID(id string) (models.Receipt, error)
+	FindAll() ([]models.Receipt, error)
+	FindDuplicateCandidates(keys []string) ([]models.Receipt, error)
+	FindReviews(status string, offset int, limit int) ([]models.Receipt, int, error)
+	Update(id string, change func(receipt *models.Receipt) error) (models.Receipt, error)
+	Delete(id string) error
+}
+
+// ErrUnchanged dikembalikan fungsi change di Update jika receipt tidak perlu disimpan
+var ErrUnchanged = errors.New("receipt unchanged")
+
+// ReceiptRepositoryImpl menyimpan receipt di document store beserta indeks duplikat dan indeks status
+// review-nya. Perubahan receipt yang sudah ada dilakukan lewat Update, yang membaca dan menyimpan
+// receipt di bawah kunci per receipt sehingga dua perubahan tidak saling menimpa.
+type ReceiptRepositoryImpl struct {
+	Store            documents.DocumentStoreInterface
+	indexMutex       sync.RWMutex
+	indexOnce        sync.Once
+	reviewIndexMutex sync.Mutex
+	reviewIndexOnce  sync.Once
+	locksMutex       sync.Mutex
+	locks            map[string]*receiptLock
+}
+
+// receiptLock adalah kunci satu receipt; waiters menghitung pemakainya supaya kunci dihapus dari
+// map setelah tidak dipakai lagi
+type receiptLock struct {
+	sync.Mutex
+	waiters int
+}
+
+func NewReceiptRepositoryImpl(store documents.DocumentStoreInterface) *ReceiptRepositoryImpl {
+	return &ReceiptRepositoryImpl{
+		Store: store,
+		locks: map[string]*receiptLock{},
+	}
+}
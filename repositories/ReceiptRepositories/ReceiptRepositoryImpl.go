@@ -0,0 +1,120 @@
+package receiptrepositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/google/uuid"
+)
+
+const receiptCollection = "receipts"
+
+// Save menyimpan receipt baru atau memperbarui receipt yang sudah ada. ID dibuat otomatis jika kosong.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) Save(receipt *models.Receipt) error {
+	now := time.Now()
+	previousReview := ""
+	if receipt.ID == "" {
+		receipt.ID = uuid.NewString()
+	} else if previous, err := receiptRepositoryImpl.FindByID(receipt.ID); err == nil {
+		previousReview = reviewIndexStatus(previous)
+	}
+	if receipt.CreatedAt.IsZero() {
+		receipt.CreatedAt = now
+	}
+	receipt.UpdatedAt = now
+	receipt.Data.ReceiptID = receipt.ID
+	if err := receiptRepositoryImpl.Store.Save(receiptCollection, receipt.ID, receipt); err != nil {
+		return err
+	}
+	receiptRepositoryImpl.index(receipt.ID, receipts.DuplicateKeys(receipt.Data))
+	receiptRepositoryImpl.reindexReview(*receipt, previousReview)
+	return nil
+}
+
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) FindByID(id string) (models.Receipt, error) {
+	var receipt models.Receipt
+	err := receiptRepositoryImpl.Store.Find(receiptCollection, id, &receipt)
+	return receipt, err
+}
+
+// FindAll mengembalikan semua receipt, diurutkan dari yang paling lama dibuat
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) FindAll() ([]models.Receipt, error) {
+	receipts := []models.Receipt{}
+	err := receiptRepositoryImpl.Store.All(receiptCollection, func(data []byte) error {
+		var receipt models.Receipt
+		if err := json.Unmarshal(data, &receipt); err != nil {
+			return err
+		}
+		receipts = append(receipts, receipt)
+		return nil
+	})
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i].CreatedAt.Before(receipts[j].CreatedAt)
+	})
+	return receipts, err
+}
+
+// Update membaca receipt terbaru, menjalankan change lalu menyimpannya di bawah kunci receipt
+// tersebut. Error dari change dikembalikan apa adanya tanpa menyimpan; ErrUnchanged mengembalikan
+// receipt terbaru tanpa menyimpannya.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) Update(id string, change func(receipt *models.Receipt) error) (models.Receipt, error) {
+	unlock := receiptRepositoryImpl.lock(id)
+	defer unlock()
+
+	receipt, err := receiptRepositoryImpl.FindByID(id)
+	if err != nil {
+		return models.Receipt{}, fmt.Errorf("Error retrieving receipt: %w", err)
+	}
+	if err := change(&receipt); errors.Is(err, ErrUnchanged) {
+		return receipt, nil
+	} else if err != nil {
+		return models.Receipt{}, err
+	}
+	if err := receiptRepositoryImpl.Save(&receipt); err != nil {
+		return models.Receipt{}, fmt.Errorf("Error saving receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) Delete(id string) error {
+	unlock := receiptRepositoryImpl.lock(id)
+	defer unlock()
+
+	receipt, err := receiptRepositoryImpl.FindByID(id)
+	if err := receiptRepositoryImpl.Store.Delete(receiptCollection, id); err != nil {
+		return err
+	}
+	if err == nil {
+		receiptRepositoryImpl.unindex(id, receipts.DuplicateKeys(receipt.Data))
+		receiptRepositoryImpl.reindexReview(models.Receipt{ID: id}, reviewIndexStatus(receipt))
+	}
+	return nil
+}
+
+// lock mengambil kunci receipt dan mengembalikan fungsi untuk melepasnya
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) lock(id string) func() {
+	receiptRepositoryImpl.locksMutex.Lock()
+	lock, ok := receiptRepositoryImpl.locks[id]
+	if !ok {
+		lock = &receiptLock{}
+		receiptRepositoryImpl.locks[id] = lock
+	}
+	lock.waiters++
+	receiptRepositoryImpl.locksMutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		receiptRepositoryImpl.locksMutex.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(receiptRepositoryImpl.locks, id)
+		}
+		receiptRepositoryImpl.locksMutex.Unlock()
+	}
+}
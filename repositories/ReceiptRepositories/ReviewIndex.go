@@ -0,0 +1,157 @@
+package receiptrepositories
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+const reviewIndexCollection = "receipt_review_index"
+
+// reviewIndexBuilt menandai indeks review yang sudah diisi dengan semua receipt lama
+const reviewIndexBuilt = "built"
+
+// reviewIndexEntry mencatat receipt di antrean review yang memiliki satu status review. CreatedAt
+// disimpan di indeks agar daftar bisa diurutkan dan dipotong per halaman tanpa membaca receipt-nya.
+type reviewIndexEntry struct {
+	Status   string            `json:"status"`
+	Receipts []reviewIndexItem `json:"receipts"`
+}
+
+type reviewIndexItem struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// reviewStatuses adalah status receipt yang pernah masuk antrean review, yaitu isi status "all"
+var reviewStatuses = []string{models.ReviewStatusPendingReview, models.ReviewStatusApproved, models.ReviewStatusRejected}
+
+// FindReviews mengembalikan receipt di antrean review dengan status tertentu ("all" untuk semua
+// status), diurutkan dari yang paling lama dibuat, mulai dari offset sebanyak paling banyak limit
+// receipt, beserta jumlah seluruh receipt dengan status tersebut.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) FindReviews(status string, offset int, limit int) ([]models.Receipt, int, error) {
+	receiptRepositoryImpl.reviewIndexOnce.Do(receiptRepositoryImpl.buildReviewIndex)
+
+	statuses := []string{status}
+	if status == "all" {
+		statuses = reviewStatuses
+	}
+	items := []reviewIndexItem{}
+	receiptRepositoryImpl.reviewIndexMutex.Lock()
+	for _, status := range statuses {
+		entry, err := receiptRepositoryImpl.findReviewIndex(status)
+		if err != nil {
+			receiptRepositoryImpl.reviewIndexMutex.Unlock()
+			return nil, 0, err
+		}
+		items = append(items, entry.Receipts...)
+	}
+	receiptRepositoryImpl.reviewIndexMutex.Unlock()
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+
+	page := []models.Receipt{}
+	for i := offset; i >= 0 && i < len(items) && len(page) < limit; i++ {
+		receipt, err := receiptRepositoryImpl.FindByID(items[i].ID)
+		if errors.Is(err, documents.ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		page = append(page, receipt)
+	}
+	return page, len(items), nil
+}
+
+// buildReviewIndex mengisi indeks review dengan receipt yang disimpan sebelum indeks ada. Ini hanya
+// berjalan sekali per penyimpanan; setelahnya indeks diperbarui oleh Save dan Delete.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) buildReviewIndex() {
+	var built reviewIndexEntry
+	if err := receiptRepositoryImpl.Store.Find(reviewIndexCollection, reviewIndexBuilt, &built); err == nil {
+		return
+	}
+	all, err := receiptRepositoryImpl.FindAll()
+	if err != nil {
+		config.GeneralLogger.Printf("Failed to build receipt review index: %v\n", err.Error())
+		return
+	}
+	for _, receipt := range all {
+		receiptRepositoryImpl.reindexReview(receipt, "")
+	}
+	if err := receiptRepositoryImpl.Store.Save(reviewIndexCollection, reviewIndexBuilt, reviewIndexEntry{Status: reviewIndexBuilt}); err != nil {
+		config.GeneralLogger.Printf("Failed to save receipt review index: %v\n", err.Error())
+	}
+	config.GeneralLogger.Printf("Receipt review index built from %d receipt(s)\n", len(all))
+}
+
+// reindexReview memindahkan receipt dari status review sebelumnya ke status review saat ini.
+// Kegagalan hanya dicatat karena receipt sudah tersimpan.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) reindexReview(receipt models.Receipt, previous string) {
+	current := reviewIndexStatus(receipt)
+	if current == previous {
+		return
+	}
+	receiptRepositoryImpl.reviewIndexMutex.Lock()
+	defer receiptRepositoryImpl.reviewIndexMutex.Unlock()
+	if previous != "" {
+		receiptRepositoryImpl.updateReviewIndex(previous, func(entry *reviewIndexEntry) bool {
+			index := slices.IndexFunc(entry.Receipts, func(item reviewIndexItem) bool { return item.ID == receipt.ID })
+			if index < 0 {
+				return false
+			}
+			entry.Receipts = slices.Delete(entry.Receipts, index, index+1)
+			return true
+		})
+	}
+	if current != "" {
+		receiptRepositoryImpl.updateReviewIndex(current, func(entry *reviewIndexEntry) bool {
+			if slices.ContainsFunc(entry.Receipts, func(item reviewIndexItem) bool { return item.ID == receipt.ID }) {
+				return false
+			}
+			entry.Receipts = append(entry.Receipts, reviewIndexItem{ID: receipt.ID, CreatedAt: receipt.CreatedAt})
+			return true
+		})
+	}
+}
+
+// updateReviewIndex mengubah entry satu status dan menyimpannya jika change mengembalikan true.
+// Pemanggil harus memegang reviewIndexMutex.
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) updateReviewIndex(status string, change func(entry *reviewIndexEntry) bool) {
+	entry, err := receiptRepositoryImpl.findReviewIndex(status)
+	if err != nil {
+		config.GeneralLogger.Printf("Failed to read receipt review index: %v\n", err.Error())
+		return
+	}
+	if !change(&entry) {
+		return
+	}
+	if err := receiptRepositoryImpl.Store.Save(reviewIndexCollection, status, entry); err != nil {
+		config.GeneralLogger.Printf("Failed to update receipt review index: %v\n", err.Error())
+	}
+}
+
+func (receiptRepositoryImpl *ReceiptRepositoryImpl) findReviewIndex(status string) (reviewIndexEntry, error) {
+	entry := reviewIndexEntry{Status: status, Receipts: []reviewIndexItem{}}
+	err := receiptRepositoryImpl.Store.Find(reviewIndexCollection, status, &entry)
+	if errors.Is(err, documents.ErrDocumentNotFound) {
+		return entry, nil
+	}
+	return entry, err
+}
+
+// reviewIndexStatus mengembalikan status review receipt yang masuk antrean review, atau string
+// kosong untuk receipt yang tidak pernah ditandai
+func reviewIndexStatus(receipt models.Receipt) string {
+	review := receipt.Data.Review
+	if review == nil || len(review.Reasons) == 0 {
+		return ""
+	}
+	return review.Status
+}
@@ -0,0 +1,128 @@
+package receiptrepositories
+
+import (
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestRepository(t *testing.T, dir string) *ReceiptRepositoryImpl {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+	return NewReceiptRepositoryImpl(documents.NewFile(dir))
+}
+
+func reviewReceipt(t *testing.T, repository *ReceiptRepositoryImpl, created time.Time, status string) string {
+	t.Helper()
+	receipt := models.Receipt{CreatedAt: created}
+	if status != "" {
+		receipt.Data.Review = &models.ReceiptReview{Status: status, Reasons: []string{models.ReviewReasonValidationFailed}}
+	}
+	if err := repository.Save(&receipt); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return receipt.ID
+}
+
+func reviewIDs(t *testing.T, repository *ReceiptRepositoryImpl, status string, offset int, limit int) ([]string, int) {
+	t.Helper()
+	page, total, err := repository.FindReviews(status, offset, limit)
+	if err != nil {
+		t.Fatalf("FindReviews(%q) error = %v", status, err)
+	}
+	ids := []string{}
+	for _, receipt := range page {
+		ids = append(ids, receipt.ID)
+	}
+	return ids, total
+}
+
+func TestFindReviews(t *testing.T) {
+	repository := newTestRepository(t, t.TempDir())
+	start := time.Date(2025, 8, 12, 19, 0, 0, 0, time.UTC)
+	// disimpan tidak berurutan agar urutan hasil berasal dari CreatedAt
+	third := reviewReceipt(t, repository, start.Add(3*time.Minute), models.ReviewStatusPendingReview)
+	first := reviewReceipt(t, repository, start.Add(time.Minute), models.ReviewStatusPendingReview)
+	approved := reviewReceipt(t, repository, start.Add(2*time.Minute), models.ReviewStatusApproved)
+	reviewReceipt(t, repository, start, "")
+
+	tests := []struct {
+		name   string
+		status string
+		offset int
+		limit  int
+		ids    []string
+		total  int
+	}{
+		{name: "pending oldest first", status: models.ReviewStatusPendingReview, limit: 10, ids: []string{first, third}, total: 2},
+		{name: "all statuses", status: "all", limit: 10, ids: []string{first, approved, third}, total: 3},
+		{name: "first page", status: "all", limit: 2, ids: []string{first, approved}, total: 3},
+		{name: "second page", status: "all", offset: 2, limit: 2, ids: []string{third}, total: 3},
+		{name: "past the end", status: "all", offset: 5, limit: 2, ids: []string{}, total: 3},
+		{name: "no receipts", status: models.ReviewStatusRejected, limit: 10, ids: []string{}, total: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids, total := reviewIDs(t, repository, test.status, test.offset, test.limit)
+			if !slices.Equal(ids, test.ids) {
+				t.Errorf("ids = %v, want %v", ids, test.ids)
+			}
+			if total != test.total {
+				t.Errorf("total = %d, want %d", total, test.total)
+			}
+		})
+	}
+}
+
+// TestFindReviewsFollowsStatus memastikan receipt pindah status di indeks saat direview dan keluar
+// dari indeks saat dihapus
+func TestFindReviewsFollowsStatus(t *testing.T) {
+	repository := newTestRepository(t, t.TempDir())
+	id := reviewReceipt(t, repository, time.Now(), models.ReviewStatusPendingReview)
+
+	_, err := repository.Update(id, func(receipt *models.Receipt) error {
+		receipt.Data.Review.Status = models.ReviewStatusRejected
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if ids, _ := reviewIDs(t, repository, models.ReviewStatusPendingReview, 0, 10); len(ids) != 0 {
+		t.Errorf("pending after reject = %v, want none", ids)
+	}
+	if ids, _ := reviewIDs(t, repository, models.ReviewStatusRejected, 0, 10); !slices.Equal(ids, []string{id}) {
+		t.Errorf("rejected = %v, want [%s]", ids, id)
+	}
+
+	if err := repository.Delete(id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if ids, total := reviewIDs(t, repository, "all", 0, 10); len(ids) != 0 || total != 0 {
+		t.Errorf("all after delete = %v (total %d), want none", ids, total)
+	}
+}
+
+// TestFindReviewsBuildsIndex memastikan receipt yang disimpan sebelum indeks review ada tetap muncul
+func TestFindReviewsBuildsIndex(t *testing.T) {
+	dir := t.TempDir()
+	store := documents.NewFile(dir)
+	old := models.Receipt{ID: "old-receipt", CreatedAt: time.Now()}
+	old.Data.Review = &models.ReceiptReview{Status: models.ReviewStatusPendingReview, Reasons: []string{models.ReviewReasonLowConfidence}}
+	if err := store.Save(receiptCollection, old.ID, old); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	repository := newTestRepository(t, dir)
+	if ids, _ := reviewIDs(t, repository, models.ReviewStatusPendingReview, 0, 10); !slices.Equal(ids, []string{old.ID}) {
+		t.Errorf("pending = %v, want [%s]", ids, old.ID)
+	}
+}
@@ -0,0 +1,28 @@
+package webhookrepositories
+
+import (
+	"time"
+
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+type WebhookRepository interface {
+	SaveRegistration(registration *models.WebhookRegistration) error
+	FindRegistration(id string) (models.WebhookRegistration, error)
+	DeleteRegistration(id string) error
+	SaveDelivery(delivery *models.WebhookDelivery) error
+	FindDelivery(id string) (models.WebhookDelivery, error)
+	FindDeliveries() ([]models.WebhookDelivery, error)
+	FindDueDeliveries(now time.Time) ([]models.WebhookDelivery, error)
+}
+
+type WebhookRepositoryImpl struct {
+	Store documents.DocumentStoreInterface
+}
+
+func NewWebhookRepositoryImpl(store documents.DocumentStoreInterface) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{
+		Store: store,
+	}
+}
@@ -0,0 +1,90 @@
+package webhookrepositories
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/google/uuid"
+)
+
+const (
+	webhookCollection         = "webhooks"
+	webhookDeliveryCollection = "webhook_deliveries"
+)
+
+func (webhookRepositoryImpl *WebhookRepositoryImpl) SaveRegistration(registration *models.WebhookRegistration) error {
+	now := time.Now()
+	if registration.CreatedAt.IsZero() {
+		registration.CreatedAt = now
+	}
+	registration.UpdatedAt = now
+	return webhookRepositoryImpl.Store.Save(webhookCollection, registration.ID, registration)
+}
+
+func (webhookRepositoryImpl *WebhookRepositoryImpl) FindRegistration(id string) (models.WebhookRegistration, error) {
+	var registration models.WebhookRegistration
+	err := webhookRepositoryImpl.Store.Find(webhookCollection, id, &registration)
+	return registration, err
+}
+
+func (webhookRepositoryImpl *WebhookRepositoryImpl) DeleteRegistration(id string) error {
+	return webhookRepositoryImpl.Store.Delete(webhookCollection, id)
+}
+
+// SaveDelivery menyimpan delivery baru atau memperbarui delivery yang sudah ada. ID dibuat otomatis
+// jika kosong.
+func (webhookRepositoryImpl *WebhookRepositoryImpl) SaveDelivery(delivery *models.WebhookDelivery) error {
+	now := time.Now()
+	if delivery.ID == "" {
+		delivery.ID = uuid.NewString()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = now
+	}
+	delivery.UpdatedAt = now
+	return webhookRepositoryImpl.Store.Save(webhookDeliveryCollection, delivery.ID, delivery)
+}
+
+func (webhookRepositoryImpl *WebhookRepositoryImpl) FindDelivery(id string) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := webhookRepositoryImpl.Store.Find(webhookDeliveryCollection, id, &delivery)
+	return delivery, err
+}
+
+// FindDeliveries mengembalikan semua delivery, diurutkan dari yang terbaru
+func (webhookRepositoryImpl *WebhookRepositoryImpl) FindDeliveries() ([]models.WebhookDelivery, error) {
+	deliveries, err := webhookRepositoryImpl.findDeliveries(func(delivery models.WebhookDelivery) bool { return true })
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	return deliveries, err
+}
+
+// FindDueDeliveries mengembalikan delivery pending yang jadwal percobaan berikutnya sudah lewat,
+// diurutkan dari yang paling lama
+func (webhookRepositoryImpl *WebhookRepositoryImpl) FindDueDeliveries(now time.Time) ([]models.WebhookDelivery, error) {
+	deliveries, err := webhookRepositoryImpl.findDeliveries(func(delivery models.WebhookDelivery) bool {
+		return delivery.Status == models.WebhookDeliveryPending && (delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.After(now))
+	})
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, err
+}
+
+func (webhookRepositoryImpl *WebhookRepositoryImpl) findDeliveries(match func(delivery models.WebhookDelivery) bool) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	err := webhookRepositoryImpl.Store.All(webhookDeliveryCollection, func(data []byte) error {
+		var delivery models.WebhookDelivery
+		if err := json.Unmarshal(data, &delivery); err != nil {
+			return err
+		}
+		if match(delivery) {
+			deliveries = append(deliveries, delivery)
+		}
+		return nil
+	})
+	return deliveries, err
+}
@@ -9,4 +9,25 @@ func Router(app *fiber.App) {
 	allController := injector.InitializeController()
 
 	app.Post("/", allController.SplitbilController.Splitbil)
+	app.Post("/text", allController.SplitbilController.SplitbilText)
+	app.Get("/receipts/:id", allController.SplitbilController.GetReceipt)
+	app.Post("/receipts/:id/duplicate", allController.SplitbilController.ResolveDuplicate)
+	app.Post("/receipts/:id/lock", allController.SplitbilController.LockReceipt)
+	app.Get("/jobs/:id", allController.SplitbilController.GetJob)
+	app.Post("/batches", allController.SplitbilController.SubmitBatch)
+	app.Get("/batches/:id", allController.SplitbilController.GetBatch)
+	app.Get("/batches/:id/csv", allController.SplitbilController.BatchCSV)
+
+	app.Get("/reviews", allController.ReviewController.ListReviews)
+	app.Get("/reviews/:id", allController.ReviewController.GetReview)
+	app.Post("/reviews/:id", allController.ReviewController.Review)
+
+	app.Put("/webhooks", allController.WebhookController.Register)
+	app.Get("/webhooks", allController.WebhookController.GetRegistration)
+	app.Delete("/webhooks", allController.WebhookController.DeleteRegistration)
+	app.Get("/webhooks/deliveries", allController.WebhookController.ListDeliveries)
+	app.Get("/webhooks/deliveries/:id", allController.WebhookController.GetDelivery)
+	app.Post("/webhooks/deliveries/:id/redeliver", allController.WebhookController.Redeliver)
+
+	app.Get("/metrics", allController.MetricsController.Metrics)
 }
@@ -0,0 +1,23 @@
+package metricsservices
+
+import (
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+type MetricsService interface {
+	Metrics() models.Metrics
+}
+
+type MetricsServiceImpl struct {
+	Limiter    limits.LimiterInterface
+	GeminiKeys *extractors.GeminiKeyPool
+}
+
+func NewMetricsServiceImpl(limiter limits.LimiterInterface, geminiKeys *extractors.GeminiKeyPool) *MetricsServiceImpl {
+	return &MetricsServiceImpl{
+		Limiter:    limiter,
+		GeminiKeys: geminiKeys,
+	}
+}
@@ -0,0 +1,12 @@
+package metricsservices
+
+import "github.com/arifin2018/splitbill-arifin.git/models"
+
+// Metrics mengembalikan kedalaman antrean, slot terpakai dan waktu tunggu panggilan model, serta
+// penghitung pemakaian setiap Gemini API key
+func (metricsServiceImpl *MetricsServiceImpl) Metrics() models.Metrics {
+	return models.Metrics{
+		Extraction: metricsServiceImpl.Limiter.Metrics(),
+		GeminiKeys: metricsServiceImpl.GeminiKeys.Metrics(),
+	}
+}
@@ -0,0 +1,23 @@
+package reviewservices
+
+import (
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewService interface {
+	ListReviews(app *fiber.Ctx) (models.ReviewQueuePage, error)
+	GetReview(app *fiber.Ctx) (models.ReviewQueueItem, error)
+	Review(app *fiber.Ctx) (models.Receipt, error)
+}
+
+type ReviewServiceImpl struct {
+	ReceiptRepository receiptrepositories.ReceiptRepository
+}
+
+func NewReviewServiceImpl(receiptRepository receiptrepositories.ReceiptRepository) *ReviewServiceImpl {
+	return &ReviewServiceImpl{
+		ReceiptRepository: receiptRepository,
+	}
+}
@@ -0,0 +1,133 @@
+package reviewservices
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reviewPageLimit adalah jumlah receipt per halaman antrean review jika query limit kosong,
+// maxReviewPageLimit batas atasnya
+const (
+	reviewPageLimit    = 50
+	maxReviewPageLimit = 200
+)
+
+// ListReviews mengembalikan satu halaman receipt di antrean review, diurutkan dari yang paling lama
+// masuk. Query status memilih status yang ditampilkan (default pending_review, "all" untuk semua
+// receipt yang pernah ditandai); limit dan offset memilih halamannya. Receipt dibaca dari indeks
+// status review di repository, bukan dari seluruh receipt.
+func (reviewServiceImpl *ReviewServiceImpl) ListReviews(app *fiber.Ctx) (models.ReviewQueuePage, error) {
+	status := strings.ToLower(strings.TrimSpace(app.Query("status", models.ReviewStatusPendingReview)))
+	switch status {
+	case "all", models.ReviewStatusPendingReview, models.ReviewStatusApproved, models.ReviewStatusRejected:
+	default:
+		return models.ReviewQueuePage{}, errors.New(fmt.Sprintf("unknown review status %q", status))
+	}
+	limit := app.QueryInt("limit", reviewPageLimit)
+	if limit <= 0 || limit > maxReviewPageLimit {
+		return models.ReviewQueuePage{}, errors.New(fmt.Sprintf("limit must be between 1 and %d", maxReviewPageLimit))
+	}
+	offset := app.QueryInt("offset", 0)
+	if offset < 0 {
+		return models.ReviewQueuePage{}, errors.New("offset must not be negative")
+	}
+
+	records, total, err := reviewServiceImpl.ReceiptRepository.FindReviews(status, offset, limit)
+	if err != nil {
+		return models.ReviewQueuePage{}, errors.New(fmt.Sprintf("Error retrieving receipts: %v", err.Error()))
+	}
+	page := models.ReviewQueuePage{Reviews: []models.ReviewQueueItem{}, Total: total, Limit: limit, Offset: offset}
+	for _, record := range records {
+		page.Reviews = append(page.Reviews, queueItem(record))
+	}
+	return page, nil
+}
+
+func (reviewServiceImpl *ReviewServiceImpl) GetReview(app *fiber.Ctx) (models.ReviewQueueItem, error) {
+	record, err := reviewServiceImpl.ReceiptRepository.FindByID(app.Params("id"))
+	if err != nil {
+		return models.ReviewQueueItem{}, errors.New(fmt.Sprintf("Error retrieving receipt: %v", err.Error()))
+	}
+	if record.Data.Review == nil || len(record.Data.Review.Reasons) == 0 {
+		return models.ReviewQueueItem{}, errors.New("receipt is not in the review queue")
+	}
+	return queueItem(record), nil
+}
+
+// Review menjalankan keputusan reviewer untuk receipt berstatus pending_review. "approve" menyetujui
+// hasil ekstraksi apa adanya, "correct" mengganti isi struk dengan koreksi reviewer lalu
+// menyetujuinya, dan "reject" menolak receipt sehingga bill-nya tidak bisa dikunci.
+func (reviewServiceImpl *ReviewServiceImpl) Review(app *fiber.Ctx) (models.Receipt, error) {
+	var request models.ReviewRequest
+	if err := app.BodyParser(&request); err != nil {
+		config.GeneralLogger.Printf("Error parsing review request: %v\n", err.Error())
+		return models.Receipt{}, errors.New(fmt.Sprintf("Error parsing request: %v", err.Error()))
+	}
+
+	record, err := reviewServiceImpl.ReceiptRepository.Update(app.Params("id"), func(record *models.Receipt) error {
+		if record.LockedAt != nil {
+			return helpers.NewApiError(fiber.StatusConflict, helpers.ErrCodeReceiptLocked, "receipt is locked", nil)
+		}
+		review := record.Data.Review
+		if review == nil || review.Status != models.ReviewStatusPendingReview {
+			return helpers.NewApiError(fiber.StatusConflict, helpers.ErrCodeReviewNotPending, "receipt is not pending review", review)
+		}
+
+		decided := *review
+		switch request.Action {
+		case models.ReviewActionApprove:
+			decided.Status = models.ReviewStatusApproved
+		case models.ReviewActionCorrect:
+			if request.Data == nil {
+				return errors.New("corrected data is required for action \"correct\"")
+			}
+			record.Data = receipts.ApplyCorrection(record.Data, *request.Data)
+			decided.Status = models.ReviewStatusApproved
+			decided.Corrected = true
+		case models.ReviewActionReject:
+			decided.Status = models.ReviewStatusRejected
+		default:
+			return errors.New(fmt.Sprintf("unknown action %q, use %q, %q or %q", request.Action, models.ReviewActionApprove, models.ReviewActionCorrect, models.ReviewActionReject))
+		}
+		now := time.Now()
+		decided.Reviewer = request.Reviewer
+		decided.Note = request.Note
+		decided.ReviewedAt = &now
+		record.Data.Review = &decided
+		return nil
+	})
+	if err != nil {
+		return models.Receipt{}, err
+	}
+	config.GeneralLogger.Printf("Receipt %s reviewed: %s\n", record.ID, record.Data.Review.Status)
+	return record, nil
+}
+
+// queueItem menampilkan URL gambar yang disimpan di samping hasil ekstraksi
+func queueItem(record models.Receipt) models.ReviewQueueItem {
+	item := models.ReviewQueueItem{
+		ReceiptID:           record.ID,
+		Status:              record.Data.Review.Status,
+		Reasons:             record.Data.Review.Reasons,
+		ImageURLs:           record.SourceURLs,
+		ImageStatus:         record.Data.ImageStatus,
+		LowConfidenceFields: record.Data.LowConfidenceFields,
+		Data:                record.Data,
+		CreatedAt:           record.CreatedAt,
+	}
+	if item.ImageURLs == nil {
+		item.ImageURLs = []string{}
+	}
+	if record.Data.Validation != nil {
+		item.ValidationIssues = record.Data.Validation.Issues
+	}
+	return item
+}
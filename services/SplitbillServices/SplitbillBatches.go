@@ -0,0 +1,329 @@
+package splitbillservices
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	appconfig "github.com/arifin2018/splitbill-arifin.git/config/appConfig"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	files "github.com/arifin2018/splitbill-arifin.git/helpers/files"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// batchUpload adalah satu file batch. Err terisi jika file ditolak sebelum job dibuat.
+type batchUpload struct {
+	Upload models.UploadedFile
+	Err    error
+}
+
+// SubmitBatch membuat satu job ekstraksi untuk setiap file di arsip ZIP atau file multipart yang
+// diunggah. Batas batch diperiksa sebelum job dibuat, lalu file dibaca dan disimpan sebagai job satu
+// per satu sehingga hanya satu file batch yang ditahan di memori. Job batch berbagi
+// BATCH_CONCURRENCY slot; file yang ditolak saat dibaca dicatat sebagai file gagal tanpa menggagalkan
+// batch.
+func (splitbilSeviceImpl *SplibillServiceImpl) SubmitBatch(app *fiber.Ctx) (models.Batch, error) {
+	if !splitbilSeviceImpl.jobsEnabled() {
+		return models.Batch{}, errors.New("batch extraction is not available")
+	}
+	fileheaders, err := checkBatch(app)
+	if err != nil {
+		return models.Batch{}, err
+	}
+
+	batch := models.Batch{Files: []models.BatchFile{}}
+	if err := splitbilSeviceImpl.JobRepository.SaveBatch(&batch); err != nil {
+		return models.Batch{}, errors.New(fmt.Sprintf("Error saving batch: %v", err.Error()))
+	}
+	jobIDs := []string{}
+	splitbilSeviceImpl.readBatch(fileheaders, func(upload batchUpload) {
+		file := models.BatchFile{Index: len(batch.Files) + 1, Filename: upload.Upload.Filename}
+		if upload.Err == nil {
+			file.JobID, upload.Err = splitbilSeviceImpl.saveBatchJob(batch.ID, upload.Upload)
+		}
+		if upload.Err != nil {
+			file.Status = models.JobStatusFailed
+			file.Error = jobError(upload.Err)
+		} else {
+			jobIDs = append(jobIDs, file.JobID)
+		}
+		batch.Files = append(batch.Files, file)
+	})
+	if err := splitbilSeviceImpl.JobRepository.SaveBatch(&batch); err != nil {
+		return models.Batch{}, errors.New(fmt.Sprintf("Error saving batch: %v", err.Error()))
+	}
+
+	go splitbilSeviceImpl.runBatch(batch.ID, jobIDs)
+	config.GeneralLogger.Printf("Batch %s queued with %d job(s), %d file(s) rejected\n", batch.ID, len(jobIDs), len(batch.Files)-len(jobIDs))
+	return splitbilSeviceImpl.batchStatus(batch), nil
+}
+
+// GetBatch mengembalikan batch dengan status, progress dan hasil setiap file
+func (splitbilSeviceImpl *SplibillServiceImpl) GetBatch(app *fiber.Ctx) (models.Batch, error) {
+	if !splitbilSeviceImpl.jobsEnabled() {
+		return models.Batch{}, errors.New("batch extraction is not available")
+	}
+	batch, err := splitbilSeviceImpl.JobRepository.FindBatch(app.Params("id"))
+	if err != nil {
+		return models.Batch{}, errors.New(fmt.Sprintf("Error retrieving batch: %v", err.Error()))
+	}
+	return splitbilSeviceImpl.batchStatus(batch), nil
+}
+
+// checkBatch mengambil file dari field "archive" dan "files" lalu memeriksa jumlah file dan total
+// ukuran file (ukuran tak terkompresi untuk isi arsip, dari direktori arsip), sehingga batch yang
+// melebihi BATCH_MAX_FILES atau BATCH_MAX_BYTES ditolak tanpa mendekompresi isinya.
+func checkBatch(app *fiber.Ctx) ([]*multipart.FileHeader, error) {
+	form, err := app.MultipartForm()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error retrieving file: %v", err.Error()))
+	}
+	fileheaders := append(append([]*multipart.FileHeader{}, form.File["archive"]...), form.File["files"]...)
+	if len(fileheaders) == 0 {
+		return nil, errors.New("there is no uploaded file associated with the given key")
+	}
+
+	limit := batchLimit{MaxFiles: maxBatchFiles(), MaxBytes: maxBatchBytes()}
+	for _, fileheader := range fileheaders {
+		file, entries, isArchive, err := openArchive(fileheader)
+		if err != nil {
+			return nil, err
+		}
+		if !isArchive {
+			err = limit.add(1, uint64(fileheader.Size))
+		} else {
+			var size uint64
+			for _, entry := range entries {
+				size += entry.UncompressedSize64
+			}
+			err = limit.add(len(entries), size)
+			file.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if limit.files == 0 {
+		return nil, errors.New("the archive does not contain any file")
+	}
+	return fileheaders, nil
+}
+
+// readBatch membaca file batch satu per satu dan menyerahkan setiap file ke each. Arsip ZIP
+// (dikenali dari magic bytes) dibuka dan setiap file di dalamnya menjadi satu file batch, dibaca
+// dengan batas ukuran yang sama seperti file yang diunggah langsung. File yang gagal dibaca
+// diserahkan dengan Err terisi.
+func (splitbilSeviceImpl *SplibillServiceImpl) readBatch(fileheaders []*multipart.FileHeader, each func(upload batchUpload)) {
+	for _, fileheader := range fileheaders {
+		file, entries, isArchive, err := openArchive(fileheader)
+		if err != nil {
+			each(batchUpload{Upload: models.UploadedFile{Filename: fileheader.Filename}, Err: err})
+			continue
+		}
+		if !isArchive {
+			data, err := splitbilSeviceImpl.readUpload(fileheader)
+			each(batchUpload{
+				Upload: models.UploadedFile{Filename: fileheader.Filename, ContentType: fileheader.Header.Get("Content-Type"), Data: data},
+				Err:    err,
+			})
+			continue
+		}
+		for _, entry := range entries {
+			upload := batchUpload{Upload: models.UploadedFile{Filename: entry.Name}}
+			reader, err := entry.Open()
+			if err != nil {
+				upload.Err = errors.New(fmt.Sprintf("Error reading %s from archive: %v", entry.Name, err.Error()))
+			} else {
+				upload.Upload.Data, upload.Err = splitbilSeviceImpl.readEntry(reader, int64(entry.UncompressedSize64))
+				reader.Close()
+			}
+			each(upload)
+		}
+		file.Close()
+	}
+}
+
+// batchLimit menghitung jumlah file dan total ukuran file batch yang sudah diterima
+type batchLimit struct {
+	MaxFiles int
+	MaxBytes uint64
+	files    int
+	bytes    uint64
+}
+
+// add menambahkan files file berukuran total size ke batch, atau mengembalikan error jika batas
+// jumlah file atau total ukuran terlewati
+func (limit *batchLimit) add(files int, size uint64) error {
+	limit.files += files
+	if limit.files > limit.MaxFiles {
+		return errors.New(fmt.Sprintf("too many files in batch: more than %d", limit.MaxFiles))
+	}
+	limit.bytes += size
+	if limit.bytes > limit.MaxBytes {
+		return errors.New(fmt.Sprintf("batch is too large: more than %d bytes uncompressed", limit.MaxBytes))
+	}
+	return nil
+}
+
+// openArchive membuka file sebagai arsip ZIP dan mengembalikan file di dalamnya tanpa membaca isinya.
+// isArchive false jika file bukan ZIP; jika true, pemanggil harus menutup file.
+func openArchive(fileheader *multipart.FileHeader) (multipart.File, []*zip.File, bool, error) {
+	file, err := fileheader.Open()
+	if err != nil {
+		return nil, nil, false, errors.New(fmt.Sprintf("Error opening uploaded file: %v", err.Error()))
+	}
+	header := make([]byte, 4)
+	if _, err := file.ReadAt(header, 0); err != nil || !files.IsZip(header) {
+		file.Close()
+		return nil, nil, false, nil
+	}
+	entries, err := files.ZipEntries(file, fileheader.Size)
+	if err != nil {
+		file.Close()
+		return nil, nil, false, errors.New(fmt.Sprintf("Error reading archive %s: %v", fileheader.Filename, err.Error()))
+	}
+	return file, entries, true, nil
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) readEntry(reader io.Reader, size int64) ([]byte, error) {
+	if splitbilSeviceImpl.UploadGuard == nil {
+		return io.ReadAll(reader)
+	}
+	data, err := splitbilSeviceImpl.UploadGuard.ReadStream(reader, size)
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	return data, nil
+}
+
+// saveBatchJob menyimpan satu file batch sebagai job async berstatus queued
+func (splitbilSeviceImpl *SplibillServiceImpl) saveBatchJob(batchID string, upload models.UploadedFile) (string, error) {
+	upload.Filename = path.Base(upload.Filename)
+	job := models.Job{Status: models.JobStatusQueued, Stage: models.JobStageQueued, BatchID: batchID}
+	if err := splitbilSeviceImpl.JobRepository.Save(&job); err != nil {
+		return "", errors.New(fmt.Sprintf("Error saving job: %v", err.Error()))
+	}
+	if err := splitbilSeviceImpl.JobRepository.SaveInput(models.JobInput{JobID: job.ID, Files: []models.UploadedFile{upload}}); err != nil {
+		splitbilSeviceImpl.failJob(job, errors.New("job input could not be saved"))
+		return "", errors.New(fmt.Sprintf("Error saving job input: %v", err.Error()))
+	}
+	return job.ID, nil
+}
+
+// runBatch menjalankan job batch lewat batchSlots, yang dibagi semua batch, terpisah dari worker
+// pool job async biasa. Karena itu paling banyak JOB_WORKERS + BATCH_CONCURRENCY job berjalan
+// bersamaan; panggilan model tetap dibatasi EXTRACTION_MAX_IN_FLIGHT. Job batch yang belum selesai
+// saat aplikasi berhenti dilanjutkan oleh resumeJobs lewat worker pool.
+func (splitbilSeviceImpl *SplibillServiceImpl) runBatch(batchID string, jobIDs []string) {
+	var wg sync.WaitGroup
+	for _, jobID := range jobIDs {
+		splitbilSeviceImpl.batchSlots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-splitbilSeviceImpl.batchSlots
+				wg.Done()
+			}()
+			splitbilSeviceImpl.runJob(jobID)
+		}()
+	}
+	wg.Wait()
+	config.GeneralLogger.Printf("Batch %s finished\n", batchID)
+}
+
+// batchStatus melengkapi batch dengan status setiap job dan menghitung status, jumlah serta
+// progress keseluruhan. Progress adalah rata-rata progress file; file yang selesai bernilai 100.
+func (splitbilSeviceImpl *SplibillServiceImpl) batchStatus(batch models.Batch) models.Batch {
+	batch.Total, batch.Completed, batch.Succeeded, batch.Failed = len(batch.Files), 0, 0, 0
+	progress, started := 0, false
+	for i, file := range batch.Files {
+		if file.JobID != "" {
+			job, err := splitbilSeviceImpl.JobRepository.FindByID(file.JobID)
+			if err != nil {
+				file.Status = models.JobStatusFailed
+				file.Error = &models.JobError{Status: fiber.StatusNotAcceptable, Message: fmt.Sprintf("job not found: %v", err.Error())}
+			} else {
+				file.Status, file.Stage, file.Progress, file.Error, file.Result = job.Status, job.Stage, job.Progress, job.Error, job.Result
+				if job.Result != nil {
+					file.ReceiptID = job.Result.ReceiptID
+				}
+			}
+		}
+		switch file.Status {
+		case models.JobStatusSucceeded:
+			batch.Succeeded++
+			file.Progress = 100
+		case models.JobStatusFailed:
+			batch.Failed++
+			file.Progress = 100
+		case models.JobStatusRunning:
+			started = true
+		}
+		progress += file.Progress
+		batch.Files[i] = file
+	}
+
+	batch.Completed = batch.Succeeded + batch.Failed
+	switch {
+	case batch.Completed == batch.Total:
+		batch.Status = models.BatchStatusCompleted
+	case started || batch.Completed > 0:
+		batch.Status = models.BatchStatusRunning
+	default:
+		batch.Status = models.BatchStatusQueued
+	}
+	if batch.Total > 0 {
+		batch.Progress = progress / batch.Total
+	}
+	return batch
+}
+
+func maxBatchFiles() int {
+	maxFiles, err := strconv.Atoi(os.Getenv("BATCH_MAX_FILES"))
+	if err != nil || maxFiles <= 0 {
+		return 100
+	}
+	return maxFiles
+}
+
+// maxBatchBytes membaca BATCH_MAX_BYTES. Defaultnya sama dengan batas body request, sehingga isi arsip
+// yang didekompresi tidak lebih besar dari request yang diterima.
+func maxBatchBytes() uint64 {
+	maxBytes, err := strconv.ParseUint(os.Getenv("BATCH_MAX_BYTES"), 10, 64)
+	if err != nil || maxBytes == 0 {
+		return uint64(appconfig.BodyLimit())
+	}
+	return maxBytes
+}
+
+// batchConcurrency membaca jumlah job batch yang boleh berjalan bersamaan di semua batch dari
+// BATCH_CONCURRENCY (default 4)
+func batchConcurrency() int {
+	concurrency, err := strconv.Atoi(os.Getenv("BATCH_CONCURRENCY"))
+	if err != nil || concurrency <= 0 {
+		return 4
+	}
+	return concurrency
+}
+
+// BatchCSV mengembalikan hasil batch sebagai CSV gabungan, satu baris per item
+func (splitbilSeviceImpl *SplibillServiceImpl) BatchCSV(app *fiber.Ctx) ([]byte, error) {
+	batch, err := splitbilSeviceImpl.GetBatch(app)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := receipts.WriteBatchCSV(&buffer, batch); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error writing batch CSV: %v", err.Error()))
+	}
+	return buffer.Bytes(), nil
+}
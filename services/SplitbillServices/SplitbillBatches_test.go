@@ -0,0 +1,158 @@
+package splitbillservices
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type batchPart struct {
+	field    string
+	filename string
+	data     []byte
+}
+
+func zipArchive(t *testing.T, entries ...string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for i := 0; i+1 < len(entries); i += 2 {
+		entry, err := writer.Create(entries[i])
+		if err != nil {
+			t.Fatalf("Create(%q) error = %v", entries[i], err)
+		}
+		entry.Write([]byte(entries[i+1]))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buffer.Bytes()
+}
+
+// readTestBatch mengirim parts sebagai request multipart lalu menjalankan checkBatch dan readBatch
+func readTestBatch(t *testing.T, parts ...batchPart) ([]batchUpload, error) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range parts {
+		file, err := writer.CreateFormFile(part.field, part.filename)
+		if err != nil {
+			t.Fatalf("CreateFormFile() error = %v", err)
+		}
+		file.Write(part.data)
+	}
+	writer.Close()
+
+	service := &SplibillServiceImpl{}
+	uploads := []batchUpload{}
+	var checkErr error
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		fileheaders, err := checkBatch(c)
+		if checkErr = err; err == nil {
+			service.readBatch(fileheaders, func(upload batchUpload) {
+				uploads = append(uploads, upload)
+			})
+		}
+		return nil
+	})
+	request := httptest.NewRequest(fiber.MethodPost, "/", &body)
+	request.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	if _, err := app.Test(request); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	return uploads, checkErr
+}
+
+func TestReadBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxFiles string
+		maxBytes string
+		parts    []batchPart
+		files    []string
+		data     []string
+		err      string
+	}{
+		{
+			name:  "archive skips folders and metadata",
+			parts: []batchPart{{field: "archive", filename: "struk.zip", data: zipArchive(t, "a.jpg", "aaa", "__MACOSX/._a.jpg", "x", ".DS_Store", "x", "dir/b.pdf", "bb")}},
+			files: []string{"a.jpg", "dir/b.pdf"},
+			data:  []string{"aaa", "bb"},
+		},
+		{
+			name: "archive and plain files",
+			parts: []batchPart{
+				{field: "archive", filename: "struk.zip", data: zipArchive(t, "a.jpg", "aaa")},
+				{field: "files", filename: "c.jpg", data: []byte("cccc")},
+			},
+			files: []string{"a.jpg", "c.jpg"},
+			data:  []string{"aaa", "cccc"},
+		},
+		{
+			name:     "too many files",
+			maxFiles: "2",
+			parts: []batchPart{
+				{field: "archive", filename: "struk.zip", data: zipArchive(t, "a.jpg", "a", "b.jpg", "b")},
+				{field: "files", filename: "c.jpg", data: []byte("c")},
+			},
+			err: "too many files in batch",
+		},
+		{
+			name:     "uncompressed size over the limit",
+			maxBytes: "10",
+			parts:    []batchPart{{field: "archive", filename: "struk.zip", data: zipArchive(t, "a.jpg", strings.Repeat("a", 11))}},
+			err:      "batch is too large",
+		},
+		{
+			name:  "empty archive",
+			parts: []batchPart{{field: "archive", filename: "struk.zip", data: zipArchive(t, ".hidden", "x")}},
+			err:   "does not contain any file",
+		},
+		{
+			name:  "no files",
+			parts: []batchPart{{field: "other", filename: "a.jpg", data: []byte("a")}},
+			err:   "no uploaded file",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("BATCH_MAX_FILES", test.maxFiles)
+			t.Setenv("BATCH_MAX_BYTES", test.maxBytes)
+			uploads, err := readTestBatch(t, test.parts...)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				if len(uploads) != 0 {
+					t.Errorf("read %d file(s) from a rejected batch", len(uploads))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			files, data := []string{}, []string{}
+			for _, upload := range uploads {
+				if upload.Err != nil {
+					t.Errorf("%s: error = %v", upload.Upload.Filename, upload.Err)
+				}
+				files = append(files, upload.Upload.Filename)
+				data = append(data, string(upload.Upload.Data))
+			}
+			if !slices.Equal(files, test.files) {
+				t.Errorf("files = %v, want %v", files, test.files)
+			}
+			if !slices.Equal(data, test.data) {
+				t.Errorf("data = %v, want %v", data, test.data)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package splitbillservices
+
+import (
+	"errors"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	caches "github.com/arifin2018/splitbill-arifin.git/helpers/Caches"
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// extractCached mengembalikan hasil ekstraksi dari cache jika input yang sama pernah diekstrak.
+// Key cache adalah SHA-256 dari bytes yang dikirim ke model (setelah normalisasi dan preprocessing),
+// jenis input, model dan versi prompt. Hasil hanya disimpan ke cache jika ekstraksi berhasil; hasil
+// parsial yang dikembalikan bersama ErrUnreconciled tetap dipakai tetapi tidak disimpan ke cache,
+// sehingga upload berikutnya mencoba mengekstrak ulang.
+func (splitbilSeviceImpl *SplibillServiceImpl) extractCached(kind string, inputs [][]byte, extract func() ([]models.SplitbillResponse, error)) ([]models.SplitbillResponse, error) {
+	var key string
+	if splitbilSeviceImpl.ExtractionCache != nil {
+		key = caches.Key(kind, splitbilSeviceImpl.extractorVersion(), inputs...)
+		if cached, ok := splitbilSeviceImpl.ExtractionCache.Get(key); ok {
+			config.GeneralLogger.Printf("Extraction cache hit for %s input %s\n", kind, key[:12])
+			for i := range cached {
+				cached[i].Cached = true
+			}
+			return cached, nil
+		}
+	}
+
+	result, err := extract()
+	if partialExtraction(err) && len(result) > 0 {
+		config.GeneralLogger.Printf("Using partial extraction result without caching it: %v\n", err.Error())
+		return result, nil
+	}
+	if err == nil && len(result) > 0 && splitbilSeviceImpl.ExtractionCache != nil {
+		splitbilSeviceImpl.ExtractionCache.Set(key, result)
+	}
+	return result, err
+}
+
+// partialExtraction bernilai true jika extractor mengembalikan hasil parsial yang totalnya belum
+// cocok dengan item-itemnya. Hasil tersebut tetap disimpan; validasi menandainya untuk direview.
+func partialExtraction(err error) bool {
+	return errors.Is(err, extractors.ErrUnreconciled)
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) extractorVersion() string {
+	if versioned, ok := splitbilSeviceImpl.Extractor.(extractors.VersionedInterface); ok {
+		return versioned.Version()
+	}
+	return ""
+}
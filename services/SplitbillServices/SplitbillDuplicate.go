@@ -0,0 +1,143 @@
+package splitbillservices
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	images "github.com/arifin2018/splitbill-arifin.git/helpers/Images"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	"github.com/gofiber/fiber/v2"
+)
+
+// imageHash menghitung perceptual hash gambar yang disimpan; gambar yang tidak bisa didekode
+// (misalnya HEIC tanpa transcoder) tidak memiliki hash
+func imageHash(imageData []byte) string {
+	hash, err := images.PerceptualHash(imageData)
+	if err != nil {
+		config.GeneralLogger.Printf("Perceptual hash skipped: %v\n", err.Error())
+		return ""
+	}
+	return hash
+}
+
+func withImageHash(hash string) func(record *models.Receipt) {
+	return func(record *models.Receipt) {
+		record.ImageHash = hash
+	}
+}
+
+// findDuplicate membandingkan receipt baru dengan receipt tersimpan yang nomor transaksi atau
+// totalnya sama (dicari lewat indeks duplikat repository) memakai perceptual hash foto dan nomor
+// transaksi, tanggal, jam, nama toko serta total hasil ekstraksi. Receipt lain dari foto yang sama
+// (SourceGroupID sama) tidak dibandingkan.
+func (splitbilSeviceImpl *SplibillServiceImpl) findDuplicate(record models.Receipt) *models.DuplicateWarning {
+	if !duplicateDetectionEnabled() {
+		return nil
+	}
+	existingReceipts, err := splitbilSeviceImpl.ReceiptRepository.FindDuplicateCandidates(receipts.DuplicateCandidateKeys(record.Data))
+	if err != nil {
+		config.GeneralLogger.Printf("Duplicate detection skipped: %v\n", err.Error())
+		return nil
+	}
+
+	// Receipt terbaru dicek lebih dulu karena duplikat biasanya diunggah berdekatan
+	for i := len(existingReceipts) - 1; i >= 0; i-- {
+		existing := existingReceipts[i]
+		if existing.ID == record.ID || (record.SourceGroupID != "" && existing.SourceGroupID == record.SourceGroupID) {
+			continue
+		}
+		reasons, duplicate := receipts.DuplicateMatch(record.Data, existing.Data, images.HashDistance(record.ImageHash, existing.ImageHash))
+		if !duplicate {
+			continue
+		}
+		config.GeneralLogger.Printf("Receipt is a probable duplicate of %s: %v\n", existing.ID, reasons)
+		return &models.DuplicateWarning{
+			ReceiptID: existing.ID,
+			URL:       "/receipts/" + existing.ID,
+			Reasons:   reasons,
+			Actions:   []string{models.DuplicateActionMerge, models.DuplicateActionKeepBoth},
+		}
+	}
+	return nil
+}
+
+// ResolveDuplicate menjalankan pilihan client untuk receipt yang diberi peringatan duplikat.
+// "merge" menghapus receipt ini dan menambahkan file sumbernya ke receipt yang sudah ada, lalu
+// mengembalikan receipt yang sudah ada. "keep_both" menyimpan keduanya dan menghapus peringatannya.
+func (splitbilSeviceImpl *SplibillServiceImpl) ResolveDuplicate(app *fiber.Ctx) (models.Receipt, error) {
+	var request models.DuplicateResolutionRequest
+	if err := app.BodyParser(&request); err != nil {
+		config.GeneralLogger.Printf("Error parsing duplicate resolution request: %v\n", err.Error())
+		return models.Receipt{}, errors.New(fmt.Sprintf("Error parsing request: %v", err.Error()))
+	}
+
+	// checkRecord dijalankan di bawah kunci receipt sehingga status yang diperiksa adalah yang terbaru
+	checkRecord := func(record *models.Receipt) error {
+		if record.Data.DuplicateWarning == nil {
+			return errors.New("receipt has no duplicate warning")
+		}
+		if record.LockedAt != nil {
+			return helpers.NewApiError(fiber.StatusConflict, helpers.ErrCodeReceiptLocked, "receipt is locked", nil)
+		}
+		return nil
+	}
+
+	switch request.Action {
+	case models.DuplicateActionKeepBoth:
+		return splitbilSeviceImpl.ReceiptRepository.Update(app.Params("id"), func(record *models.Receipt) error {
+			if err := checkRecord(record); err != nil {
+				return err
+			}
+			record.Data.DuplicateWarning = nil
+			return nil
+		})
+	case models.DuplicateActionMerge:
+		record, err := splitbilSeviceImpl.ReceiptRepository.Update(app.Params("id"), func(record *models.Receipt) error {
+			if err := checkRecord(record); err != nil {
+				return err
+			}
+			// File yang masih di spool akan diunggah ke receipt ini; jika receipt dihapus sekarang,
+			// file tersebut hilang dari receipt hasil merge
+			if record.Data.ImageStatus == models.ImageStatusPending {
+				return helpers.NewApiError(fiber.StatusConflict, helpers.ErrCodeImagePending,
+					"receipt image is still being uploaded, retry the merge once image_status is stored", nil)
+			}
+			return receiptrepositories.ErrUnchanged
+		})
+		if err != nil {
+			return models.Receipt{}, err
+		}
+		existing, err := splitbilSeviceImpl.ReceiptRepository.Update(record.Data.DuplicateWarning.ReceiptID, func(existing *models.Receipt) error {
+			if existing.LockedAt != nil {
+				return helpers.NewApiError(fiber.StatusConflict, helpers.ErrCodeReceiptLocked, "existing receipt is locked and cannot be merged into", nil)
+			}
+			for _, sourceURL := range record.SourceURLs {
+				if sourceURL != "" && !slices.Contains(existing.SourceURLs, sourceURL) {
+					existing.SourceURLs = append(existing.SourceURLs, sourceURL)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return models.Receipt{}, err
+		}
+		if err := splitbilSeviceImpl.ReceiptRepository.Delete(record.ID); err != nil {
+			return models.Receipt{}, errors.New(fmt.Sprintf("Error deleting duplicate receipt: %v", err.Error()))
+		}
+		config.GeneralLogger.Printf("Receipt %s merged into %s\n", record.ID, existing.ID)
+		return existing, nil
+	}
+	return models.Receipt{}, errors.New(fmt.Sprintf("unknown action %q, use %q or %q", request.Action, models.DuplicateActionMerge, models.DuplicateActionKeepBoth))
+}
+
+func duplicateDetectionEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("DUPLICATE_DETECTION_ENABLED"))
+	return err != nil || enabled
+}
@@ -0,0 +1,198 @@
+package splitbillservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SubmitSplitbil membaca file yang diunggah lalu menyimpannya sebagai job async. Ekstraksi
+// dijalankan oleh worker pool; hasilnya bisa diambil lewat GET /jobs/:id.
+func (splitbilSeviceImpl *SplibillServiceImpl) SubmitSplitbil(app *fiber.Ctx) (models.JobAccepted, error) {
+	if !splitbilSeviceImpl.jobsEnabled() {
+		return models.JobAccepted{}, errors.New("async extraction jobs are not available")
+	}
+	target, err := splitbilSeviceImpl.webhookTarget(app)
+	if err != nil {
+		return models.JobAccepted{}, err
+	}
+	uploads, err := splitbilSeviceImpl.readUploads(app)
+	if err != nil {
+		return models.JobAccepted{}, err
+	}
+
+	job := models.Job{Status: models.JobStatusQueued, Stage: models.JobStageQueued, Callback: target}
+	if err := splitbilSeviceImpl.JobRepository.Save(&job); err != nil {
+		return models.JobAccepted{}, errors.New(fmt.Sprintf("Error saving job: %v", err.Error()))
+	}
+	// Input disimpan sebelum job masuk antrean agar job tetap bisa dijalankan setelah restart
+	// Kegagalan sebelum job masuk antrean dikembalikan langsung ke client, tanpa webhook
+	job.Callback = nil
+	if err := splitbilSeviceImpl.JobRepository.SaveInput(models.JobInput{JobID: job.ID, Files: uploads}); err != nil {
+		splitbilSeviceImpl.failJob(job, errors.New("job input could not be saved"))
+		return models.JobAccepted{}, errors.New(fmt.Sprintf("Error saving job input: %v", err.Error()))
+	}
+	if !splitbilSeviceImpl.JobPool.TryEnqueue(job.ID) {
+		queueErr := helpers.NewApiError(fiber.StatusServiceUnavailable, helpers.ErrCodeJobQueueFull, "job queue is full, please retry later", nil)
+		splitbilSeviceImpl.failJob(job, queueErr)
+		return models.JobAccepted{}, queueErr
+	}
+	config.GeneralLogger.Printf("Job %s queued with %d file(s)\n", job.ID, len(uploads))
+	return models.JobAccepted{JobID: job.ID, Status: job.Status, StatusURL: "/jobs/" + job.ID}, nil
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) GetJob(app *fiber.Ctx) (models.Job, error) {
+	if !splitbilSeviceImpl.jobsEnabled() {
+		return models.Job{}, errors.New("async extraction jobs are not available")
+	}
+	job, err := splitbilSeviceImpl.JobRepository.FindByID(app.Params("id"))
+	if err != nil {
+		return models.Job{}, errors.New(fmt.Sprintf("Error retrieving job: %v", err.Error()))
+	}
+	return job, nil
+}
+
+// jobStages memetakan event progress ke tahap job yang sedang berjalan setelah event tersebut.
+// Event tanpa tahap (stored dan item) tidak mengubah job.
+var jobStages = map[string]string{
+	models.ProgressEventUploaded:     models.JobStagePreprocessing,
+	models.ProgressEventPreprocessed: models.JobStageExtracting,
+	models.ProgressEventModelStarted: models.JobStageExtracting,
+	models.ProgressEventValidated:    models.JobStageSaving,
+}
+
+// runJob dijalankan oleh worker pool dan runBatch. Job diklaim lebih dulu sehingga tidak berjalan dua
+// kali; job yang sudah dicoba lebih dari JOB_MAX_ATTEMPTS kali (misalnya karena aplikasi berhenti
+// setiap kali menjalankannya) ditandai gagal. Status, tahap dan progress job disimpan setiap kali
+// berubah sehingga bisa dipantau lewat GET /jobs/:id.
+func (splitbilSeviceImpl *SplibillServiceImpl) runJob(jobID string) {
+	job, claimed, err := splitbilSeviceImpl.JobRepository.Claim(jobID)
+	if err != nil {
+		config.GeneralLogger.Printf("Job %s could not be claimed: %v\n", jobID, err.Error())
+		return
+	}
+	if !claimed {
+		return
+	}
+	defer splitbilSeviceImpl.JobRepository.Release(jobID)
+	// Claim sudah menambah Attempts untuk percobaan ini
+	if job.Attempts > jobMaxAttempts() {
+		splitbilSeviceImpl.failJob(job, attemptsExceeded())
+		return
+	}
+	input, err := splitbilSeviceImpl.JobRepository.FindInput(jobID)
+	if err != nil {
+		splitbilSeviceImpl.failJob(job, errors.New(fmt.Sprintf("job input not found: %v", err.Error())))
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			splitbilSeviceImpl.failJob(job, errors.New(fmt.Sprintf("Error occured %v", r)))
+		}
+	}()
+	// Event "stored" dilaporkan dari goroutine upload, bersamaan dengan event extractor. Job menunggu
+	// slot model tanpa batas antrean karena tidak ada client yang menunggu respons.
+	var progressLock sync.Mutex
+	result, err := splitbilSeviceImpl.extractUploads(limits.Background(context.Background()), input.Files, func(event models.ProgressEvent) {
+		stage, ok := jobStages[event.Event]
+		if !ok {
+			return
+		}
+		progressLock.Lock()
+		defer progressLock.Unlock()
+		job.Stage, job.Progress = stage, event.Progress
+		splitbilSeviceImpl.saveJob(&job)
+	})
+	if err != nil {
+		splitbilSeviceImpl.failJob(job, err)
+		return
+	}
+
+	finished := time.Now()
+	job.Status, job.Stage, job.Progress = models.JobStatusSucceeded, models.JobStageDone, 100
+	job.Result = &result
+	job.FinishedAt = &finished
+	splitbilSeviceImpl.saveJob(&job)
+	splitbilSeviceImpl.deleteJobInput(job.ID)
+	splitbilSeviceImpl.notifyExtraction(job.Callback, job.ID, result, nil)
+	config.GeneralLogger.Printf("Job %s succeeded in %v\n", job.ID, finished.Sub(*job.StartedAt))
+}
+
+// failJob menandai job gagal dengan status dan code yang sama seperti respons request sinkron
+func (splitbilSeviceImpl *SplibillServiceImpl) failJob(job models.Job, err error) {
+	finished := time.Now()
+	job.Status = models.JobStatusFailed
+	job.Error = jobError(err)
+	job.FinishedAt = &finished
+	splitbilSeviceImpl.saveJob(&job)
+	splitbilSeviceImpl.deleteJobInput(job.ID)
+	splitbilSeviceImpl.notifyExtraction(job.Callback, job.ID, models.SplitbillResponse{}, err)
+	config.GeneralLogger.Printf("Job %s failed: %v\n", job.ID, err.Error())
+}
+
+// attemptsExceeded adalah error job yang dihentikan karena sudah dicoba JOB_MAX_ATTEMPTS kali
+func attemptsExceeded() error {
+	return helpers.NewApiError(fiber.StatusInternalServerError, helpers.ErrCodeJobAttemptsExceeded,
+		fmt.Sprintf("job was interrupted after %d attempt(s) and will not be retried", jobMaxAttempts()), nil)
+}
+
+// jobError mengubah error menjadi status dan code yang sama seperti respons request sinkron
+func jobError(err error) *models.JobError {
+	var apiError *helpers.ApiError
+	if errors.As(err, &apiError) {
+		return &models.JobError{Status: apiError.Status, Code: apiError.Code, Message: apiError.Message, Data: apiError.Data}
+	}
+	return &models.JobError{Status: fiber.StatusNotAcceptable, Message: err.Error()}
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) saveJob(job *models.Job) {
+	if err := splitbilSeviceImpl.JobRepository.Save(job); err != nil {
+		config.GeneralLogger.Printf("Failed to save job %s: %v\n", job.ID, err.Error())
+	}
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) deleteJobInput(jobID string) {
+	if err := splitbilSeviceImpl.JobRepository.DeleteInput(jobID); err != nil {
+		config.GeneralLogger.Printf("Failed to delete input of job %s: %v\n", jobID, err.Error())
+	}
+}
+
+// resumeJobs memasukkan kembali job yang belum selesai saat aplikasi berhenti. Job yang sedang
+// berjalan saat itu diulang dari awal; batas JOB_MAX_ATTEMPTS diperiksa oleh runJob.
+func (splitbilSeviceImpl *SplibillServiceImpl) resumeJobs() {
+	unfinished, err := splitbilSeviceImpl.JobRepository.FindUnfinished()
+	if err != nil {
+		config.GeneralLogger.Printf("Failed to load unfinished jobs: %v\n", err.Error())
+		return
+	}
+	if len(unfinished) > 0 {
+		config.GeneralLogger.Printf("Resuming %d unfinished job(s)\n", len(unfinished))
+	}
+	for _, job := range unfinished {
+		splitbilSeviceImpl.JobPool.Enqueue(job.ID)
+	}
+}
+
+// jobMaxAttempts membaca JOB_MAX_ATTEMPTS (default 3), jumlah maksimum job dijalankan
+func jobMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("JOB_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		return 3
+	}
+	return attempts
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) jobsEnabled() bool {
+	return splitbilSeviceImpl.JobRepository != nil && splitbilSeviceImpl.JobPool != nil
+}
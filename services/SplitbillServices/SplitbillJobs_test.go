@@ -0,0 +1,106 @@
+package splitbillservices
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	jobrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/JobRepositories"
+)
+
+// fakePool mencatat job yang dimasukkan ke antrean tanpa menjalankannya
+type fakePool struct {
+	jobIDs []string
+}
+
+func (pool *fakePool) Start(handler func(jobID string)) {}
+
+func (pool *fakePool) TryEnqueue(jobID string) bool {
+	pool.jobIDs = append(pool.jobIDs, jobID)
+	return true
+}
+
+func (pool *fakePool) Enqueue(jobID string) {
+	pool.jobIDs = append(pool.jobIDs, jobID)
+}
+
+func TestRunJobFailures(t *testing.T) {
+	quietLogger()
+	t.Setenv("JOB_MAX_ATTEMPTS", "")
+	tests := []struct {
+		name     string
+		job      models.Job
+		input    bool
+		status   string
+		code     string
+		message  string
+		attempts int
+	}{
+		// job yang terhenti tiga kali tidak dijalankan untuk keempat kalinya
+		{name: "attempts exceeded", job: models.Job{Status: models.JobStatusRunning, Attempts: 3}, input: true, status: models.JobStatusFailed, code: helpers.ErrCodeJobAttemptsExceeded, attempts: 4},
+		{name: "missing input", job: models.Job{Status: models.JobStatusQueued}, status: models.JobStatusFailed, message: "job input not found", attempts: 1},
+		{name: "already finished", job: models.Job{Status: models.JobStatusSucceeded, Attempts: 1}, input: true, status: models.JobStatusSucceeded, attempts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := jobrepositories.NewJobRepositoryImpl(documents.NewFile(t.TempDir()))
+			service := &SplibillServiceImpl{JobRepository: repository}
+			job := test.job
+			if err := repository.Save(&job); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			if test.input {
+				if err := repository.SaveInput(models.JobInput{JobID: job.ID}); err != nil {
+					t.Fatalf("SaveInput() error = %v", err)
+				}
+			}
+
+			service.runJob(job.ID)
+			saved, err := repository.FindByID(job.ID)
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if saved.Status != test.status || saved.Attempts != test.attempts {
+				t.Errorf("job = %s after %d attempt(s), want %s after %d", saved.Status, saved.Attempts, test.status, test.attempts)
+			}
+			if test.status != models.JobStatusFailed {
+				return
+			}
+			if saved.Error == nil || saved.Error.Code != test.code || !strings.Contains(saved.Error.Message, test.message) {
+				t.Errorf("job error = %+v, want code %q message %q", saved.Error, test.code, test.message)
+			}
+			if _, err := repository.FindInput(job.ID); err == nil {
+				t.Errorf("input of failed job was not deleted")
+			}
+		})
+	}
+}
+
+func TestResumeJobs(t *testing.T) {
+	quietLogger()
+	repository := jobrepositories.NewJobRepositoryImpl(documents.NewFile(t.TempDir()))
+	pool := &fakePool{}
+	service := &SplibillServiceImpl{JobRepository: repository, JobPool: pool}
+
+	start := time.Now().Add(-time.Hour)
+	want := []string{}
+	for i, status := range []string{models.JobStatusQueued, models.JobStatusSucceeded, models.JobStatusRunning} {
+		job := models.Job{Status: status, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := repository.Save(&job); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if status != models.JobStatusSucceeded {
+			want = append(want, job.ID)
+		}
+	}
+
+	service.resumeJobs()
+	if !slices.Equal(pool.jobIDs, want) {
+		t.Errorf("resumed %v, want %v", pool.jobIDs, want)
+	}
+}
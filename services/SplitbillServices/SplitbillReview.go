@@ -0,0 +1,55 @@
+package splitbillservices
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reviewFor menentukan status review receipt baru. Receipt yang validasinya gagal atau memiliki
+// field dengan confidence rendah masuk antrean review dengan status pending_review.
+func reviewFor(receipt models.SplitbillResponse) *models.ReceiptReview {
+	if !reviewQueueEnabled() {
+		return nil
+	}
+	reasons := receipts.ReviewReasons(receipt)
+	if len(reasons) == 0 {
+		return &models.ReceiptReview{Status: models.ReviewStatusNotRequired}
+	}
+	config.GeneralLogger.Printf("Receipt queued for review: %v\n", reasons)
+	return &models.ReceiptReview{Status: models.ReviewStatusPendingReview, Reasons: reasons}
+}
+
+// LockReceipt mengunci bill sehingga isinya tidak berubah lagi saat dibagi. Receipt yang masih
+// menunggu review atau ditolak tidak bisa dikunci.
+func (splitbilSeviceImpl *SplibillServiceImpl) LockReceipt(app *fiber.Ctx) (models.Receipt, error) {
+	record, err := splitbilSeviceImpl.ReceiptRepository.Update(app.Params("id"), func(record *models.Receipt) error {
+		if record.LockedAt != nil {
+			return receiptrepositories.ErrUnchanged
+		}
+		if review := record.Data.Review; !receipts.ReviewCleared(review) {
+			return helpers.NewApiError(fiber.StatusConflict, helpers.ErrCodeReviewRequired, fmt.Sprintf("receipt must be approved before the bill can be locked (review status: %s)", review.Status), review)
+		}
+		now := time.Now()
+		record.LockedAt = &now
+		return nil
+	})
+	if err != nil {
+		return models.Receipt{}, err
+	}
+	config.GeneralLogger.Printf("Receipt %s locked\n", record.ID)
+	return record, nil
+}
+
+func reviewQueueEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("REVIEW_QUEUE_ENABLED"))
+	return err != nil || enabled
+}
@@ -1,14 +1,79 @@
 package splitbillservices
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"context"
+
+	caches "github.com/arifin2018/splitbill-arifin.git/helpers/Caches"
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	images "github.com/arifin2018/splitbill-arifin.git/helpers/Images"
+	jobs "github.com/arifin2018/splitbill-arifin.git/helpers/Jobs"
+	files "github.com/arifin2018/splitbill-arifin.git/helpers/files"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	jobrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/JobRepositories"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	webhookservices "github.com/arifin2018/splitbill-arifin.git/services/WebhookServices"
+	"github.com/gofiber/fiber/v2"
+)
 
 type SplibillService interface {
-	Splitbil(app *fiber.Ctx) (map[string]interface{}, error)
+	Splitbil(app *fiber.Ctx) (models.SplitbillResponse, error)
+	SplitbilText(app *fiber.Ctx) (models.SplitbillResponse, error)
+	GetReceipt(app *fiber.Ctx) (models.Receipt, error)
+	ResolveDuplicate(app *fiber.Ctx) (models.Receipt, error)
+	LockReceipt(app *fiber.Ctx) (models.Receipt, error)
+	SubmitSplitbil(app *fiber.Ctx) (models.JobAccepted, error)
+	StreamSplitbil(app *fiber.Ctx) (func(ctx context.Context, emit func(event models.ProgressEvent)), error)
+	SubmitBatch(app *fiber.Ctx) (models.Batch, error)
+	GetBatch(app *fiber.Ctx) (models.Batch, error)
+	BatchCSV(app *fiber.Ctx) ([]byte, error)
+	GetJob(app *fiber.Ctx) (models.Job, error)
 }
 
 type SplibillServiceImpl struct {
+	Extractor         extractors.ExtractorInterface
+	Classifier        extractors.ClassifierInterface
+	UploadGuard       images.UploadGuardInterface
+	Normalizer        images.NormalizerInterface
+	QualityChecker    images.QualityCheckerInterface
+	Preprocessor      images.PreprocessorInterface
+	ReceiptRepository receiptrepositories.ReceiptRepository
+	Spool             files.SpoolInterface
+	ExtractionCache   caches.ExtractionCacheInterface
+	JobRepository     jobrepositories.JobRepository
+	JobPool           jobs.PoolInterface
+	Webhooks          webhookservices.WebhookNotifier
+
+	// batchSlots membatasi job batch yang berjalan bersamaan di semua batch (BATCH_CONCURRENCY)
+	batchSlots chan struct{}
 }
 
-func NewSplitbillServiceImpl() *SplibillServiceImpl {
-	return &SplibillServiceImpl{}
+func NewSplitbillServiceImpl(extractor extractors.ExtractorInterface, classifier extractors.ClassifierInterface, uploadGuard images.UploadGuardInterface, normalizer images.NormalizerInterface, qualityChecker images.QualityCheckerInterface, preprocessor images.PreprocessorInterface, receiptRepository receiptrepositories.ReceiptRepository, spool files.SpoolInterface, extractionCache caches.ExtractionCacheInterface, jobRepository jobrepositories.JobRepository, jobPool jobs.PoolInterface, webhooks webhookservices.WebhookNotifier) *SplibillServiceImpl {
+	splitbilSeviceImpl := &SplibillServiceImpl{
+		Extractor:         extractor,
+		Classifier:        classifier,
+		UploadGuard:       uploadGuard,
+		Normalizer:        normalizer,
+		QualityChecker:    qualityChecker,
+		Preprocessor:      preprocessor,
+		ReceiptRepository: receiptRepository,
+		Spool:             spool,
+		ExtractionCache:   extractionCache,
+		JobRepository:     jobRepository,
+		JobPool:           jobPool,
+		Webhooks:          webhooks,
+		batchSlots:        make(chan struct{}, batchConcurrency()),
+	}
+	// File yang gagal diunggah saat bucket tidak tersedia diunggah ulang di background sampai
+	// jobs.Shutdown
+	if splitbilSeviceImpl.spoolEnabled() {
+		jobs.Go(func(ctx context.Context) {
+			splitbilSeviceImpl.uploadSpooled(ctx, spoolInterval())
+		})
+	}
+	// Job async dijalankan worker pool; job yang belum selesai sebelum restart dilanjutkan
+	if splitbilSeviceImpl.jobsEnabled() {
+		splitbilSeviceImpl.JobPool.Start(splitbilSeviceImpl.runJob)
+		go splitbilSeviceImpl.resumeJobs()
+	}
+	return splitbilSeviceImpl
 }
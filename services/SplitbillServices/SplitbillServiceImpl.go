@@ -2,194 +2,613 @@ package splitbillservices
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil" // Tambahkan ini
+	"io"
+	"mime/multipart"
 	"os"
+	"strconv"
 	"strings"
 
 	// "time" // Tidak perlu lagi timestamp di sini, karena sudah di handle di UploadFile
 
 	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	images "github.com/arifin2018/splitbill-arifin.git/helpers/Images"
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	receipts "github.com/arifin2018/splitbill-arifin.git/helpers/Receipts"
 	files "github.com/arifin2018/splitbill-arifin.git/helpers/files"
 	"github.com/arifin2018/splitbill-arifin.git/helpers/files/buckets"
+	"github.com/arifin2018/splitbill-arifin.git/models"
 	"github.com/gofiber/fiber/v2"
-	"google.golang.org/genai"
+	"github.com/google/uuid"
 )
 
-func (splitbilSeviceImpl *SplibillServiceImpl) Splitbil(app *fiber.Ctx) (map[string]interface{}, error) {
-	var bucketInterface buckets.BucketInterface
-	fileheader, err := app.FormFile("image")
+func (splitbilSeviceImpl *SplibillServiceImpl) Splitbil(app *fiber.Ctx) (models.SplitbillResponse, error) {
+	target, err := splitbilSeviceImpl.webhookTarget(app)
 	if err != nil {
-		config.GeneralLogger.Printf("Error retrieving file from form: %v\n", err.Error()) // Log lebih spesifik
-		return nil, errors.New(fmt.Sprintf("Error retrieving file: %v", err.Error()))
+		return models.SplitbillResponse{}, err
+	}
+	uploads, err := splitbilSeviceImpl.readUploads(app)
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	ctx, cancel := limits.RequestContext(app)
+	defer cancel()
+	result, err := splitbilSeviceImpl.extractUploads(ctx, uploads, func(event models.ProgressEvent) {})
+	splitbilSeviceImpl.notifyExtraction(target, "", result, err)
+	return result, err
+}
+
+// extractUploads memproses file yang sudah dibaca dari request. Dipakai langsung oleh request
+// sinkron, stream SSE maupun worker job async; report menerima setiap event progress dan ctx
+// diteruskan ke extractor. Semua panggilan model satu ekstraksi memakai satu slot limiter.
+func (splitbilSeviceImpl *SplibillServiceImpl) extractUploads(ctx context.Context, uploads []models.UploadedFile, report progressFunc) (models.SplitbillResponse, error) {
+	ctx, releaseSlot := limits.WithExtractionSlot(ctx)
+	defer releaseSlot()
+	bucketInterface, err := newBucket()
+	if err != nil {
+		return models.SplitbillResponse{}, err
 	}
 
-	var uploadedImage = files.UploadFileImpl{}
-	if os.Getenv("BUCKET_STORAGE") == "VM" {
-		bucketInterface = new(buckets.VM)
-	} else if os.Getenv("BUCKET_STORAGE") == "FIREBASE" {
-		bucketInterface = new(buckets.Firebase)
-	} else {
-		return nil, errors.New("sorry bucket storage not found,please setup your bucket")
+	report(models.ProgressEvent{Event: models.ProgressEventUploaded, Progress: 10})
+	if len(uploads) > 1 {
+		return splitbilSeviceImpl.splitbilSections(ctx, uploads, bucketInterface, report)
+	}
+	upload := uploads[0]
+	if files.IsPDF(upload.Data) {
+		return splitbilSeviceImpl.splitbilPDF(ctx, upload, bucketInterface, report)
 	}
 
-	uploadedImageURL, err := uploadedImage.UploadImage(app, fileheader, bucketInterface)
+	// Gambar dipreprocessing; hasilnya yang disimpan dan dikirim ke Gemini
+	prepared, err := splitbilSeviceImpl.prepareImage(upload)
 	if err != nil {
-		// Ini akan mencetak error yang dikembalikan oleh files.UploadImage
-		config.GeneralLogger.Printf("Failed to upload image to Firebase Storage: %v\n", err.Error())
-		return nil, errors.New(fmt.Sprintf("Error uploading image to Firebase Storage: %v", err.Error()))
+		return models.SplitbillResponse{}, err
 	}
-	// return nil, errors.New("testing")
-	// config.GeneralLogger.Println("Uploaded Image URL:", uploadedImageURL) // Ini harusnya tidak kosong jika tidak ada error
+	imgData, mimeType := prepared.Data, prepared.MIMEType
 
-	// --- Perubahan besar di sini: Cara mendapatkan data gambar untuk Gemini ---
-	file, err := fileheader.Open()
+	var detected []models.SplitbillResponse
+	sources := []sourceFile{{Filename: imageFilename(upload.Filename, mimeType), Data: imgData, ContentType: mimeType}}
+	report(models.ProgressEvent{Event: models.ProgressEventPreprocessed, Progress: 30})
+	stored, err := splitbilSeviceImpl.storeWhileExtracting(ctx, bucketInterface, sources, report, func(ctx context.Context) error {
+		var err error
+		detected, err = splitbilSeviceImpl.extractCached(models.ReceiptSourceImage, [][]byte{imgData}, func() ([]models.SplitbillResponse, error) {
+			classification, err := splitbilSeviceImpl.classifyImage(ctx, imgData, mimeType)
+			if err != nil {
+				return nil, err
+			}
+			extracted, err := splitbilSeviceImpl.Extractor.ExtractReceiptsFromImage(ctx, imgData, mimeType)
+			if err != nil && !partialExtraction(err) {
+				return nil, err
+			}
+			for i := range extracted {
+				extracted[i].Classification = classification
+			}
+			return extracted, err
+		})
+		return err
+	})
 	if err != nil {
-		config.GeneralLogger.Printf("Error opening file for Gemini: %v\n", err.Error()) // Log lebih spesifik
-		return nil, errors.New(fmt.Sprintf("Error opening file for Gemini: %v", err.Error()))
+		return models.SplitbillResponse{}, err
 	}
-	defer file.Close()
+	if stored.URLs[0] != "" {
+		config.GeneralLogger.Println("Uploaded Image URL:", stored.URLs[0]) // Log URL gambar yang diunggah
+	}
+	for i := range detected {
+		detected[i].Quality = prepared.Quality
+		detected[i].PreprocessingSteps = prepared.Steps
+		detected[i] = splitbilSeviceImpl.withImageStatus(detected[i], stored)
+	}
+	saved, err := splitbilSeviceImpl.saveReceipts(detected, models.ReceiptSourceImage, stored.URLs, withImageHash(imageHash(imgData)))
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	reportValidated(report, saved)
+	return splitbilSeviceImpl.spoolFailed(saved, sources, stored), nil
+}
 
-	imgData, err := ioutil.ReadAll(file)
+// splitbilSections memproses struk panjang yang difoto dalam beberapa bagian berurutan. Semua foto
+// disimpan ke bucket dan diekstrak bersama menjadi satu struk dalam satu receipt record.
+func (splitbilSeviceImpl *SplibillServiceImpl) splitbilSections(ctx context.Context, uploads []models.UploadedFile, bucketInterface buckets.BucketInterface, report progressFunc) (models.SplitbillResponse, error) {
+	sectionImages := make([]extractors.ImageInput, 0, len(uploads))
+	for i, upload := range uploads {
+		prepared, err := splitbilSeviceImpl.prepareImage(upload)
+		if err != nil {
+			return models.SplitbillResponse{}, sectionError(i, err)
+		}
+		sectionImages = append(sectionImages, extractors.ImageInput{Data: prepared.Data, MIMEType: prepared.MIMEType})
+	}
+
+	// Semua bagian diunggah bersamaan; urutan URL tetap sama dengan urutan bagian
+	sources := make([]sourceFile, len(sectionImages))
+	for i, section := range sectionImages {
+		sources[i] = sourceFile{Filename: imageFilename(uploads[i].Filename, section.MIMEType), Data: section.Data, ContentType: section.MIMEType}
+	}
+	var receipt models.SplitbillResponse
+	sectionData := make([][]byte, len(sectionImages))
+	for i, section := range sectionImages {
+		sectionData[i] = section.Data
+	}
+	report(models.ProgressEvent{Event: models.ProgressEventPreprocessed, Progress: 30})
+	stored, err := splitbilSeviceImpl.storeWhileExtracting(ctx, bucketInterface, sources, report, func(ctx context.Context) error {
+		extracted, err := splitbilSeviceImpl.extractCached(models.ReceiptSourceSections, sectionData, func() ([]models.SplitbillResponse, error) {
+			// Cukup bagian pertama yang diklasifikasikan; bagian tengah struk panjang sering hanya berisi item
+			classification, err := splitbilSeviceImpl.classifyImage(ctx, sectionImages[0].Data, sectionImages[0].MIMEType)
+			if err != nil {
+				return nil, err
+			}
+			receipt, err := splitbilSeviceImpl.Extractor.ExtractFromImages(ctx, sectionImages)
+			if err != nil && !partialExtraction(err) {
+				return nil, err
+			}
+			receipt.Classification = classification
+			return []models.SplitbillResponse{receipt}, err
+		})
+		if err != nil {
+			return err
+		}
+		receipt = extracted[0]
+		return nil
+	})
 	if err != nil {
-		config.GeneralLogger.Printf("Failed to read image data for Gemini: %v\n", err.Error()) // Log lebih spesifik
-		return nil, errors.New(fmt.Sprintf("Failed to read image data for Gemini: %v", err.Error()))
+		return models.SplitbillResponse{}, err
 	}
-	// --- Akhir perubahan besar untuk Gemini ---
+	saved, err := splitbilSeviceImpl.saveReceiptRecord(splitbilSeviceImpl.withImageStatus(receipt, stored), models.ReceiptSourceSections, stored.URLs, withImageHash(imageHash(sectionImages[0].Data)))
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	reportValidated(report, saved)
+	return splitbilSeviceImpl.spoolFailed(saved, sources, stored), nil
+}
+
+// splitbilPDF menyimpan PDF struk/invoice ke bucket lalu mengirimnya utuh ke extractor sebagai input
+// native, sehingga semua halaman digabung menjadi satu struk.
+func (splitbilSeviceImpl *SplibillServiceImpl) splitbilPDF(ctx context.Context, upload models.UploadedFile, bucketInterface buckets.BucketInterface, report progressFunc) (models.SplitbillResponse, error) {
+	pdfData := upload.Data
+	pages := files.CountPDFPages(pdfData)
+	if pages == 0 {
+		return models.SplitbillResponse{}, errors.New("PDF page count could not be determined")
+	}
+	if maxPages := maxPDFPages(); pages > maxPages {
+		return models.SplitbillResponse{}, errors.New(fmt.Sprintf("PDF has %d pages, maximum is %d", pages, maxPages))
+	}
+	config.GeneralLogger.Printf("Processing PDF receipt with %d page(s)\n", pages)
 
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  os.Getenv("GEMINI_API_KEY"),
-		Backend: genai.BackendGeminiAPI,
+	var receipt models.SplitbillResponse
+	sources := []sourceFile{{Filename: files.SafeFilename(upload.Filename), Data: pdfData, ContentType: files.PDFMimeType, Document: true}}
+	stored, err := splitbilSeviceImpl.storeWhileExtracting(ctx, bucketInterface, sources, report, func(ctx context.Context) error {
+		extracted, err := splitbilSeviceImpl.extractCached(models.ReceiptSourcePDF, [][]byte{pdfData}, func() ([]models.SplitbillResponse, error) {
+			receipt, err := splitbilSeviceImpl.Extractor.ExtractFromImage(ctx, pdfData, files.PDFMimeType)
+			if err != nil && !partialExtraction(err) {
+				return nil, err
+			}
+			return []models.SplitbillResponse{receipt}, err
+		})
+		if err != nil {
+			return err
+		}
+		receipt = extracted[0]
+		return nil
 	})
 	if err != nil {
-		config.GeneralLogger.Printf("Failed to create Gemini client: %v\n", err.Error()) // Log lebih spesifik
-		return nil, errors.New(fmt.Sprintf("Failed to create client: %v", err.Error()))
-	}
-	// config.GeneralLogger.Println(app.Query("image")) // Ini tidak relevan lagi
-
-	config.GeneralLogger.Println("Uploaded Image URL:", uploadedImageURL) // Log URL gambar yang diunggah
-	// config.GeneralLogger.Println(imagePath) // Ini tidak relevan lagi
-
-	prompt := `Tolong lakukan Optical Character Recognition (OCR) pada gambar struk ini dan ekstrak informasi belanja. Kembalikan hasilnya dalam format JSON dengan struktur berikut:
-{
-  "items": [
-    {
-      "name": "[Nama Barang 1]",
-      "price": "[Harga per Unit 1] - Jika tidak tersedia secara eksplisit sebagai kolom terpisah, hitung sebagai [Total Harga Item 1] dibagi [Kuantitas 1]. Jika pembagian menghasilkan angka tidak terbatas (misalnya, total 0 dan kuantitas 0), gunakan 0.",
-      "quantity": "[Kuantitas 1]",
-      "total": "[Total Harga Item 1]"
-    },
-    {
-      "name": "[Nama Barang 2]",
-      "price": "[Harga per Unit 2] - Jika tidak tersedia secara eksplisit sebagai kolom terpisah, hitung sebagai [Total Harga Item 2] dibagi [Kuantitas 2]. Jika pembagian menghasilkan angka tidak terbatas (misalnya, total 0 dan kuantitas 0), gunakan 0.",
-      "quantity": "[Kuantitas 2]",
-      "total": "[Total Harga Item 2]"
-    }
-    // ... (dan seterusnya untuk semua item)
-  ],
-  "store_information": {
-    "address": "[Alamat Toko]",
-    "email": "[Email Toko]",
-    "npwp": "[NPWP Toko]",
-    "phone_number": "[Nomor Telepon Toko]",
-    "store_name": "[Nama Toko]"
-  },
-  "totals": {
-    "change": "[Uang Kembali]",
-    "discount": "[Nilai Diskon/Nilai Yang Dikurangi]. Kembalikan angka desimal tanpa pengurangan. Jika tidak ada diskon, gunakan 0.",
-    "payment": "[Jumlah Pembayaran]",
-    "subtotal": "[Subtotal]",
-    "tax": {
-      "amount": "[Nilai Pajak]",
-      "service_charge": "[Biaya Layanan]",
-      "dpp": "[Dasar Pengenaan Pajak]",
-      "name": "[Nama Pajak]",
-      "total_tax": "[Total Pajak dari service_charge + amount]"
-    },
-    "total": "[Total Belanja]"
-  },
-  "transaction_information": {
-    "date": "[Tanggal Transaksi] dalam format DD/MM/YYYY",
-    "time": "[Waktu Transaksi] dalam format HH:MM",
-    "transaction_id": "[ID Transaksi]"
-  }
-}
-
-Pastikan semua nilai diisi sesuai dengan informasi yang tertera pada struk. Jika suatu informasi tidak ditemukan, gunakan nilai null atau string kosong untuk field yang sesuai. Untuk nilai numerik (harga, kuantitas, total, totals, discount, dll.), kembalikan dalam format desimal tanpa pemisah ribuan (misalnya, "220000.00" bukan "220,000.00").`
-
-	parts := []*genai.Part{
-		genai.NewPartFromText(prompt),
-		&genai.Part{
-			InlineData: &genai.Blob{
-				MIMEType: fileheader.Header.Get("Content-Type"), // Gunakan Content-Type asli dari file header
-				Data:     imgData,                               // Menggunakan imgData yang dibaca dari fileheader
-			},
-		},
-	}
-
-	contents := []*genai.Content{
-		genai.NewContentFromParts(parts, genai.RoleUser),
-	}
-
-	result, err := client.Models.GenerateContent(
-		ctx,
-		"gemini-2.0-flash",
-		contents,
-		nil,
-	)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Failed to generate content: %v", err.Error()))
-	}
-
-	responseText := result.Text()
-	config.GeneralLogger.Println("Raw response from Gemini:")
-	config.GeneralLogger.Println(responseText)
-
-	// Bersihkan string dari karakter di luar JSON valid
-	cleanedJSON := strings.TrimSpace(responseText)
-	if strings.HasPrefix(cleanedJSON, "```json") {
-		cleanedJSON = cleanedJSON[len("```json"):]
-	}
-	if strings.HasSuffix(cleanedJSON, "```") {
-		cleanedJSON = cleanedJSON[:len(cleanedJSON)-len("```")]
-	}
-	cleanedJSON = strings.TrimSpace(cleanedJSON)
-
-	// Coba parse cleanedJSON sebagai JSON
-	var jsonData map[string]interface{}
-	err = json.Unmarshal([]byte(cleanedJSON), &jsonData)
-	if err != nil {
-		config.GeneralLogger.Println("\nFailed to unmarshal JSON after cleaning:")
-		return nil, errors.New(fmt.Sprintf("Failed to unmarshal JSON after cleaning: %v", err.Error()))
-	} else {
-		config.GeneralLogger.Println("\nSuccessfully unmarshaled JSON after cleaning:")
-		if items, ok := jsonData["items"].([]interface{}); ok {
-			config.GeneralLogger.Printf("Number of items: %d\n", len(items))
-			if len(items) > 0 {
-				if firstItem, ok := items[0].(map[string]interface{}); ok {
-					config.GeneralLogger.Printf("First item name: %v\n", firstItem["name"])
-					config.GeneralLogger.Printf("First item price: %v\n", firstItem["price"])
-				}
+		return models.SplitbillResponse{}, err
+	}
+	saved, err := splitbilSeviceImpl.saveReceipt(splitbilSeviceImpl.withImageStatus(receipt, stored), models.ReceiptSourcePDF, stored.URLs...)
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	reportValidated(report, saved)
+	return splitbilSeviceImpl.spoolFailed(saved, sources, stored), nil
+}
+
+// preparedImage adalah gambar yang sudah lolos pemeriksaan kualitas dan selesai dipreprocessing
+type preparedImage struct {
+	Data     []byte
+	MIMEType string
+	Steps    []string
+	Quality  *models.ImageQuality
+}
+
+// prepareImage memeriksa gambar yang sudah dibaca, mengenali formatnya dari magic bytes (HEIC/AVIF
+// di-transcode jika transcoder tersedia), menolak foto yang buram, salah exposure atau terlalu
+// kecil, lalu menjalankan preprocessing. Jika gambar tidak bisa didekode, gambar asli tetap dipakai.
+func (splitbilSeviceImpl *SplibillServiceImpl) prepareImage(upload models.UploadedFile) (preparedImage, error) {
+	var err error
+	imgData := upload.Data
+	// Ukuran dimensi dan isi file diperiksa dari header sebelum gambar didekode penuh
+	if splitbilSeviceImpl.UploadGuard != nil {
+		if err := splitbilSeviceImpl.UploadGuard.Inspect(imgData, upload.ContentType, upload.Filename); err != nil {
+			config.GeneralLogger.Printf("Image upload rejected: %v\n", err.Error())
+			return preparedImage{}, uploadError(err)
+		}
+	}
+
+	// Format ditentukan dari isi file, bukan dari Content-Type yang dikirim client
+	normalized := images.Normalized{Data: imgData, MIMEType: upload.ContentType, Decodable: true}
+	if splitbilSeviceImpl.Normalizer != nil {
+		normalized, err = splitbilSeviceImpl.Normalizer.Normalize(imgData)
+		if err != nil {
+			config.GeneralLogger.Printf("Unsupported image upload: %v\n", err.Error())
+			return preparedImage{}, uploadError(err)
+		}
+	}
+	prepared := preparedImage{Data: normalized.Data, MIMEType: normalized.MIMEType}
+	if !normalized.Decodable {
+		// HEIC tanpa transcoder dikirim apa adanya ke Gemini, tanpa pemeriksaan kualitas dan preprocessing
+		return prepared, nil
+	}
+
+	if splitbilSeviceImpl.QualityChecker != nil {
+		quality, err := splitbilSeviceImpl.QualityChecker.Check(prepared.Data)
+		if err != nil {
+			config.GeneralLogger.Printf("Image quality check skipped: %v\n", err.Error())
+		} else {
+			if !quality.Passed {
+				config.GeneralLogger.Printf("Image rejected by quality check: %v\n", quality.Issues)
+				return preparedImage{}, helpers.NewApiError(fiber.StatusNotAcceptable, quality.Issues[0],
+					fmt.Sprintf("photo quality is too low (%s), please retake the photo", strings.Join(quality.Issues, ", ")), quality)
 			}
+			prepared.Quality = &quality
+		}
+	}
+
+	if splitbilSeviceImpl.Preprocessor == nil {
+		return prepared, nil
+	}
+	processed, err := splitbilSeviceImpl.Preprocessor.Process(prepared.Data, prepared.MIMEType)
+	if err != nil {
+		config.GeneralLogger.Printf("Image preprocessing skipped: %v\n", err.Error())
+		return prepared, nil
+	}
+	prepared.Data, prepared.MIMEType, prepared.Steps = processed.Data, processed.MIMEType, processed.Steps
+	return prepared, nil
+}
+
+// readUploads membaca semua file yang diunggah ke memori selama request masih berjalan, sehingga
+// pemrosesannya tidak bergantung lagi pada request (misalnya di worker job async). Ukuran file dan
+// jumlah bagian struk panjang sudah diperiksa di sini.
+func (splitbilSeviceImpl *SplibillServiceImpl) readUploads(app *fiber.Ctx) ([]models.UploadedFile, error) {
+	fileheaders, err := formFiles(app)
+	if err != nil {
+		config.GeneralLogger.Printf("Error retrieving file from form: %v\n", err.Error()) // Log lebih spesifik
+		return nil, errors.New(fmt.Sprintf("Error retrieving file: %v", err.Error()))
+	}
+	if maxSections := maxReceiptSections(); len(fileheaders) > maxSections {
+		return nil, errors.New(fmt.Sprintf("too many receipt sections: %d, maximum is %d", len(fileheaders), maxSections))
+	}
+
+	// File hanya dibaca sekali; jenisnya (PDF atau gambar) ditentukan dari bytes yang sama
+	uploads := make([]models.UploadedFile, 0, len(fileheaders))
+	for i, fileheader := range fileheaders {
+		data, err := splitbilSeviceImpl.readUpload(fileheader)
+		if err != nil {
+			config.GeneralLogger.Printf("Error reading uploaded file: %v\n", err.Error())
+			if len(fileheaders) > 1 {
+				return nil, sectionError(i, err)
+			}
+			return nil, err
+		}
+		if len(fileheaders) > 1 && files.IsPDF(data) {
+			return nil, errors.New("PDF files cannot be combined with other receipt sections")
+		}
+		uploads = append(uploads, models.UploadedFile{
+			Filename:    fileheader.Filename,
+			ContentType: fileheader.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+	return uploads, nil
+}
+
+// readUpload membaca file yang diunggah dengan batas ukuran UploadGuard
+func (splitbilSeviceImpl *SplibillServiceImpl) readUpload(fileheader *multipart.FileHeader) ([]byte, error) {
+	if splitbilSeviceImpl.UploadGuard == nil {
+		file, err := fileheader.Open()
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Error opening uploaded file: %v", err.Error()))
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+	data, err := splitbilSeviceImpl.UploadGuard.Read(fileheader)
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	return data, nil
+}
+
+// sectionError menandai error dengan nomor bagian struk panjang
+func sectionError(index int, err error) error {
+	var apiError *helpers.ApiError
+	if errors.As(err, &apiError) {
+		apiError.Message = fmt.Sprintf("section %d: %s", index+1, apiError.Message)
+		return apiError
+	}
+	return errors.New(fmt.Sprintf("Error reading section %d: %v", index+1, err.Error()))
+}
+
+// uploadError memetakan error dari UploadGuard dan Normalizer ke ApiError dengan code masing-masing
+func uploadError(err error) error {
+	switch {
+	case errors.Is(err, images.ErrFileTooLarge):
+		return helpers.NewApiError(fiber.StatusRequestEntityTooLarge, helpers.ErrCodeFileTooLarge, err.Error(), nil)
+	case errors.Is(err, images.ErrDimensionsTooLarge):
+		return helpers.NewApiError(fiber.StatusRequestEntityTooLarge, helpers.ErrCodeDimensionsTooBig, err.Error(), nil)
+	case errors.Is(err, images.ErrTooManyPixels):
+		return helpers.NewApiError(fiber.StatusRequestEntityTooLarge, helpers.ErrCodeTooManyPixels, err.Error(), nil)
+	case errors.Is(err, images.ErrMediaTypeMismatch):
+		return helpers.NewApiError(fiber.StatusUnsupportedMediaType, helpers.ErrCodeTypeMismatch, err.Error(), nil)
+	case errors.Is(err, images.ErrPolyglotFile):
+		return helpers.NewApiError(fiber.StatusUnsupportedMediaType, helpers.ErrCodePolyglotFile, err.Error(), nil)
+	case errors.Is(err, images.ErrUnsupportedMedia):
+		return helpers.NewApiError(fiber.StatusUnsupportedMediaType, helpers.ErrCodeUnsupportedMedia,
+			fmt.Sprintf("%v, supported formats are JPEG, PNG, WebP, HEIC/HEIF and AVIF", err.Error()), nil)
+	}
+	return err
+}
+
+// imageFilename menyesuaikan ekstensi nama file dengan format gambar yang sebenarnya, baik hasil
+// image pipeline maupun hasil deteksi magic bytes
+func imageFilename(filename string, mimeType string) string {
+	switch mimeType {
+	case images.MIMETypeJPEG:
+		return files.ReplaceExtension(filename, ".jpg")
+	case images.MIMETypePNG:
+		return files.ReplaceExtension(filename, ".png")
+	case images.MIMETypeWebP:
+		return files.ReplaceExtension(filename, ".webp")
+	case images.MIMETypeHEIC, images.MIMETypeHEIF:
+		return files.ReplaceExtension(filename, ".heic")
+	}
+	return filename
+}
+
+// classifyImage menolak gambar yang jelas bukan struk atau invoice sebelum prompt ekstraksi lengkap
+// dijalankan. Label lain hanya ditolak jika confidence mencapai CLASSIFICATION_MIN_CONFIDENCE;
+// kegagalan classifier tidak menghentikan proses, kecuali penolakan antrean model (429/503) karena
+// ekstraksi juga akan ditolak.
+func (splitbilSeviceImpl *SplibillServiceImpl) classifyImage(ctx context.Context, imgData []byte, mimeType string) (*models.DocumentClass, error) {
+	if splitbilSeviceImpl.Classifier == nil || os.Getenv("CLASSIFICATION_ENABLED") == "false" {
+		return nil, nil
+	}
+
+	classification, err := splitbilSeviceImpl.Classifier.Classify(ctx, imgData, mimeType)
+	var apiError *helpers.ApiError
+	if errors.As(err, &apiError) {
+		return nil, err
+	}
+	if err != nil {
+		config.GeneralLogger.Printf("Image classification failed, continuing with extraction: %v\n", err.Error())
+		return nil, nil
+	}
+	config.GeneralLogger.Printf("Image classified as %s (confidence %.2f)\n", classification.Label, classification.Confidence)
+
+	if classification.Confidence < minClassificationConfidence() {
+		return &classification, nil
+	}
+	switch classification.Label {
+	case models.DocumentClassMenu, models.DocumentClassNonDocument:
+		return nil, helpers.NewApiError(fiber.StatusNotAcceptable, helpers.ErrCodeNotAReceipt,
+			fmt.Sprintf("uploaded image is not a receipt (detected: %s)", classification.Label), classification)
+	case models.DocumentClassUnreadable:
+		return nil, helpers.NewApiError(fiber.StatusNotAcceptable, helpers.ErrCodeUnreadableImage,
+			"receipt image is unreadable, please retake the photo", classification)
+	}
+	return &classification, nil
+}
+
+func minClassificationConfidence() float64 {
+	minConfidence, err := strconv.ParseFloat(os.Getenv("CLASSIFICATION_MIN_CONFIDENCE"), 64)
+	if err != nil || minConfidence < 0 {
+		return 0.6
+	}
+	return minConfidence
+}
+
+func lowConfidenceThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("CONFIDENCE_LOW_THRESHOLD"), 64)
+	if err != nil || threshold < 0 {
+		return 0.7
+	}
+	return threshold
+}
+
+func maxPDFPages() int {
+	maxPages, err := strconv.Atoi(os.Getenv("PDF_MAX_PAGES"))
+	if err != nil || maxPages <= 0 {
+		return 20
+	}
+	return maxPages
+}
+
+// SplitbilText mengekstrak informasi splitbill dari teks struk (hasil OCR, ringkasan pesanan GoFood/GrabFood,
+// notifikasi bank, output POS) maupun HTML e-receipt. Sumber asli disimpan ke bucket bersamaan dengan
+// ekstraksi seperti gambar struk, termasuk spool saat bucket tidak tersedia, cache hasil ekstraksi dan
+// webhook callback.
+func (splitbilSeviceImpl *SplibillServiceImpl) SplitbilText(app *fiber.Ctx) (models.SplitbillResponse, error) {
+	var request models.TextReceiptRequest
+	switch {
+	case strings.HasPrefix(app.Get(fiber.HeaderContentType), fiber.MIMETextHTML):
+		request.HTML = string(app.Body())
+	case strings.HasPrefix(app.Get(fiber.HeaderContentType), fiber.MIMETextPlain):
+		request.Text = string(app.Body())
+	default:
+		if err := app.BodyParser(&request); err != nil {
+			config.GeneralLogger.Printf("Error parsing text receipt request: %v\n", err.Error())
+			return models.SplitbillResponse{}, errors.New(fmt.Sprintf("Error parsing request: %v", err.Error()))
 		}
-		if storeInfo, ok := jsonData["store_information"].(map[string]interface{}); ok {
-			config.GeneralLogger.Printf("Store Name: %v\n", storeInfo["store_name"])
-			config.GeneralLogger.Printf("Store Address: %v\n", storeInfo["address"])
+	}
+	if request.HTML == "" && receipts.LooksLikeHTML(request.Text) {
+		request.HTML, request.Text = request.Text, ""
+	}
+
+	source := sourceFile{Filename: "receipt.txt", Data: []byte(request.Text), ContentType: "text/plain; charset=utf-8", Document: true}
+	sourceType, text := models.ReceiptSourceText, request.Text
+	if request.HTML != "" {
+		source = sourceFile{Filename: "receipt.html", Data: []byte(request.HTML), ContentType: "text/html; charset=utf-8", Document: true}
+		sourceType = models.ReceiptSourceHTML
+		converted, err := receipts.HTMLToText(request.HTML)
+		if err != nil {
+			config.GeneralLogger.Printf("Error converting HTML receipt to text: %v\n", err.Error())
+			return models.SplitbillResponse{}, errors.New(fmt.Sprintf("Error reading HTML receipt: %v", err.Error()))
 		}
-		if totals, ok := jsonData["totals"].(map[string]interface{}); ok {
-			config.GeneralLogger.Printf("Total belanja: %v\n", totals["total"])
-			if taxInfo, ok := totals["tax"].(map[string]interface{}); ok {
-				config.GeneralLogger.Printf("Tax Amount: %v\n", taxInfo["amount"])
-				config.GeneralLogger.Printf("Tax Name: %v\n", taxInfo["name"])
+		text = converted
+	}
+	if strings.TrimSpace(text) == "" {
+		return models.SplitbillResponse{}, errors.New("text or html is required")
+	}
+
+	target, err := splitbilSeviceImpl.webhookTarget(app)
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	bucketInterface, err := newBucket()
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	ctx, cancel := limits.RequestContext(app)
+	defer cancel()
+	result, err := splitbilSeviceImpl.splitbilText(ctx, text, source, sourceType, bucketInterface)
+	splitbilSeviceImpl.notifyExtraction(target, "", result, err)
+	return result, err
+}
+
+// splitbilText menyimpan sumber teks/HTML ke bucket sambil mengekstrak text, lalu menyimpan receipt record
+func (splitbilSeviceImpl *SplibillServiceImpl) splitbilText(ctx context.Context, text string, source sourceFile, sourceType string, bucketInterface buckets.BucketInterface) (models.SplitbillResponse, error) {
+	ctx, releaseSlot := limits.WithExtractionSlot(ctx)
+	defer releaseSlot()
+	var receipt models.SplitbillResponse
+	sources := []sourceFile{source}
+	report := func(event models.ProgressEvent) {}
+	stored, err := splitbilSeviceImpl.storeWhileExtracting(ctx, bucketInterface, sources, report, func(ctx context.Context) error {
+		extracted, err := splitbilSeviceImpl.extractCached(sourceType, [][]byte{[]byte(text)}, func() ([]models.SplitbillResponse, error) {
+			receipt, err := splitbilSeviceImpl.Extractor.ExtractFromText(ctx, text)
+			if err != nil && !partialExtraction(err) {
+				return nil, err
 			}
+			return []models.SplitbillResponse{receipt}, err
+		})
+		if err != nil {
+			return err
 		}
-		if transactionInfo, ok := jsonData["transaction_information"].(map[string]interface{}); ok {
-			config.GeneralLogger.Printf("Transaction Date: %v\n", transactionInfo["date"])
-			config.GeneralLogger.Printf("Transaction ID: %v\n", transactionInfo["transaction_id"])
+		receipt = extracted[0]
+		return nil
+	})
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	saved, err := splitbilSeviceImpl.saveReceipt(splitbilSeviceImpl.withImageStatus(receipt, stored), sourceType, stored.URLs...)
+	if err != nil {
+		return models.SplitbillResponse{}, err
+	}
+	return splitbilSeviceImpl.spoolFailed(saved, sources, stored), nil
+}
+
+// GetReceipt mengembalikan receipt record beserta semua file sumbernya
+func (splitbilSeviceImpl *SplibillServiceImpl) GetReceipt(app *fiber.Ctx) (models.Receipt, error) {
+	receipt, err := splitbilSeviceImpl.ReceiptRepository.FindByID(app.Params("id"))
+	if err != nil {
+		return models.Receipt{}, errors.New(fmt.Sprintf("Error retrieving receipt: %v", err.Error()))
+	}
+	return receipt, nil
+}
+
+// saveReceipt melengkapi hasil ekstraksi dengan hasil validasi aritmetika lalu menyimpannya sebagai
+// receipt record, sama untuk input gambar, PDF maupun teks.
+func (splitbilSeviceImpl *SplibillServiceImpl) saveReceipt(receipt models.SplitbillResponse, sourceType string, sourceURLs ...string) (models.SplitbillResponse, error) {
+	return splitbilSeviceImpl.saveReceiptRecord(receipt, sourceType, sourceURLs)
+}
+
+// saveReceiptRecord adalah implementasi saveReceipt; options dapat mengisi field tambahan pada
+// receipt record sebelum disimpan. Field dengan confidence rendah dicatat di LowConfidenceFields,
+// receipt yang gagal validasi atau meragukan masuk antrean review, dan receipt yang kemungkinan
+// duplikat diberi DuplicateWarning.
+func (splitbilSeviceImpl *SplibillServiceImpl) saveReceiptRecord(receipt models.SplitbillResponse, sourceType string, sourceURLs []string, options ...func(record *models.Receipt)) (models.SplitbillResponse, error) {
+	validation := receipts.Validate(receipt)
+	if !validation.Valid {
+		config.GeneralLogger.Printf("Receipt validation issues: %v\n", validation.Issues)
+	}
+	if len(sourceURLs) > 0 {
+		receipt.SourceURL = sourceURLs[0]
+	}
+	receipt.Validation = &validation
+	receipt.LowConfidenceFields = receipts.LowConfidenceFields(receipt, lowConfidenceThreshold())
+	receipt.Review = reviewFor(receipt)
+
+	record := models.Receipt{
+		SourceType: sourceType,
+		SourceURLs: sourceURLs,
+		Data:       receipt,
+	}
+	for _, option := range options {
+		option(&record)
+	}
+	record.Data.DuplicateWarning = splitbilSeviceImpl.findDuplicate(record)
+	if err := splitbilSeviceImpl.ReceiptRepository.Save(&record); err != nil {
+		config.GeneralLogger.Printf("Failed to save receipt: %v\n", err.Error())
+		return models.SplitbillResponse{}, errors.New(fmt.Sprintf("Error saving receipt: %v", err.Error()))
+	}
+	return record.Data, nil
+}
+
+// saveReceipts menyimpan setiap struk yang terdeteksi dalam satu foto sebagai receipt record
+// terpisah yang terhubung ke gambar sumber yang sama melalui SourceGroupID. Respons berisi struk
+// pertama dengan semua struk di field Receipts.
+func (splitbilSeviceImpl *SplibillServiceImpl) saveReceipts(detected []models.SplitbillResponse, sourceType string, sourceURLs []string, options ...func(record *models.Receipt)) (models.SplitbillResponse, error) {
+	if len(detected) == 0 {
+		return models.SplitbillResponse{}, errors.New("no receipt found in image")
+	}
+	if len(detected) == 1 {
+		return splitbilSeviceImpl.saveReceiptRecord(detected[0], sourceType, sourceURLs, options...)
+	}
+
+	config.GeneralLogger.Printf("Saving %d receipts detected in one image\n", len(detected))
+	groupID := uuid.NewString()
+	saved := make([]models.SplitbillResponse, 0, len(detected))
+	for i, receipt := range detected {
+		record, err := splitbilSeviceImpl.saveReceiptRecord(receipt, sourceType, sourceURLs, append(options, func(record *models.Receipt) {
+			record.SourceGroupID = groupID
+			record.SourceIndex = i
+		})...)
+		if err != nil {
+			return models.SplitbillResponse{}, err
 		}
+		saved = append(saved, record)
+	}
+
+	response := saved[0]
+	response.Receipts = saved
+	return response, nil
+}
+
+// formFiles mengambil file yang diunggah secara berurutan dari field "images", "image" atau "file"
+func formFiles(app *fiber.Ctx) ([]*multipart.FileHeader, error) {
+	form, err := app.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"images", "image", "file"} {
+		if fileheaders := form.File[field]; len(fileheaders) > 0 {
+			return fileheaders, nil
+		}
+	}
+	return nil, errors.New("there is no uploaded file associated with the given key")
+}
+
+func maxReceiptSections() int {
+	maxSections, err := strconv.Atoi(os.Getenv("MAX_RECEIPT_SECTIONS"))
+	if err != nil || maxSections <= 0 {
+		return 10
+	}
+	return maxSections
+}
+
+func newBucket() (buckets.BucketInterface, error) {
+	if os.Getenv("BUCKET_STORAGE") == "VM" {
+		return new(buckets.VM), nil
+	} else if os.Getenv("BUCKET_STORAGE") == "FIREBASE" {
+		return new(buckets.Firebase), nil
 	}
-	return jsonData, nil
+	return nil, errors.New("sorry bucket storage not found,please setup your bucket")
 }
@@ -0,0 +1,135 @@
+package splitbillservices
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+	"github.com/sirupsen/logrus"
+)
+
+func quietLogger() {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+}
+
+func detectedReceipt(storeName string) models.SplitbillResponse {
+	var receipt models.SplitbillResponse
+	receipt.StoreInformation.StoreName = storeName
+	return receipt
+}
+
+// TestSaveReceipts memastikan setiap struk dalam satu foto disimpan sebagai record terpisah yang
+// terhubung lewat SourceGroupID
+func TestSaveReceipts(t *testing.T) {
+	quietLogger()
+	repository := receiptrepositories.NewReceiptRepositoryImpl(documents.NewFile(t.TempDir()))
+	service := &SplibillServiceImpl{ReceiptRepository: repository}
+
+	saved, err := service.saveReceipts([]models.SplitbillResponse{detectedReceipt("Toko A"), detectedReceipt("Toko B")}, "image", []string{"/storage/images/a.jpg"})
+	if err != nil {
+		t.Fatalf("saveReceipts() error = %v", err)
+	}
+	if len(saved.Receipts) != 2 || saved.ReceiptID != saved.Receipts[0].ReceiptID {
+		t.Fatalf("saveReceipts() = %+v, want the first of two receipts", saved)
+	}
+	groupID := ""
+	for i, receipt := range saved.Receipts {
+		record, err := repository.FindByID(receipt.ReceiptID)
+		if err != nil {
+			t.Fatalf("FindByID(%s) error = %v", receipt.ReceiptID, err)
+		}
+		if i == 0 {
+			groupID = record.SourceGroupID
+		}
+		if record.SourceGroupID == "" || record.SourceGroupID != groupID || record.SourceIndex != i {
+			t.Errorf("receipt %d: group %q index %d, want group %q index %d", i, record.SourceGroupID, record.SourceIndex, groupID, i)
+		}
+		if !slices.Equal(record.SourceURLs, []string{"/storage/images/a.jpg"}) {
+			t.Errorf("receipt %d: source URLs = %v", i, record.SourceURLs)
+		}
+	}
+	if saved.Receipts[1].StoreInformation.StoreName != "Toko B" {
+		t.Errorf("second receipt = %q, want Toko B", saved.Receipts[1].StoreInformation.StoreName)
+	}
+
+	single, err := service.saveReceipts([]models.SplitbillResponse{detectedReceipt("Toko C")}, "image", nil)
+	if err != nil {
+		t.Fatalf("saveReceipts() error = %v", err)
+	}
+	record, _ := repository.FindByID(single.ReceiptID)
+	if len(single.Receipts) != 0 || record.SourceGroupID != "" {
+		t.Errorf("single receipt grouped: %+v", record)
+	}
+
+	if _, err := service.saveReceipts(nil, "image", nil); err == nil {
+		t.Errorf("saveReceipts(nil) error = nil, want no receipt found")
+	}
+}
+
+type fakeClassifier struct {
+	class models.DocumentClass
+	err   error
+}
+
+func (classifier fakeClassifier) Classify(ctx context.Context, imageData []byte, mimeType string) (models.DocumentClass, error) {
+	return classifier.class, classifier.err
+}
+
+func TestClassifyImage(t *testing.T) {
+	quietLogger()
+
+	tests := []struct {
+		name    string
+		enabled string
+		class   models.DocumentClass
+		err     error
+		label   string
+		code    string
+	}{
+		{name: "receipt", class: models.DocumentClass{Label: models.DocumentClassReceipt, Confidence: 0.9}, label: models.DocumentClassReceipt},
+		{name: "menu", class: models.DocumentClass{Label: models.DocumentClassMenu, Confidence: 0.9}, code: helpers.ErrCodeNotAReceipt},
+		{name: "non document", class: models.DocumentClass{Label: models.DocumentClassNonDocument, Confidence: 0.6}, code: helpers.ErrCodeNotAReceipt},
+		{name: "unreadable", class: models.DocumentClass{Label: models.DocumentClassUnreadable, Confidence: 0.8}, code: helpers.ErrCodeUnreadableImage},
+		// di bawah CLASSIFICATION_MIN_CONFIDENCE gambar tetap diekstrak
+		{name: "unsure menu", class: models.DocumentClass{Label: models.DocumentClassMenu, Confidence: 0.4}, label: models.DocumentClassMenu},
+		{name: "classifier failure", err: errors.New("model unavailable")},
+		{name: "disabled", enabled: "false", class: models.DocumentClass{Label: models.DocumentClassMenu, Confidence: 0.9}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("CLASSIFICATION_ENABLED", test.enabled)
+			service := &SplibillServiceImpl{Classifier: fakeClassifier{class: test.class, err: test.err}}
+			class, err := service.classifyImage(context.Background(), []byte("image"), "image/jpeg")
+
+			if test.code != "" {
+				var apiError *helpers.ApiError
+				if !errors.As(err, &apiError) || apiError.Code != test.code || apiError.Status != 406 {
+					t.Fatalf("error = %v, want 406 %s", err, test.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if test.label == "" {
+				if class != nil {
+					t.Errorf("class = %+v, want none", class)
+				}
+				return
+			}
+			if class == nil || class.Label != test.label {
+				t.Errorf("class = %+v, want %s", class, test.label)
+			}
+		})
+	}
+}
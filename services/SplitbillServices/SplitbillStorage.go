@@ -0,0 +1,275 @@
+package splitbillservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	files "github.com/arifin2018/splitbill-arifin.git/helpers/files"
+	"github.com/arifin2018/splitbill-arifin.git/helpers/files/buckets"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+)
+
+// sourceFile adalah file sumber struk yang disimpan ke bucket. Document bernilai true untuk file
+// non-gambar (PDF) yang disimpan di folder receipts.
+type sourceFile struct {
+	Filename    string
+	Data        []byte
+	ContentType string
+	Document    bool
+}
+
+// storedFiles adalah hasil upload ke bucket. URLs berurutan sama dengan file sumber; URL file yang
+// gagal diunggah kosong dan indeksnya dicatat di Failed. Error terisi jika upload gagal tetapi
+// request tetap dilanjutkan (STORAGE_NON_BLOCKING atau spool aktif).
+type storedFiles struct {
+	URLs   []string
+	Failed []int
+	Error  string
+}
+
+// storeWhileExtracting mengunggah semua file sumber ke bucket secara bersamaan sambil menjalankan
+// ekstraksi model dengan bytes yang sama. Jika upload gagal dan kegagalan storage tidak boleh
+// diabaikan, ekstraksi dibatalkan dan error keduanya digabung. report menerima event "stored"
+// setelah semua file terunggah, serta event model dan item dari extractor lewat trace.
+func (splitbilSeviceImpl *SplibillServiceImpl) storeWhileExtracting(ctx context.Context, bucketInterface buckets.BucketInterface, sources []sourceFile, report progressFunc, extract func(ctx context.Context) error) (storedFiles, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = extractors.WithTrace(ctx, extractionTrace(report))
+
+	nonBlocking := storageNonBlocking() || splitbilSeviceImpl.spoolEnabled()
+	stored := storedFiles{URLs: make([]string, len(sources))}
+	uploadErrors := make([]error, len(sources))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i, source := range sources {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stored.URLs[i], uploadErrors[i] = uploadSource(source, bucketInterface)
+				if uploadErrors[i] != nil && len(sources) > 1 {
+					uploadErrors[i] = errors.New(fmt.Sprintf("section %d: %v", i+1, uploadErrors[i].Error()))
+				}
+			}()
+		}
+		wg.Wait()
+		if errors.Join(uploadErrors...) == nil {
+			report(models.ProgressEvent{Event: models.ProgressEventStored, ImageURLs: stored.URLs})
+		} else if !nonBlocking {
+			cancel()
+		}
+	}()
+
+	extractErr := extract(ctx)
+	<-done
+
+	storeErr := errors.Join(uploadErrors...)
+	if storeErr == nil {
+		return stored, extractErr
+	}
+	config.GeneralLogger.Printf("Failed to upload file to storage: %v\n", storeErr.Error())
+	storeErr = errors.New(fmt.Sprintf("Error uploading file to storage: %v", storeErr.Error()))
+	if nonBlocking {
+		for i, err := range uploadErrors {
+			if err != nil {
+				stored.Failed = append(stored.Failed, i)
+			}
+		}
+		stored.Error = storeErr.Error()
+		return stored, extractErr
+	}
+	if errors.Is(extractErr, context.Canceled) {
+		// Ekstraksi hanya dibatalkan karena upload gagal
+		extractErr = nil
+	}
+	return storedFiles{}, errors.Join(storeErr, extractErr)
+}
+
+// uploadSource mengunggah satu file sumber ke bucket sesuai jenisnya
+func uploadSource(source sourceFile, bucketInterface buckets.BucketInterface) (string, error) {
+	var uploadedFile = files.UploadFileImpl{}
+	if source.Document {
+		return uploadedFile.UploadDocument(source.Filename, source.Data, source.ContentType, bucketInterface)
+	}
+	return uploadedFile.UploadImageData(source.Filename, source.Data, source.ContentType, bucketInterface)
+}
+
+// withImageStatus mengisi status penyimpanan file sumber pada hasil ekstraksi: stored jika semua
+// file tersimpan di bucket, pending jika file yang gagal akan diunggah ulang dari spool, dan failed
+// jika file yang gagal tidak bisa diunggah ulang.
+func (splitbilSeviceImpl *SplibillServiceImpl) withImageStatus(receipt models.SplitbillResponse, stored storedFiles) models.SplitbillResponse {
+	receipt.StorageError = stored.Error
+	switch {
+	case len(stored.Failed) == 0:
+		receipt.ImageStatus = models.ImageStatusStored
+	case splitbilSeviceImpl.spoolEnabled():
+		receipt.ImageStatus = models.ImageStatusPending
+	default:
+		receipt.ImageStatus = models.ImageStatusFailed
+	}
+	return receipt
+}
+
+// spoolFailed menyimpan file yang gagal diunggah ke spool lokal agar diunggah ulang di background.
+// Jika spool gagal ditulis, status receipt diubah menjadi failed.
+func (splitbilSeviceImpl *SplibillServiceImpl) spoolFailed(saved models.SplitbillResponse, sources []sourceFile, stored storedFiles) models.SplitbillResponse {
+	if len(stored.Failed) == 0 || !splitbilSeviceImpl.spoolEnabled() {
+		return saved
+	}
+	records := []models.SplitbillResponse{saved}
+	if len(saved.Receipts) > 0 {
+		records = saved.Receipts
+	}
+	receiptIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		receiptIDs = append(receiptIDs, record.ReceiptID)
+	}
+
+	for _, index := range stored.Failed {
+		source := sources[index]
+		entry := files.SpoolEntry{
+			Filename:    source.Filename,
+			ContentType: source.ContentType,
+			Document:    source.Document,
+			ReceiptIDs:  receiptIDs,
+			Index:       index,
+		}
+		if err := splitbilSeviceImpl.Spool.Enqueue(&entry, source.Data); err != nil {
+			config.GeneralLogger.Printf("Failed to spool %s for re-upload: %v\n", source.Filename, err.Error())
+			return splitbilSeviceImpl.markImageStatus(saved, receiptIDs, models.ImageStatusFailed)
+		}
+		config.GeneralLogger.Printf("Spooled %s for background re-upload (receipts %v)\n", source.Filename, receiptIDs)
+	}
+	return saved
+}
+
+// markImageStatus mengubah status penyimpanan receipt record yang sudah tersimpan dan respons-nya
+func (splitbilSeviceImpl *SplibillServiceImpl) markImageStatus(saved models.SplitbillResponse, receiptIDs []string, status string) models.SplitbillResponse {
+	for _, receiptID := range receiptIDs {
+		_, err := splitbilSeviceImpl.ReceiptRepository.Update(receiptID, func(record *models.Receipt) error {
+			record.Data.ImageStatus = status
+			return nil
+		})
+		if err != nil {
+			config.GeneralLogger.Printf("Failed to update image status of receipt %s: %v\n", receiptID, err.Error())
+		}
+	}
+	saved.ImageStatus = status
+	for i := range saved.Receipts {
+		saved.Receipts[i].ImageStatus = status
+	}
+	return saved
+}
+
+// uploadSpooled berjalan di background dan mengunggah ulang file di spool setiap STORAGE_SPOOL_INTERVAL.
+// Setelah berhasil, hanya URL dan status penyimpanan di receipt record yang diperbarui (lewat
+// ReceiptRepository.Update, sehingga review, kunci bill atau merge yang disimpan bersamaan tidak
+// tertimpa) dan entry dihapus dari spool. Loop berhenti saat ctx dibatalkan.
+func (splitbilSeviceImpl *SplibillServiceImpl) uploadSpooled(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		entries, err := splitbilSeviceImpl.Spool.Pending()
+		if err != nil {
+			config.GeneralLogger.Printf("Failed to list storage spool: %v\n", err.Error())
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		bucketInterface, err := newBucket()
+		if err != nil {
+			config.GeneralLogger.Printf("Spool re-upload skipped: %v\n", err.Error())
+			continue
+		}
+		for _, entry := range entries {
+			splitbilSeviceImpl.reuploadSpooled(entry, bucketInterface)
+		}
+	}
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) reuploadSpooled(entry files.SpoolEntry, bucketInterface buckets.BucketInterface) {
+	data, err := splitbilSeviceImpl.Spool.Read(entry)
+	if err != nil {
+		config.GeneralLogger.Printf("Failed to read spooled file %s: %v\n", entry.ID, err.Error())
+		return
+	}
+	source := sourceFile{Filename: entry.Filename, Data: data, ContentType: entry.ContentType, Document: entry.Document}
+	sourceURL, err := uploadSource(source, bucketInterface)
+	if err != nil {
+		entry.Attempts++
+		entry.LastError = err.Error()
+		if err := splitbilSeviceImpl.Spool.Update(entry); err != nil {
+			config.GeneralLogger.Printf("Failed to update spool entry %s: %v\n", entry.ID, err.Error())
+		}
+		return
+	}
+
+	for _, receiptID := range entry.ReceiptIDs {
+		_, err := splitbilSeviceImpl.ReceiptRepository.Update(receiptID, func(record *models.Receipt) error {
+			for len(record.SourceURLs) <= entry.Index {
+				record.SourceURLs = append(record.SourceURLs, "")
+			}
+			record.SourceURLs[entry.Index] = sourceURL
+			record.Data.SourceURL = record.SourceURLs[0]
+			if !hasMissingURL(record.SourceURLs) {
+				record.Data.ImageStatus = models.ImageStatusStored
+				record.Data.StorageError = ""
+			}
+			return nil
+		})
+		if errors.Is(err, documents.ErrDocumentNotFound) {
+			config.GeneralLogger.Printf("Spooled file %s uploaded but receipt %s not found: %v\n", entry.ID, receiptID, err.Error())
+			continue
+		}
+		if err != nil {
+			// Entry tetap di spool supaya URL dicoba disimpan lagi di putaran berikutnya
+			config.GeneralLogger.Printf("Failed to update receipt %s with uploaded URL: %v\n", receiptID, err.Error())
+			return
+		}
+	}
+	if err := splitbilSeviceImpl.Spool.Remove(entry); err != nil {
+		config.GeneralLogger.Printf("Failed to remove spool entry %s: %v\n", entry.ID, err.Error())
+	}
+	config.GeneralLogger.Printf("Spooled file %s uploaded after %d failed attempt(s): %s\n", entry.Filename, entry.Attempts, sourceURL)
+}
+
+func hasMissingURL(urls []string) bool {
+	for _, url := range urls {
+		if url == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (splitbilSeviceImpl *SplibillServiceImpl) spoolEnabled() bool {
+	return splitbilSeviceImpl.Spool != nil && splitbilSeviceImpl.Spool.Enabled()
+}
+
+func storageNonBlocking() bool {
+	nonBlocking, err := strconv.ParseBool(os.Getenv("STORAGE_NON_BLOCKING"))
+	return err == nil && nonBlocking
+}
+
+func spoolInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("STORAGE_SPOOL_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return 30 * time.Second
+	}
+	return interval
+}
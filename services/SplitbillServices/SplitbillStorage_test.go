@@ -0,0 +1,157 @@
+package splitbillservices
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	files "github.com/arifin2018/splitbill-arifin.git/helpers/files"
+	bucketmodels "github.com/arifin2018/splitbill-arifin.git/helpers/files/buckets/models"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	receiptrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/ReceiptRepositories"
+)
+
+// fakeBucket mengembalikan /storage/<objectName> dan gagal untuk file yang namanya ada di failing
+type fakeBucket struct {
+	mutex   sync.Mutex
+	failing []string
+	objects []string
+}
+
+func (bucket *fakeBucket) CreateFileStorageAndPublish(objectName string, imageDataReader bucketmodels.ReaderFileHeader) (string, error) {
+	if slices.Contains(bucket.failing, imageDataReader.Fileheader.Filename) {
+		return "", errors.New("bucket unavailable")
+	}
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+	bucket.objects = append(bucket.objects, objectName)
+	return "/storage/" + objectName, nil
+}
+
+func TestStoreWhileExtracting(t *testing.T) {
+	quietLogger()
+	sources := []sourceFile{
+		{Filename: "a.jpg", Data: []byte("a"), ContentType: "image/jpeg"},
+		{Filename: "b.pdf", Data: []byte("b"), ContentType: "application/pdf", Document: true},
+	}
+	tests := []struct {
+		name        string
+		nonBlocking string
+		spool       bool
+		failing     []string
+		urls        []string
+		failed      []int
+		events      []string
+		err         string
+	}{
+		{name: "stored", urls: []string{"/storage/images/", "/storage/receipts/"}, events: []string{models.ProgressEventStored}},
+		{name: "upload failure cancels extraction", failing: []string{"b.pdf"}, err: "Error uploading file to storage: section 2: bucket unavailable"},
+		{name: "non-blocking upload failure", nonBlocking: "true", failing: []string{"b.pdf"}, urls: []string{"/storage/images/", ""}, failed: []int{1}},
+		{name: "spool keeps the extraction", spool: true, failing: []string{"a.jpg"}, urls: []string{"", "/storage/receipts/"}, failed: []int{0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("STORAGE_NON_BLOCKING", test.nonBlocking)
+			service := &SplibillServiceImpl{}
+			if test.spool {
+				service.Spool = &files.Spool{Dir: t.TempDir()}
+			}
+			events := []string{}
+			report := func(event models.ProgressEvent) {
+				events = append(events, event.Event)
+			}
+			stored, err := service.storeWhileExtracting(context.Background(), &fakeBucket{failing: test.failing}, sources, report, func(ctx context.Context) error {
+				if len(test.failing) > 0 && test.nonBlocking == "" && !test.spool {
+					// ekstraksi hanya selesai karena dibatalkan oleh upload yang gagal
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return nil
+			})
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) || errors.Is(err, context.Canceled) {
+					t.Fatalf("error = %v, want only %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if len(stored.URLs) != len(test.urls) {
+				t.Fatalf("URLs = %v, want %v", stored.URLs, test.urls)
+			}
+			for i, url := range stored.URLs {
+				if !strings.HasPrefix(url, test.urls[i]) || (url == "") != (test.urls[i] == "") {
+					t.Errorf("URLs[%d] = %q, want prefix %q", i, url, test.urls[i])
+				}
+			}
+			if !slices.Equal(stored.Failed, test.failed) {
+				t.Errorf("failed = %v, want %v", stored.Failed, test.failed)
+			}
+			if (stored.Error != "") != (len(test.failed) > 0) {
+				t.Errorf("storage error = %q", stored.Error)
+			}
+			if test.events == nil {
+				test.events = []string{}
+			}
+			if !slices.Equal(events, test.events) {
+				t.Errorf("events = %v, want %v", events, test.events)
+			}
+		})
+	}
+}
+
+// TestReuploadSpooled menyimpan section yang gagal diunggah ke spool, lalu mengunggahnya ulang
+// sampai URL di receipt record lengkap
+func TestReuploadSpooled(t *testing.T) {
+	quietLogger()
+	repository := receiptrepositories.NewReceiptRepositoryImpl(documents.NewFile(t.TempDir()))
+	spool := &files.Spool{Dir: t.TempDir()}
+	service := &SplibillServiceImpl{ReceiptRepository: repository, Spool: spool}
+
+	record := models.Receipt{SourceURLs: []string{"/storage/images/a.jpg", ""}}
+	record.Data.ImageStatus = models.ImageStatusPending
+	if err := repository.Save(&record); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	sources := []sourceFile{
+		{Filename: "a.jpg", Data: []byte("a"), ContentType: "image/jpeg"},
+		{Filename: "b.jpg", Data: []byte("b"), ContentType: "image/jpeg"},
+	}
+	service.spoolFailed(models.SplitbillResponse{ReceiptID: record.ID}, sources, storedFiles{URLs: []string{"/storage/images/a.jpg", ""}, Failed: []int{1}})
+
+	entries, err := spool.Pending()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Pending() = %v, %v, want one entry", entries, err)
+	}
+	if entries[0].Index != 1 || !slices.Equal(entries[0].ReceiptIDs, []string{record.ID}) {
+		t.Fatalf("entry = %+v, want section 2 of %s", entries[0], record.ID)
+	}
+
+	service.reuploadSpooled(entries[0], &fakeBucket{failing: []string{"b.jpg"}})
+	entries, _ = spool.Pending()
+	if len(entries) != 1 || entries[0].Attempts != 1 || entries[0].LastError == "" {
+		t.Fatalf("entries after failed re-upload = %+v, want one entry with 1 attempt", entries)
+	}
+
+	service.reuploadSpooled(entries[0], &fakeBucket{})
+	if entries, _ := spool.Pending(); len(entries) != 0 {
+		t.Errorf("entries after re-upload = %+v, want none", entries)
+	}
+	saved, err := repository.FindByID(record.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if saved.SourceURLs[0] != "/storage/images/a.jpg" || !strings.HasPrefix(saved.SourceURLs[1], "/storage/images/") || !strings.HasSuffix(saved.SourceURLs[1], "_b.jpg") {
+		t.Errorf("source URLs = %v", saved.SourceURLs)
+	}
+	if saved.Data.ImageStatus != models.ImageStatusStored {
+		t.Errorf("image status = %q, want %q", saved.Data.ImageStatus, models.ImageStatusStored)
+	}
+}
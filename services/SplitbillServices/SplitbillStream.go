@@ -0,0 +1,67 @@
+package splitbillservices
+
+import (
+	"context"
+	"sync"
+
+	extractors "github.com/arifin2018/splitbill-arifin.git/helpers/Extractors"
+	limits "github.com/arifin2018/splitbill-arifin.git/helpers/Limits"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// progressFunc menerima setiap event progress ekstraksi. Bisa dipanggil dari goroutine upload dan
+// extractor secara bersamaan.
+type progressFunc func(event models.ProgressEvent)
+
+// StreamSplitbil membaca file yang diunggah selama request, lalu mengembalikan fungsi yang
+// menjalankan ekstraksi dan mengirim setiap event progress ke emit, diakhiri event "done" atau
+// "error". Fungsi tersebut dijalankan oleh stream SSE setelah header respons dikirim; ctx dibatalkan
+// stream saat client memutus koneksi dan diberi deadline EXTRACTION_TIMEOUT sejak request diterima.
+func (splitbilSeviceImpl *SplibillServiceImpl) StreamSplitbil(app *fiber.Ctx) (func(ctx context.Context, emit func(event models.ProgressEvent)), error) {
+	target, err := splitbilSeviceImpl.webhookTarget(app)
+	if err != nil {
+		return nil, err
+	}
+	uploads, err := splitbilSeviceImpl.readUploads(app)
+	if err != nil {
+		return nil, err
+	}
+	deadline := limits.RequestDeadline(app)
+	return func(ctx context.Context, emit func(event models.ProgressEvent)) {
+		ctx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+		var emitLock sync.Mutex
+		report := func(event models.ProgressEvent) {
+			emitLock.Lock()
+			defer emitLock.Unlock()
+			emit(event)
+		}
+		result, err := splitbilSeviceImpl.extractUploads(ctx, uploads, report)
+		splitbilSeviceImpl.notifyExtraction(target, "", result, err)
+		if err != nil {
+			report(models.ProgressEvent{Event: models.ProgressEventError, Error: jobError(err)})
+			return
+		}
+		report(models.ProgressEvent{Event: models.ProgressEventDone, Progress: 100, Result: &result})
+	}, nil
+}
+
+// extractionTrace meneruskan event extractor ke report. Item diberi nomor urut mulai dari 1.
+func extractionTrace(report progressFunc) *extractors.Trace {
+	items := 0
+	return &extractors.Trace{
+		ModelStarted: func() {
+			report(models.ProgressEvent{Event: models.ProgressEventModelStarted, Progress: 40})
+		},
+		Item: func(item models.Item) {
+			items++
+			report(models.ProgressEvent{Event: models.ProgressEventItem, Index: items, Item: &item})
+		},
+	}
+}
+
+// reportValidated melaporkan hasil validasi receipt yang sudah disimpan
+func reportValidated(report progressFunc, saved models.SplitbillResponse) {
+	report(models.ProgressEvent{Event: models.ProgressEventValidated, Progress: 90, Validation: saved.Validation})
+}
@@ -0,0 +1,30 @@
+package splitbillservices
+
+import (
+	webhooks "github.com/arifin2018/splitbill-arifin.git/helpers/Webhooks"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// webhookTarget membaca callback_url (query atau form) dan X-API-Key request. Hasilnya nil jika
+// request tidak meminta callback.
+func (splitbilSeviceImpl *SplibillServiceImpl) webhookTarget(app *fiber.Ctx) (*models.WebhookTarget, error) {
+	if splitbilSeviceImpl.Webhooks == nil {
+		return nil, nil
+	}
+	callbackURL := app.Query("callback_url", app.FormValue("callback_url"))
+	return splitbilSeviceImpl.Webhooks.Target(app.Get(webhooks.HeaderAPIKey), callbackURL)
+}
+
+// notifyExtraction mengirim hasil ekstraksi ke callback URL: receipt untuk ekstraksi yang berhasil,
+// error dengan status dan code yang sama seperti respons sinkron untuk yang gagal
+func (splitbilSeviceImpl *SplibillServiceImpl) notifyExtraction(target *models.WebhookTarget, jobID string, result models.SplitbillResponse, err error) {
+	if target == nil || splitbilSeviceImpl.Webhooks == nil {
+		return
+	}
+	event := models.WebhookEvent{Type: models.WebhookEventExtractionSucceeded, JobID: jobID, ReceiptID: result.ReceiptID, Receipt: &result}
+	if err != nil {
+		event = models.WebhookEvent{Type: models.WebhookEventExtractionFailed, JobID: jobID, Error: jobError(err)}
+	}
+	splitbilSeviceImpl.Webhooks.Notify(*target, event)
+}
@@ -0,0 +1,47 @@
+package webhookservices
+
+import (
+	"context"
+	"sync"
+
+	jobs "github.com/arifin2018/splitbill-arifin.git/helpers/Jobs"
+	webhooks "github.com/arifin2018/splitbill-arifin.git/helpers/Webhooks"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	webhookrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/WebhookRepositories"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookService interface {
+	Register(app *fiber.Ctx) (models.WebhookRegistration, error)
+	GetRegistration(app *fiber.Ctx) (models.WebhookRegistration, error)
+	DeleteRegistration(app *fiber.Ctx) error
+	ListDeliveries(app *fiber.Ctx) ([]models.WebhookDelivery, error)
+	GetDelivery(app *fiber.Ctx) (models.WebhookDelivery, error)
+	Redeliver(app *fiber.Ctx) (models.WebhookDelivery, error)
+}
+
+// WebhookNotifier dipakai service ekstraksi untuk menentukan callback URL sebuah request dan
+// mengirim hasil ekstraksi setelah selesai
+type WebhookNotifier interface {
+	Target(apiKey string, callbackURL string) (*models.WebhookTarget, error)
+	Notify(target models.WebhookTarget, event models.WebhookEvent)
+}
+
+type WebhookServiceImpl struct {
+	WebhookRepository webhookrepositories.WebhookRepository
+	Sender            webhooks.SenderInterface
+	inFlight          sync.Map
+}
+
+func NewWebhookServiceImpl(webhookRepository webhookrepositories.WebhookRepository, sender webhooks.SenderInterface) *WebhookServiceImpl {
+	webhookServiceImpl := &WebhookServiceImpl{
+		WebhookRepository: webhookRepository,
+		Sender:            sender,
+	}
+	// Delivery yang gagal dicoba ulang di background sampai jobs.Shutdown, termasuk yang tertunda
+	// sebelum restart
+	jobs.Go(func(ctx context.Context) {
+		webhookServiceImpl.retryDeliveries(ctx, retryInterval())
+	})
+	return webhookServiceImpl
+}
@@ -0,0 +1,359 @@
+package webhookservices
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	webhooks "github.com/arifin2018/splitbill-arifin.git/helpers/Webhooks"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Register mendaftarkan atau mengganti callback URL untuk API key di header X-API-Key. Signing
+// secret dibuat otomatis untuk registrasi baru yang tidak mengirim secret. Secret hanya dikembalikan
+// saat dibuat atau diganti; mengganti URL tanpa secret tidak mengembalikan secret yang sudah ada.
+func (webhookServiceImpl *WebhookServiceImpl) Register(app *fiber.Ctx) (models.WebhookRegistration, error) {
+	keyID, err := apiKeyID(app)
+	if err != nil {
+		return models.WebhookRegistration{}, err
+	}
+	var request models.WebhookRegistrationRequest
+	if err := app.BodyParser(&request); err != nil {
+		return models.WebhookRegistration{}, errors.New(fmt.Sprintf("Error parsing request: %v", err.Error()))
+	}
+	request.URL = strings.TrimSpace(request.URL)
+	if err := webhooks.ValidateURL(request.URL); err != nil {
+		return models.WebhookRegistration{}, err
+	}
+
+	registration, err := webhookServiceImpl.WebhookRepository.FindRegistration(keyID)
+	if err != nil && !errors.Is(err, documents.ErrDocumentNotFound) {
+		return models.WebhookRegistration{}, errors.New(fmt.Sprintf("Error retrieving webhook: %v", err.Error()))
+	}
+	registration.ID = keyID
+	registration.URL = request.URL
+	secretChanged := request.Secret != "" || registration.Secret == ""
+	if request.Secret != "" {
+		registration.Secret = request.Secret
+	}
+	if registration.Secret == "" {
+		if registration.Secret, err = webhooks.NewSecret(); err != nil {
+			return models.WebhookRegistration{}, errors.New(fmt.Sprintf("Error generating webhook secret: %v", err.Error()))
+		}
+	}
+	if err := webhookServiceImpl.WebhookRepository.SaveRegistration(&registration); err != nil {
+		return models.WebhookRegistration{}, errors.New(fmt.Sprintf("Error saving webhook: %v", err.Error()))
+	}
+	if !secretChanged {
+		registration.Secret = ""
+	}
+	return registration, nil
+}
+
+// GetRegistration mengembalikan callback URL API key tanpa signing secret-nya
+func (webhookServiceImpl *WebhookServiceImpl) GetRegistration(app *fiber.Ctx) (models.WebhookRegistration, error) {
+	keyID, err := apiKeyID(app)
+	if err != nil {
+		return models.WebhookRegistration{}, err
+	}
+	registration, err := webhookServiceImpl.WebhookRepository.FindRegistration(keyID)
+	if err != nil {
+		return models.WebhookRegistration{}, errors.New(fmt.Sprintf("Error retrieving webhook: %v", err.Error()))
+	}
+	registration.Secret = ""
+	return registration, nil
+}
+
+func (webhookServiceImpl *WebhookServiceImpl) DeleteRegistration(app *fiber.Ctx) error {
+	keyID, err := apiKeyID(app)
+	if err != nil {
+		return err
+	}
+	if err := webhookServiceImpl.WebhookRepository.DeleteRegistration(keyID); err != nil {
+		return errors.New(fmt.Sprintf("Error deleting webhook: %v", err.Error()))
+	}
+	return nil
+}
+
+// ListDeliveries mengembalikan log delivery milik API key di header X-API-Key, terbaru lebih dulu;
+// query status, job_id dan receipt_id menyaring hasilnya.
+func (webhookServiceImpl *WebhookServiceImpl) ListDeliveries(app *fiber.Ctx) ([]models.WebhookDelivery, error) {
+	keyID, err := apiKeyID(app)
+	if err != nil {
+		return nil, err
+	}
+	status := strings.ToLower(strings.TrimSpace(app.Query("status")))
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown delivery status %q", status))
+	}
+
+	deliveries, err := webhookServiceImpl.WebhookRepository.FindDeliveries()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error retrieving webhook deliveries: %v", err.Error()))
+	}
+	filtered := []models.WebhookDelivery{}
+	for _, delivery := range deliveries {
+		if status != "" && delivery.Status != status {
+			continue
+		}
+		if delivery.Target.APIKeyID != keyID {
+			continue
+		}
+		if jobID := app.Query("job_id"); jobID != "" && delivery.JobID != jobID {
+			continue
+		}
+		if receiptID := app.Query("receipt_id"); receiptID != "" && delivery.ReceiptID != receiptID {
+			continue
+		}
+		filtered = append(filtered, delivery)
+	}
+	return filtered, nil
+}
+
+func (webhookServiceImpl *WebhookServiceImpl) GetDelivery(app *fiber.Ctx) (models.WebhookDelivery, error) {
+	return webhookServiceImpl.findOwnDelivery(app)
+}
+
+// findOwnDelivery mengambil delivery dari parameter id yang dimiliki API key di header X-API-Key.
+// Delivery milik API key lain dilaporkan sama seperti delivery yang tidak ada (404).
+func (webhookServiceImpl *WebhookServiceImpl) findOwnDelivery(app *fiber.Ctx) (models.WebhookDelivery, error) {
+	keyID, err := apiKeyID(app)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	delivery, err := webhookServiceImpl.WebhookRepository.FindDelivery(app.Params("id"))
+	if err != nil && !errors.Is(err, documents.ErrDocumentNotFound) {
+		return models.WebhookDelivery{}, errors.New(fmt.Sprintf("Error retrieving webhook delivery: %v", err.Error()))
+	}
+	if err != nil || delivery.Target.APIKeyID != keyID {
+		return models.WebhookDelivery{}, helpers.NewApiError(fiber.StatusNotFound, helpers.ErrCodeDeliveryNotFound,
+			fmt.Sprintf("webhook delivery %s not found", app.Params("id")), nil)
+	}
+	return delivery, nil
+}
+
+// Redeliver mengirim ulang delivery, baik yang sudah terkirim maupun yang gagal, sekali secara
+// langsung. Jika percobaan ini gagal, delivery dicoba ulang dengan backoff seperti delivery baru.
+func (webhookServiceImpl *WebhookServiceImpl) Redeliver(app *fiber.Ctx) (models.WebhookDelivery, error) {
+	delivery, err := webhookServiceImpl.findOwnDelivery(app)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	if _, sending := webhookServiceImpl.inFlight.Load(delivery.ID); sending {
+		return models.WebhookDelivery{}, errors.New("delivery is being sent, please retry later")
+	}
+	now := time.Now()
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = &now
+	delivery.MaxAttempts = len(delivery.Attempts) + maxAttempts()
+	if err := webhookServiceImpl.WebhookRepository.SaveDelivery(&delivery); err != nil {
+		return models.WebhookDelivery{}, errors.New(fmt.Sprintf("Error saving webhook delivery: %v", err.Error()))
+	}
+	webhookServiceImpl.deliver(delivery.ID)
+	return webhookServiceImpl.GetDelivery(app)
+}
+
+// Target menentukan ke mana hasil ekstraksi sebuah request dikirim. callback_url request lebih
+// diutamakan daripada callback URL yang didaftarkan untuk API key. Delivery ditandatangani dengan
+// secret registrasi API key, atau WEBHOOK_SECRET untuk request tanpa registrasi. Request tanpa
+// callback menghasilkan nil.
+func (webhookServiceImpl *WebhookServiceImpl) Target(apiKey string, callbackURL string) (*models.WebhookTarget, error) {
+	var registration *models.WebhookRegistration
+	if apiKey = strings.TrimSpace(apiKey); apiKey != "" && webhooks.AllowedKey(apiKey) {
+		found, err := webhookServiceImpl.WebhookRepository.FindRegistration(webhooks.KeyID(apiKey))
+		if err != nil && !errors.Is(err, documents.ErrDocumentNotFound) {
+			return nil, errors.New(fmt.Sprintf("Error retrieving webhook: %v", err.Error()))
+		}
+		if err == nil {
+			registration = &found
+		}
+	}
+
+	if callbackURL = strings.TrimSpace(callbackURL); callbackURL != "" {
+		if err := webhooks.ValidateURL(callbackURL); err != nil {
+			return nil, err
+		}
+		if registration != nil {
+			return &models.WebhookTarget{URL: callbackURL, APIKeyID: registration.ID}, nil
+		}
+		if os.Getenv("WEBHOOK_SECRET") == "" {
+			return nil, errors.New("callback_url requires WEBHOOK_SECRET or a webhook registered for the X-API-Key with PUT /webhooks")
+		}
+		return &models.WebhookTarget{URL: callbackURL}, nil
+	}
+	if registration != nil {
+		return &models.WebhookTarget{URL: registration.URL, APIKeyID: registration.ID}, nil
+	}
+	return nil, nil
+}
+
+// Notify menyimpan delivery baru lalu mengirimnya di background
+func (webhookServiceImpl *WebhookServiceImpl) Notify(target models.WebhookTarget, event models.WebhookEvent) {
+	now := time.Now()
+	// Event ID sama dengan delivery ID sehingga penerima bisa mengabaikan delivery yang berulang
+	event.ID = uuid.NewString()
+	event.CreatedAt = now
+	delivery := models.WebhookDelivery{
+		ID:            event.ID,
+		Event:         event.Type,
+		Target:        target,
+		JobID:         event.JobID,
+		ReceiptID:     event.ReceiptID,
+		Payload:       event,
+		Status:        models.WebhookDeliveryPending,
+		Attempts:      []models.WebhookAttempt{},
+		NextAttemptAt: &now,
+		MaxAttempts:   maxAttempts(),
+	}
+	if err := webhookServiceImpl.WebhookRepository.SaveDelivery(&delivery); err != nil {
+		config.GeneralLogger.Printf("Failed to save webhook delivery %s: %v\n", delivery.ID, err.Error())
+		return
+	}
+	go webhookServiceImpl.deliver(delivery.ID)
+}
+
+// deliver menjalankan satu percobaan pengiriman lalu menjadwalkan percobaan berikutnya dengan
+// backoff eksponensial. Setelah MaxAttempts percobaan gagal, delivery ditandai failed.
+func (webhookServiceImpl *WebhookServiceImpl) deliver(id string) {
+	if _, sending := webhookServiceImpl.inFlight.LoadOrStore(id, struct{}{}); sending {
+		return
+	}
+	defer webhookServiceImpl.inFlight.Delete(id)
+
+	delivery, err := webhookServiceImpl.WebhookRepository.FindDelivery(id)
+	if err != nil {
+		config.GeneralLogger.Printf("Webhook delivery %s not found: %v\n", id, err.Error())
+		return
+	}
+	if delivery.Status != models.WebhookDeliveryPending {
+		return
+	}
+
+	started := time.Now()
+	attempt := models.WebhookAttempt{At: started}
+	secret, err := webhookServiceImpl.secret(delivery.Target)
+	if err == nil {
+		var body []byte
+		body, err = json.Marshal(delivery.Payload)
+		if err == nil {
+			attempt.StatusCode, err = webhookServiceImpl.Sender.Send(context.Background(), delivery.Target.URL, secret, delivery.ID, delivery.Event, body)
+		}
+	}
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case len(delivery.Attempts) >= delivery.MaxAttempts:
+		delivery.Attempts[len(delivery.Attempts)-1].Error = err.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		config.GeneralLogger.Printf("Webhook delivery %s to %s failed after %d attempt(s): %v\n", delivery.ID, delivery.Target.URL, len(delivery.Attempts), err.Error())
+	default:
+		delivery.Attempts[len(delivery.Attempts)-1].Error = err.Error()
+		next := now.Add(retryBackoff(len(delivery.Attempts)))
+		delivery.NextAttemptAt = &next
+	}
+	if err := webhookServiceImpl.WebhookRepository.SaveDelivery(&delivery); err != nil {
+		config.GeneralLogger.Printf("Failed to save webhook delivery %s: %v\n", delivery.ID, err.Error())
+	}
+}
+
+// secret mengambil signing secret registrasi API key, atau WEBHOOK_SECRET jika target tidak
+// terhubung ke registrasi. Registrasi yang sudah dihapus membuat percobaan gagal.
+func (webhookServiceImpl *WebhookServiceImpl) secret(target models.WebhookTarget) (string, error) {
+	if target.APIKeyID == "" {
+		if secret := os.Getenv("WEBHOOK_SECRET"); secret != "" {
+			return secret, nil
+		}
+		return "", errors.New("WEBHOOK_SECRET is not configured")
+	}
+	registration, err := webhookServiceImpl.WebhookRepository.FindRegistration(target.APIKeyID)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("webhook registration not found: %v", err.Error()))
+	}
+	return registration.Secret, nil
+}
+
+// retryDeliveries mengirim delivery pending yang jadwalnya sudah lewat setiap interval sampai ctx
+// dibatalkan
+func (webhookServiceImpl *WebhookServiceImpl) retryDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		due, err := webhookServiceImpl.WebhookRepository.FindDueDeliveries(time.Now())
+		if err != nil {
+			config.GeneralLogger.Printf("Failed to load pending webhook deliveries: %v\n", err.Error())
+			continue
+		}
+		for _, delivery := range due {
+			webhookServiceImpl.deliver(delivery.ID)
+		}
+	}
+}
+
+func apiKeyID(app *fiber.Ctx) (string, error) {
+	apiKey := strings.TrimSpace(app.Get(webhooks.HeaderAPIKey))
+	if apiKey == "" {
+		return "", helpers.NewApiError(fiber.StatusUnauthorized, helpers.ErrCodeAPIKeyRequired, "the X-API-Key header is required", nil)
+	}
+	if !webhooks.AllowedKey(apiKey) {
+		return "", helpers.NewApiError(fiber.StatusUnauthorized, helpers.ErrCodeAPIKeyInvalid, "the X-API-Key is not in WEBHOOK_API_KEYS", nil)
+	}
+	return webhooks.KeyID(apiKey), nil
+}
+
+// retryBackoff menghitung jeda sebelum percobaan berikutnya: WEBHOOK_RETRY_BASE (default 30s)
+// dikali dua untuk setiap percobaan yang gagal, paling lama WEBHOOK_RETRY_MAX (default 1h)
+func retryBackoff(failedAttempts int) time.Duration {
+	base, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_BASE"))
+	if err != nil || base <= 0 {
+		base = 30 * time.Second
+	}
+	limit, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_MAX"))
+	if err != nil || limit <= 0 {
+		limit = time.Hour
+	}
+	backoff := base
+	for i := 1; i < failedAttempts && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, limit)
+}
+
+func maxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		return 6
+	}
+	return attempts
+}
+
+func retryInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return 10 * time.Second
+	}
+	return interval
+}
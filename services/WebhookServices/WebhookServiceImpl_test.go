@@ -0,0 +1,111 @@
+package webhookservices
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arifin2018/splitbill-arifin.git/config"
+	"github.com/arifin2018/splitbill-arifin.git/helpers"
+	documents "github.com/arifin2018/splitbill-arifin.git/helpers/Documents"
+	"github.com/arifin2018/splitbill-arifin.git/models"
+	webhookrepositories "github.com/arifin2018/splitbill-arifin.git/repositories/WebhookRepositories"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// register mengirim PUT /webhooks dengan apiKey dan body lalu mengembalikan hasil Register
+func register(t *testing.T, service *WebhookServiceImpl, apiKey string, body string) (models.WebhookRegistration, error) {
+	t.Helper()
+	var registration models.WebhookRegistration
+	var registerErr error
+	app := fiber.New()
+	app.Put("/webhooks", func(c *fiber.Ctx) error {
+		registration, registerErr = service.Register(c)
+		return nil
+	})
+	request := httptest.NewRequest(fiber.MethodPut, "/webhooks", strings.NewReader(body))
+	request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	request.Header.Set("X-API-Key", apiKey)
+	if _, err := app.Test(request); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	return registration, registerErr
+}
+
+func registrationBody(url string, secret string) string {
+	body, _ := json.Marshal(models.WebhookRegistrationRequest{URL: url, Secret: secret})
+	return string(body)
+}
+
+func TestRegisterSecret(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+	service := &WebhookServiceImpl{WebhookRepository: webhookrepositories.NewWebhookRepositoryImpl(documents.NewFile(t.TempDir()))}
+
+	created, err := register(t, service, "key-a", registrationBody("https://93.184.216.34/first", ""))
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") {
+		t.Fatalf("secret of a new registration = %q, want a generated secret", created.Secret)
+	}
+
+	updated, err := register(t, service, "key-a", registrationBody("https://93.184.216.34/second", ""))
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if updated.Secret != "" {
+		t.Errorf("secret after changing only the URL = %q, want none", updated.Secret)
+	}
+	if updated.URL != "https://93.184.216.34/second" {
+		t.Errorf("url = %q, want the new URL", updated.URL)
+	}
+	stored, err := service.WebhookRepository.FindRegistration(updated.ID)
+	if err != nil || stored.Secret != created.Secret {
+		t.Errorf("stored secret = %q (%v), want the existing secret kept", stored.Secret, err)
+	}
+
+	rotated, err := register(t, service, "key-a", registrationBody("https://93.184.216.34/second", "whsec_rotated"))
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if rotated.Secret != "whsec_rotated" {
+		t.Errorf("secret after rotating = %q, want whsec_rotated", rotated.Secret)
+	}
+}
+
+func TestRegisterAPIKeys(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	config.GeneralLogger = logger
+	service := &WebhookServiceImpl{WebhookRepository: webhookrepositories.NewWebhookRepositoryImpl(documents.NewFile(t.TempDir()))}
+	t.Setenv("WEBHOOK_API_KEYS", "key-a,key-b")
+
+	tests := []struct {
+		name   string
+		apiKey string
+		code   string
+	}{
+		{name: "listed key", apiKey: "key-b"},
+		{name: "unknown key", apiKey: "key-c", code: helpers.ErrCodeAPIKeyInvalid},
+		{name: "missing key", apiKey: "", code: helpers.ErrCodeAPIKeyRequired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := register(t, service, test.apiKey, registrationBody("https://93.184.216.34/callback", ""))
+			var apiError *helpers.ApiError
+			switch {
+			case test.code == "" && err != nil:
+				t.Errorf("Register() error = %v", err)
+			case test.code != "" && (!errors.As(err, &apiError) || apiError.Code != test.code || apiError.Status != fiber.StatusUnauthorized):
+				t.Errorf("Register() error = %v, want 401 %s", err, test.code)
+			}
+		})
+	}
+}